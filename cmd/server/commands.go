@@ -0,0 +1,375 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/paulgreig/guitar-training/internal/audio"
+	"github.com/paulgreig/guitar-training/internal/config"
+	"github.com/paulgreig/guitar-training/internal/exercise"
+	"github.com/paulgreig/guitar-training/internal/fretboard"
+	"github.com/paulgreig/guitar-training/internal/midi"
+	"github.com/paulgreig/guitar-training/internal/pitch"
+	"github.com/paulgreig/guitar-training/internal/score"
+	"github.com/paulgreig/guitar-training/internal/theory"
+	"github.com/paulgreig/guitar-training/internal/tui"
+)
+
+// runCommand runs a command-line subcommand instead of the TUI and returns
+// the process exit code. cfg supplies the defaults for its options.
+func runCommand(args []string, cfg *config.Config, stdout, stderr io.Writer) int {
+	switch args[0] {
+	case "midi":
+		return runMIDI(args[1:], cfg, stdout, stderr)
+	case "pitch":
+		return runPitch(args[1:], cfg, stdout, stderr)
+	case "import":
+		return runImport(args[1:], cfg, stdout, stderr)
+	case "lint":
+		return runLint(args[1:], stdout, stderr)
+	case "help":
+		usage(stdout)
+		return 0
+	default:
+		fmt.Fprintf(stderr, "unknown command %q\n\n", args[0])
+		usage(stderr)
+		return 2
+	}
+}
+
+func usage(w io.Writer) {
+	fmt.Fprint(w, `Usage:
+  guitar-training [--config FILE]      start the TUI
+  guitar-training midi scale ROOT TYPE export a scale run as a MIDI file
+  guitar-training midi chords CHORD... export a chord progression as a MIDI file
+  guitar-training pitch FILE.wav       list the notes detected in a recording
+  guitar-training import FILE          add a MusicXML or Guitar Pro track as a lesson
+  guitar-training lint DIR...          check content directories for mistakes
+
+Settings are read from FILE, or $XDG_CONFIG_HOME/guitar-training/config.toml
+if it exists, and then from environment variables.
+
+Run "guitar-training midi -h" or "guitar-training import -h" for options.
+`)
+}
+
+// runMIDI exports a scale run or chord progression as a Standard MIDI File.
+func runMIDI(args []string, cfg *config.Config, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("midi", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	out := fs.String("o", "", "output file (default: named after the export, e.g. c-major-ascending.mid)")
+	bpm := fs.Int("bpm", 80, "tempo in beats per minute")
+	format := fs.Int("format", 1, "MIDI file format: 0 (single track) or 1 (one track per string)")
+	tuningName := fs.String("tuning", cfg.Tuning, "tuning ID or name")
+	patternName := fs.String("pattern", exercise.Patterns[0].Name, "scale run pattern")
+	shape := fs.Int("shape", 1, "scale shape to walk (1 = first; 0 = every note up to the 12th fret)")
+	beats := fs.Int("beats", 4, "beats per chord")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: guitar-training midi [options] scale ROOT TYPE | chords CHORD...")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	rest := fs.Args()
+	if len(rest) < 2 {
+		fs.Usage()
+		return 2
+	}
+	tuning, ok := fretboard.LookupTuning(*tuningName)
+	if !ok {
+		fmt.Fprintf(stderr, "unknown tuning %q\n", *tuningName)
+		return 2
+	}
+
+	var seq midi.Sequence
+	switch rest[0] {
+	case "scale":
+		pattern, ok := exercise.LookupPattern(*patternName)
+		if !ok {
+			fmt.Fprintf(stderr, "unknown pattern %q\n", *patternName)
+			return 2
+		}
+		name, notes, err := theory.BuildScale(rest[1], strings.Join(rest[2:], " "))
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		pcs := make([]theory.PitchClass, len(notes))
+		for i, n := range notes {
+			pcs[i] = n.PitchClass()
+		}
+		locs := tuning.Find(pcs, fretboard.DefaultFrets)
+		if shapes := tuning.Shapes(pcs); *shape > 0 {
+			if *shape > len(shapes) {
+				fmt.Fprintf(stderr, "%s has %d shapes\n", name, len(shapes))
+				return 1
+			}
+			locs = shapes[*shape-1].Locations
+		}
+		seq = midi.FromRun(name+" "+pattern.Name, exercise.Run(tuning, locs, pattern), *bpm)
+	case "chords":
+		var voicings []fretboard.Voicing
+		for _, sym := range rest[1:] {
+			root, quality, err := theory.ParseChordSymbol(sym)
+			if err != nil {
+				fmt.Fprintln(stderr, err)
+				return 1
+			}
+			var pcs []theory.PitchClass
+			for _, n := range quality.Notes(root) {
+				pcs = append(pcs, n.PitchClass())
+			}
+			v := tuning.Voicings(pcs, 1)
+			if len(v) == 0 {
+				fmt.Fprintf(stderr, "no playable voicing for %s in %s tuning\n", sym, tuning.Name)
+				return 1
+			}
+			voicings = append(voicings, v[0])
+		}
+		seq = midi.Progression(strings.Join(rest[1:], " "), tuning, voicings, *bpm, float64(*beats))
+	default:
+		fs.Usage()
+		return 2
+	}
+
+	f, err := seq.File(*format)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	path := *out
+	if path == "" {
+		path = tui.FileName(seq.Name, ".mid")
+	}
+	if err := f.Save(path); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "wrote %s (%d notes, %d BPM)\n", path, len(seq.Notes), seq.BPM)
+	return 0
+}
+
+// runPitch prints the notes detected in a WAV recording, one line each time
+// a new note is held for at least two frames, which skips the mixed frames
+// between notes.
+func runPitch(args []string, cfg *config.Config, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("pitch", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	a4 := fs.Float64("a4", cfg.A4, "reference pitch of A4 in Hz")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: guitar-training pitch [options] FILE.wav")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	src, err := audio.OpenWAVSource(fs.Arg(0), false)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	t := pitch.NewTracker(src, *a4)
+	rate := float64(src.SampleRate())
+	var prev pitch.Estimate
+	held, reported := 0, false
+	for i := 1; ; i++ {
+		est, ok, err := t.Next()
+		if err != nil {
+			break
+		}
+		switch {
+		case !ok:
+			held, reported = 0, false
+			continue
+		case held > 0 && est.Pitch == prev.Pitch:
+			held++
+		default:
+			held, reported = 1, false
+		}
+		prev = est
+		if held >= 2 && !reported {
+			// Report the start of the window in which the note was first heard.
+			at := float64((i-1)*pitch.DefaultHop-pitch.FrameSize(src.SampleRate())) / rate
+			fmt.Fprintf(stdout, "%7.2fs  %-4s %8.2f Hz  %+5.1f cents\n", math.Max(at, 0), est.Pitch, est.Freq, est.Cents)
+			reported = true
+		}
+	}
+	return 0
+}
+
+// runImport reads a MusicXML or Guitar Pro file and adds one of its tracks,
+// as tab, to the lessons in the data directory.
+func runImport(args []string, cfg *config.Config, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	list := fs.Bool("list", false, "list the file's tracks and exit")
+	track := fs.Int("track", 1, "track to import (1 = first; see -list)")
+	id := fs.String("id", "", "lesson ID (default: the next lesson-NNN)")
+	title := fs.String("title", "", "lesson title (default: the song title and track name)")
+	level := fs.String("level", "intermediate", "lesson level")
+	dataDir := fs.String("data", lastDir(cfg.DataPaths), "content directory to add the lesson to (default: the last data_path)")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: guitar-training import [options] FILE.musicxml|.mxl|.gp3|.gp4|.gp5")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	path := fs.Arg(0)
+	if *dataDir == "" && !*list {
+		fmt.Fprintln(stderr, "no content directory to add the lesson to: pass -data DIR or set data_path")
+		return 2
+	}
+	s, err := score.Import(path)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	if *list {
+		for i, t := range s.Tracks {
+			fmt.Fprintf(stdout, "%2d  %-24s %-20s %d bars, %d notes\n", i+1, t.Name, t.Tuning().Name, len(t.Bars), t.Notes())
+		}
+		return 0
+	}
+	if *track < 1 || *track > len(s.Tracks) {
+		fmt.Fprintf(stderr, "%s has %d tracks\n", path, len(s.Tracks))
+		return 2
+	}
+	t := s.Tracks[*track-1]
+
+	song := s.Title
+	if song == "" {
+		song = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	lesson := tui.Lesson{ID: *id, Title: *title, Level: *level}
+	if lesson.Title == "" {
+		lesson.Title = song
+		if t.Name != "" {
+			lesson.Title += " (" + t.Name + ")"
+		}
+	}
+	source := song
+	if s.Artist != "" {
+		source += " by " + s.Artist
+	}
+	lesson.Content = fmt.Sprintf("%s, imported from %s. Tuning: %s.\n\n```tab\n%s\n```",
+		source, filepath.Base(path), t.Tuning().Name, t.Tab())
+
+	// Number new lessons after every lesson the app will show, built-in
+	// ones included; files that don't load are reported by the TUI.
+	known, _ := tui.LoadLessons(append(cfg.DataPaths, *dataDir))
+	file := filepath.Join(*dataDir, "lessons.json")
+	if lesson, err = appendLesson(file, lesson, known); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "added %s %q to %s (%d bars, %d notes)\n", lesson.ID, lesson.Title, file, len(t.Bars), t.Notes())
+	return 0
+}
+
+// runLint checks content directories and lists every problem found. It
+// exits 1 if there are any, so it can run before commits.
+func runLint(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("lint", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: guitar-training lint DIR...")
+		fmt.Fprintln(stderr, "Checks scales.json, chords.json, lessons.json and lessons/*.md in each directory.")
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+	code := 0
+	for _, dir := range fs.Args() {
+		problems, err := tui.Lint(dir)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			code = 1
+			continue
+		}
+		for _, p := range problems {
+			fmt.Fprintln(stdout, p)
+		}
+		switch len(problems) {
+		case 0:
+		case 1:
+			fmt.Fprintf(stderr, "%s: 1 problem\n", dir)
+			code = 1
+		default:
+			fmt.Fprintf(stderr, "%s: %d problems\n", dir, len(problems))
+			code = 1
+		}
+	}
+	return code
+}
+
+// appendLesson adds l to the lessons file, giving it the next lesson-NNN ID
+// after those in the file and in known when it has none.
+func appendLesson(file string, l tui.Lesson, known []tui.Lesson) (tui.Lesson, error) {
+	var lessons []tui.Lesson
+	data, err := os.ReadFile(file)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, &lessons); err != nil {
+			return l, fmt.Errorf("could not parse lessons: %w", err)
+		}
+	case !os.IsNotExist(err):
+		return l, fmt.Errorf("could not read lessons file: %w", err)
+	}
+
+	next := 0
+	for _, existing := range append(known, lessons...) {
+		var n int
+		if _, err := fmt.Sscanf(existing.ID, "lesson-%d", &n); err == nil && n > next {
+			next = n
+		}
+	}
+	for _, existing := range lessons {
+		if l.ID != "" && existing.ID == l.ID {
+			return l, fmt.Errorf("lesson %s already exists in %s", l.ID, file)
+		}
+	}
+	if l.ID == "" {
+		l.ID = fmt.Sprintf("lesson-%03d", next+1)
+	}
+
+	var out bytes.Buffer
+	enc := json.NewEncoder(&out)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(append(lessons, l)); err != nil {
+		return l, fmt.Errorf("could not encode lessons: %w", err)
+	}
+	if err := os.WriteFile(file, out.Bytes(), 0o644); err != nil {
+		return l, fmt.Errorf("could not write lessons file: %w", err)
+	}
+	return l, nil
+}
+
+// lastDir returns the last of dirs, or "" if there are none.
+func lastDir(dirs []string) string {
+	if len(dirs) == 0 {
+		return ""
+	}
+	return dirs[len(dirs)-1]
+}
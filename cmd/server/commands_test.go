@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/paulgreig/guitar-training/internal/config"
+	"github.com/paulgreig/guitar-training/internal/tui"
+)
+
+func readLessons(t *testing.T, file string) []tui.Lesson {
+	t.Helper()
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var lessons []tui.Lesson
+	if err := json.Unmarshal(data, &lessons); err != nil {
+		t.Fatalf("%s: %v", file, err)
+	}
+	return lessons
+}
+
+func TestAppendLesson(t *testing.T) {
+	known := []tui.Lesson{{ID: "lesson-007"}, {ID: "intro"}}
+	tests := []struct {
+		name     string
+		existing string // lessons.json; "" for none
+		lesson   tui.Lesson
+		wantID   string
+		wantErr  string
+	}{
+		{"new file", "", tui.Lesson{Title: "Riff"}, "lesson-008", ""},
+		{"after the file", `[{"id":"lesson-012","title":"Old"}]`, tui.Lesson{Title: "Riff"}, "lesson-013", ""},
+		{"after known", `[{"id":"lesson-002","title":"Old"}]`, tui.Lesson{Title: "Riff"}, "lesson-008", ""},
+		{"given ID", `[{"id":"lesson-002","title":"Old"}]`, tui.Lesson{ID: "my-riff", Title: "Riff"}, "my-riff", ""},
+		{"ID taken", `[{"id":"my-riff","title":"Old"}]`, tui.Lesson{ID: "my-riff", Title: "Riff"}, "", "lesson my-riff already exists"},
+		{"bad JSON", `[{"id":`, tui.Lesson{Title: "Riff"}, "", "could not parse lessons"},
+	}
+	for _, tt := range tests {
+		file := filepath.Join(t.TempDir(), "lessons.json")
+		if tt.existing != "" {
+			os.WriteFile(file, []byte(tt.existing), 0o644)
+		}
+		l, err := appendLesson(file, tt.lesson, known)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: error %v, want %q", tt.name, err, tt.wantErr)
+			}
+			if data, _ := os.ReadFile(file); string(data) != tt.existing {
+				t.Errorf("%s: file changed to %s", tt.name, data)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		lessons := readLessons(t, file)
+		last := lessons[len(lessons)-1]
+		if l.ID != tt.wantID || last.ID != tt.wantID || last.Title != "Riff" {
+			t.Errorf("%s: added %q, file ends with %+v; want %q", tt.name, l.ID, last, tt.wantID)
+		}
+		if want := strings.Count(tt.existing, `"id"`) + 1; len(lessons) != want {
+			t.Errorf("%s: file has %d lessons, want %d", tt.name, len(lessons), want)
+		}
+	}
+}
+
+func TestRunImport(t *testing.T) {
+	builtin, err := tui.LoadLessons(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	next := 0
+	for _, l := range builtin {
+		var n int
+		if _, err := fmt.Sscanf(l.ID, "lesson-%d", &n); err == nil && n > next {
+			next = n
+		}
+	}
+	gp5 := filepath.Join("..", "..", "internal", "score", "testdata", "riff.gp5")
+	mxl := filepath.Join("..", "..", "internal", "score", "testdata", "riff.mxl")
+
+	dir := t.TempDir()
+	cfg := config.Default()
+	cfg.DataPaths = []string{dir}
+	var stdout, stderr bytes.Buffer
+	if code := runImport([]string{gp5}, cfg, &stdout, &stderr); code != 0 {
+		t.Fatalf("import exited %d: %s", code, stderr.String())
+	}
+	if code := runImport([]string{"-id", "riff", "-title", "My riff", "-level", "beginner", mxl}, cfg, &stdout, &stderr); code != 0 {
+		t.Fatalf("second import exited %d: %s", code, stderr.String())
+	}
+	lessons := readLessons(t, filepath.Join(dir, "lessons.json"))
+	if len(lessons) != 2 {
+		t.Fatalf("got %d lessons, want 2", len(lessons))
+	}
+	first, second := lessons[0], lessons[1]
+	if want := fmt.Sprintf("lesson-%03d", next+1); first.ID != want || first.Title != "Fixture Riff (Lead)" || first.Level != "intermediate" {
+		t.Errorf("first lesson is %s %q %s, want %s", first.ID, first.Title, first.Level, want)
+	}
+	for _, s := range []string{"Fixture Riff by The Testers, imported from riff.gp5. Tuning: Standard.", "```tab\n", "|-5-"} {
+		if !strings.Contains(first.Content, s) {
+			t.Errorf("first lesson doesn't contain %q:\n%s", s, first.Content)
+		}
+	}
+	if second.ID != "riff" || second.Title != "My riff" || second.Level != "beginner" || !strings.Contains(second.Content, "Tuning: Drop D.") {
+		t.Errorf("second lesson is %s %q %s:\n%s", second.ID, second.Title, second.Level, second.Content)
+	}
+	if out := stdout.String(); !strings.Contains(out, `added riff "My riff"`) {
+		t.Errorf("output is %q", out)
+	}
+	if _, err := tui.LoadLessons([]string{dir}); err != nil {
+		t.Errorf("imported lessons don't load: %v", err)
+	}
+}
+
+func TestRunImportErrors(t *testing.T) {
+	gp5 := filepath.Join("..", "..", "internal", "score", "testdata", "riff.gp5")
+	tests := []struct {
+		name     string
+		args     []string
+		dataPath bool
+		code     int
+		want     string
+	}{
+		{"no file", nil, true, 2, "Usage: guitar-training import"},
+		{"no data dir", []string{gp5}, false, 2, "no content directory"},
+		{"no such track", []string{"-track", "2", gp5}, true, 2, "has 1 tracks"},
+		{"unreadable", []string{"missing.gp5"}, true, 1, "could not open score"},
+		{"bad flag", []string{"-bogus", gp5}, true, 2, "flag provided but not defined"},
+	}
+	for _, tt := range tests {
+		cfg := config.Default()
+		cfg.DataPaths = nil
+		if tt.dataPath {
+			cfg.DataPaths = []string{t.TempDir()}
+		}
+		var stdout, stderr bytes.Buffer
+		code := runImport(tt.args, cfg, &stdout, &stderr)
+		if code != tt.code || !strings.Contains(stderr.String(), tt.want) {
+			t.Errorf("%s: exited %d with %q; want %d and %q", tt.name, code, stderr.String(), tt.code, tt.want)
+		}
+		if tt.dataPath {
+			if _, err := os.Stat(filepath.Join(cfg.DataPaths[0], "lessons.json")); !os.IsNotExist(err) {
+				t.Errorf("%s: wrote lessons.json", tt.name)
+			}
+		}
+	}
+
+	cfg := config.Default()
+	var stdout, stderr bytes.Buffer
+	if code := runImport([]string{"-list", gp5}, cfg, &stdout, &stderr); code != 0 || !strings.Contains(stdout.String(), "Lead") {
+		t.Errorf("-list exited %d with %q", code, stdout.String())
+	}
+}
@@ -1,23 +1,53 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
 	"os"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/paulgreig/guitar-training/internal/audio"
+	"github.com/paulgreig/guitar-training/internal/config"
+	"github.com/paulgreig/guitar-training/internal/metronome"
+	"github.com/paulgreig/guitar-training/internal/midi"
 	"github.com/paulgreig/guitar-training/internal/obs"
+	"github.com/paulgreig/guitar-training/internal/practice"
 	"github.com/paulgreig/guitar-training/internal/tui"
 )
 
 func main() {
+	flags := flag.NewFlagSet("guitar-training", flag.ContinueOnError)
+	configPath := flags.String("config", "", "config file (default: $XDG_CONFIG_HOME/guitar-training/config.toml)")
+	flags.Usage = func() { usage(os.Stderr) }
+	if err := flags.Parse(os.Args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(0)
+		}
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config:\n%v\n", err)
+		os.Exit(1)
+	}
+
+	if flags.NArg() > 0 {
+		os.Exit(runCommand(flags.Args(), cfg, os.Stdout, os.Stderr))
+	}
+
 	// Initialise logging and metrics.
-	obs.InitLogger()
+	obs.InitLogger(cfg.LogLevel, cfg.LogPath)
 	obs.RecordAppStart()
 	obs.Info("application starting")
+	if cfg.File != "" {
+		obs.Info("config loaded path=%s", cfg.File)
+	}
 
 	// Start Prometheus metrics server (CPU, RAM, GC + app metrics) for Grafana Cloud / Prometheus.
-	obs.StartMetricsServer()
+	obs.StartMetricsServer(cfg.MetricsPort)
 	defer obs.StopMetricsServer()
 
 	start := time.Now()
@@ -39,14 +69,44 @@ func main() {
 				"lessons_list_views":  m.LessonsListViews,
 				"scale_detail_views":  m.ScaleDetailViews,
 				"lesson_detail_views": m.LessonDetailViews,
+				"chords_list_views":   m.ChordsListViews,
+				"chord_detail_views":  m.ChordDetailViews,
 				"data_load_errors":    m.DataLoadErrors,
 				"data_load_successes": m.DataLoadSuccesses,
 			},
 		)
 	}()
 
+	clicker, err := metronome.NewClicker(cfg.Click)
+	if err != nil {
+		obs.Warn("metronome output %q unavailable, using bell: %v", cfg.Click, err)
+		clicker = metronome.Bell{Out: os.Stderr}
+	}
+
+	sink, err := audio.OpenSink(cfg.Audio)
+	if err != nil {
+		obs.Warn("audio output %q unavailable, synthesised audio will be silent: %v", cfg.Audio, err)
+		sink = audio.Discard{}
+	}
+	defer sink.Close()
+
+	midiIn, err := midi.OpenInput(cfg.MIDIIn)
+	if err != nil {
+		obs.Warn("midi input %q unavailable: %v", cfg.MIDIIn, err)
+	}
+
 	// Initialize the TUI application.
-	p := tea.NewProgram(tui.NewModel(), tea.WithAltScreen())
+	model := tui.NewModel(cfg).WithClicker(clicker).WithSink(sink)
+	if midiIn != nil {
+		defer midiIn.Close()
+		model = model.WithMIDIInput(midiIn)
+	}
+	if store, err := openPracticeStore(cfg.Practice); err != nil {
+		obs.Warn("practice log disabled: %v", err)
+	} else {
+		model = model.WithStore(store)
+	}
+	p := tea.NewProgram(model, tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
 		obs.Error("application error: %v", err)
 		fmt.Printf("Error running application: %v\n", err)
@@ -55,3 +115,19 @@ func main() {
 
 	obs.Info("application shutdown complete")
 }
+
+// openPracticeStore opens the practice log at path, or at the XDG default when path is empty.
+func openPracticeStore(path string) (*practice.Store, error) {
+	if path == "" {
+		var err error
+		if path, err = practice.DefaultPath(); err != nil {
+			return nil, err
+		}
+	}
+	store, err := practice.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	obs.Info("practice log path=%s sessions=%d", store.Path(), len(store.Sessions()))
+	return store, nil
+}
@@ -0,0 +1,11 @@
+// Package data holds the stock scales, lessons and chords, compiled into the
+// binary so it runs from any directory.
+package data
+
+import "embed"
+
+// FS holds scales.json, lessons.json, chords.json and the Markdown lessons
+// in lessons/.
+//
+//go:embed scales.json lessons.json chords.json lessons
+var FS embed.FS
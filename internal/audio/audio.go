@@ -0,0 +1,90 @@
+package audio
+
+import "math"
+
+// DefaultSampleRate is the sample rate used for generated audio.
+const DefaultSampleRate = 44100
+
+// Sink receives mono PCM audio as float64 samples in the range [-1, 1].
+// Implementations decide where the audio goes: a file, a sound device or nowhere.
+type Sink interface {
+	// Write queues samples for output. It may return before playback finishes.
+	Write(samples []float64) error
+	// SampleRate is the rate the sink expects samples at.
+	SampleRate() int
+	// Close flushes and releases the sink.
+	Close() error
+}
+
+// Discard is a Sink that drops everything. Useful when audio is disabled.
+type Discard struct {
+	Rate int
+}
+
+// Write implements Sink.
+func (d Discard) Write(samples []float64) error { return nil }
+
+// SampleRate implements Sink.
+func (d Discard) SampleRate() int {
+	if d.Rate == 0 {
+		return DefaultSampleRate
+	}
+	return d.Rate
+}
+
+// Close implements Sink.
+func (d Discard) Close() error { return nil }
+
+// Silence returns n seconds of silence at the given rate.
+func Silence(seconds float64, rate int) []float64 {
+	return make([]float64, int(seconds*float64(rate)))
+}
+
+// Tone returns a sine tone with a short linear fade in and out to avoid clicks.
+func Tone(freq, seconds, amplitude float64, rate int) []float64 {
+	n := int(seconds * float64(rate))
+	out := make([]float64, n)
+	fade := rate / 200 // 5ms
+	if fade*2 > n {
+		fade = n / 2
+	}
+	for i := range out {
+		env := 1.0
+		switch {
+		case i < fade:
+			env = float64(i) / float64(fade)
+		case i >= n-fade:
+			env = float64(n-1-i) / float64(fade)
+		}
+		out[i] = amplitude * env * math.Sin(2*math.Pi*freq*float64(i)/float64(rate))
+	}
+	return out
+}
+
+// Mix adds src into dst starting at offset, growing dst as needed, and returns it.
+func Mix(dst, src []float64, offset int) []float64 {
+	if need := offset + len(src); need > len(dst) {
+		dst = append(dst, make([]float64, need-len(dst))...)
+	}
+	for i, v := range src {
+		dst[offset+i] += v
+	}
+	return dst
+}
+
+// Normalize scales samples so the loudest peak reaches peak (e.g. 0.9).
+func Normalize(samples []float64, peak float64) {
+	max := 0.0
+	for _, v := range samples {
+		if a := math.Abs(v); a > max {
+			max = a
+		}
+	}
+	if max == 0 {
+		return
+	}
+	k := peak / max
+	for i := range samples {
+		samples[i] *= k
+	}
+}
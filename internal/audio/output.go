@@ -0,0 +1,54 @@
+package audio
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// OpenSink builds a Sink from an output description: "audio" (system audio
+// player), "wav:<path>" (write to a WAV file) or "none". On error the sink
+// is nil.
+func OpenSink(output string) (Sink, error) {
+	if err := CheckOutput(output); err != nil {
+		return nil, err
+	}
+	switch output {
+	case "none":
+		return Discard{}, nil
+	case "audio":
+		p, ok := FindPlayer(DefaultSampleRate)
+		if !ok {
+			return nil, fmt.Errorf("no audio player found on PATH")
+		}
+		return p, nil
+	}
+	// Return a nil interface, not a nil *WAVFile, on error.
+	w, err := CreateWAVFile(strings.TrimPrefix(output, "wav:"), DefaultSampleRate)
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// CheckOutput reports whether OpenSink accepts an output description,
+// without opening it. A WAV file's directory must exist; whether an audio
+// player is installed is only found out on opening.
+func CheckOutput(output string) error {
+	switch {
+	case output == "audio" || output == "none":
+		return nil
+	case strings.HasPrefix(output, "wav:"):
+		path := strings.TrimPrefix(output, "wav:")
+		if path == "" {
+			return fmt.Errorf("wav: output needs a file path")
+		}
+		if info, err := os.Stat(filepath.Dir(path)); err != nil || !info.IsDir() {
+			return fmt.Errorf("directory %s for %s does not exist", filepath.Dir(path), path)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown audio output %q (want audio, none or wav:<path>)", output)
+	}
+}
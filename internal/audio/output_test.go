@@ -0,0 +1,42 @@
+package audio
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// TestOpenSinkError checks a failed open returns a nil Sink, so callers can
+// test it against nil.
+func TestOpenSinkError(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "no-such-dir", "out.wav")
+	for _, output := range []string{"wav:" + missing, "bogus"} {
+		sink, err := OpenSink(output)
+		if err == nil {
+			t.Errorf("OpenSink(%q): no error", output)
+		}
+		if sink != nil {
+			t.Errorf("OpenSink(%q) = %#v, want nil", output, sink)
+		}
+	}
+}
+
+func TestCheckOutput(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		output  string
+		wantErr bool
+	}{
+		{"audio", false},
+		{"none", false},
+		{"wav:" + filepath.Join(dir, "out.wav"), false},
+		{"wav:", true},
+		{"wav:" + filepath.Join(dir, "missing", "out.wav"), true},
+		{"speakers", true},
+		{"", true},
+	}
+	for _, tt := range tests {
+		if err := CheckOutput(tt.output); (err != nil) != tt.wantErr {
+			t.Errorf("CheckOutput(%q) = %v, want error %v", tt.output, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package audio
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+)
+
+// playerCommands are external players that accept a WAV stream on stdin,
+// tried in order.
+var playerCommands = [][]string{
+	{"paplay"},
+	{"aplay", "-q"},
+	{"pw-play", "-"},
+	{"ffplay", "-nodisp", "-autoexit", "-loglevel", "quiet", "-"},
+}
+
+// Player is a Sink that plays audio through an external command-line player
+// found on PATH. Each Write is played asynchronously as its own clip.
+type Player struct {
+	args []string
+	rate int
+}
+
+// FindPlayer returns a Player for the first available system audio player,
+// or false when none is installed (e.g. on a headless machine).
+func FindPlayer(rate int) (*Player, bool) {
+	for _, args := range playerCommands {
+		if _, err := exec.LookPath(args[0]); err == nil {
+			return &Player{args: args, rate: rate}, true
+		}
+	}
+	return nil, false
+}
+
+// Write implements Sink. Playback errors after the player has started are ignored.
+func (p *Player) Write(samples []float64) error {
+	var buf bytes.Buffer
+	if err := WriteWAV(&buf, samples, p.rate); err != nil {
+		return err
+	}
+	cmd := exec.Command(p.args[0], p.args[1:]...)
+	cmd.Stdin = &buf
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("could not start %s: %w", p.args[0], err)
+	}
+	go func() { _ = cmd.Wait() }()
+	return nil
+}
+
+// SampleRate implements Sink.
+func (p *Player) SampleRate() int { return p.rate }
+
+// Close implements Sink.
+func (p *Player) Close() error { return nil }
+
+// Name returns the external command used for playback.
+func (p *Player) Name() string { return p.args[0] }
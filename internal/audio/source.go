@@ -0,0 +1,207 @@
+package audio
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Source supplies mono PCM audio as float64 samples in [-1, 1], such as a
+// microphone or a recording.
+type Source interface {
+	// Read fills buf with the next samples, blocking until they are
+	// available, and returns io.EOF when the source has ended.
+	Read(buf []float64) (int, error)
+	// SampleRate is the rate samples arrive at.
+	SampleRate() int
+	// Close stops the source.
+	Close() error
+}
+
+// SampleSource is a Source that plays back samples held in memory, e.g. a
+// WAV file. In real time mode reads are paced to the sample rate, as if the
+// audio were arriving live.
+type SampleSource struct {
+	samples  []float64
+	rate     int
+	pos      int
+	realtime bool
+	start    time.Time
+}
+
+// NewSampleSource returns a source reading samples at rate.
+func NewSampleSource(samples []float64, rate int, realtime bool) *SampleSource {
+	return &SampleSource{samples: samples, rate: rate, realtime: realtime}
+}
+
+// OpenWAVSource loads a WAV file as a source.
+func OpenWAVSource(path string, realtime bool) (*SampleSource, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open wav file: %w", err)
+	}
+	defer f.Close()
+	samples, rate, err := ReadWAV(bufio.NewReader(f))
+	if err != nil {
+		return nil, fmt.Errorf("could not read %s: %w", path, err)
+	}
+	return NewSampleSource(samples, rate, realtime), nil
+}
+
+// Read implements Source.
+func (s *SampleSource) Read(buf []float64) (int, error) {
+	if s.pos >= len(s.samples) {
+		return 0, io.EOF
+	}
+	n := copy(buf, s.samples[s.pos:])
+	s.pos += n
+	if s.realtime {
+		if s.start.IsZero() {
+			s.start = time.Now()
+		}
+		due := s.start.Add(time.Duration(float64(s.pos) / float64(s.rate) * float64(time.Second)))
+		time.Sleep(time.Until(due))
+	}
+	return n, nil
+}
+
+// SampleRate implements Source.
+func (s *SampleSource) SampleRate() int { return s.rate }
+
+// Close implements Source.
+func (s *SampleSource) Close() error { return nil }
+
+// recorderCommands are external recorders that write raw signed 16-bit
+// little-endian mono audio to stdout, tried in order. {rate} is replaced
+// with the sample rate.
+var recorderCommands = [][]string{
+	{"parec", "--format=s16le", "--channels=1", "--rate={rate}", "--latency-msec=20"},
+	{"pw-record", "--format=s16", "--channels=1", "--rate={rate}", "-"},
+	{"arecord", "-q", "-f", "S16_LE", "-c", "1", "-r", "{rate}", "-t", "raw"},
+}
+
+// Recorder is a Source capturing the default microphone through an external
+// command-line recorder found on PATH.
+type Recorder struct {
+	mu   sync.Mutex
+	cmd  *exec.Cmd
+	out  *bufio.Reader
+	rate int
+	raw  []byte
+}
+
+// StartRecorder starts the first available system recorder, or fails when
+// none is installed (e.g. on a headless machine).
+func StartRecorder(rate int) (*Recorder, error) {
+	for _, args := range recorderCommands {
+		if _, err := exec.LookPath(args[0]); err != nil {
+			continue
+		}
+		argv := make([]string, len(args))
+		for i, a := range args {
+			argv[i] = strings.ReplaceAll(a, "{rate}", strconv.Itoa(rate))
+		}
+		cmd := exec.Command(argv[0], argv[1:]...)
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			return nil, err
+		}
+		if err := cmd.Start(); err != nil {
+			return nil, fmt.Errorf("could not start %s: %w", argv[0], err)
+		}
+		return &Recorder{cmd: cmd, out: bufio.NewReader(stdout), rate: rate}, nil
+	}
+	return nil, fmt.Errorf("no audio recorder found on PATH")
+}
+
+// Read implements Source.
+func (r *Recorder) Read(buf []float64) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if cap(r.raw) < len(buf)*2 {
+		r.raw = make([]byte, len(buf)*2)
+	}
+	raw := r.raw[:len(buf)*2]
+	n, err := io.ReadFull(r.out, raw)
+	n /= 2
+	for i := 0; i < n; i++ {
+		buf[i] = float64(int16(binary.LittleEndian.Uint16(raw[i*2:]))) / 32768
+	}
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+	return n, err
+}
+
+// SampleRate implements Source.
+func (r *Recorder) SampleRate() int { return r.rate }
+
+// Close implements Source.
+func (r *Recorder) Close() error {
+	if r.cmd.Process != nil {
+		_ = r.cmd.Process.Kill()
+	}
+	_ = r.cmd.Wait()
+	return nil
+}
+
+// Name returns the external command used for recording.
+func (r *Recorder) Name() string { return r.cmd.Args[0] }
+
+// OpenSource opens an input from a description: "mic" (system recorder),
+// "wav:<path>" (a WAV file played back in real time) or "none". On error
+// the source is nil.
+func OpenSource(input string) (Source, error) {
+	if err := CheckInput(input); err != nil {
+		return nil, err
+	}
+	// The constructors return concrete pointers; a nil one must not become a
+	// non-nil Source.
+	switch input {
+	case "mic":
+		r, err := StartRecorder(DefaultSampleRate)
+		if err != nil {
+			return nil, err
+		}
+		return r, nil
+	case "none":
+		return nil, fmt.Errorf("audio input disabled")
+	}
+	s, err := OpenWAVSource(strings.TrimPrefix(input, "wav:"), true)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// CheckInput reports whether OpenSource accepts an input description,
+// without opening it. A WAV file must exist; whether a recorder is installed
+// is only found out on opening.
+func CheckInput(input string) error {
+	switch {
+	case input == "mic" || input == "none":
+		return nil
+	case strings.HasPrefix(input, "wav:"):
+		path := strings.TrimPrefix(input, "wav:")
+		if path == "" {
+			return fmt.Errorf("wav: input needs a file path")
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("could not open audio input: %w", err)
+		}
+		if info.IsDir() {
+			return fmt.Errorf("audio input %s is a directory", path)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown audio input %q (want mic, none or wav:<path>)", input)
+	}
+}
@@ -0,0 +1,47 @@
+package audio
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestOpenSourceError checks a failed open returns a nil Source, so callers
+// can test it against nil.
+func TestOpenSourceError(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.wav")
+	for _, input := range []string{"wav:" + missing, "none", "bogus"} {
+		src, err := OpenSource(input)
+		if err == nil {
+			t.Errorf("OpenSource(%q): no error", input)
+		}
+		if src != nil {
+			t.Errorf("OpenSource(%q) = %#v, want nil", input, src)
+		}
+	}
+}
+
+func TestCheckInput(t *testing.T) {
+	dir := t.TempDir()
+	wav := filepath.Join(dir, "in.wav")
+	if err := os.WriteFile(wav, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		input   string
+		wantErr bool
+	}{
+		{"mic", false},
+		{"none", false},
+		{"wav:" + wav, false},
+		{"wav:", true},
+		{"wav:" + filepath.Join(dir, "missing.wav"), true},
+		{"wav:" + dir, true},
+		{"line-in", true},
+	}
+	for _, tt := range tests {
+		if err := CheckInput(tt.input); (err != nil) != tt.wantErr {
+			t.Errorf("CheckInput(%q) = %v, want error %v", tt.input, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package audio
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sync"
+)
+
+const wavHeaderSize = 44
+
+// writeWAVHeader writes a 16-bit mono PCM WAV header for dataBytes of sample data.
+func writeWAVHeader(w io.Writer, rate, dataBytes int) error {
+	h := make([]byte, wavHeaderSize)
+	copy(h[0:], "RIFF")
+	binary.LittleEndian.PutUint32(h[4:], uint32(36+dataBytes))
+	copy(h[8:], "WAVE")
+	copy(h[12:], "fmt ")
+	binary.LittleEndian.PutUint32(h[16:], 16) // fmt chunk size
+	binary.LittleEndian.PutUint16(h[20:], 1)  // PCM
+	binary.LittleEndian.PutUint16(h[22:], 1)  // mono
+	binary.LittleEndian.PutUint32(h[24:], uint32(rate))
+	binary.LittleEndian.PutUint32(h[28:], uint32(rate*2)) // byte rate
+	binary.LittleEndian.PutUint16(h[32:], 2)              // block align
+	binary.LittleEndian.PutUint16(h[34:], 16)             // bits per sample
+	copy(h[36:], "data")
+	binary.LittleEndian.PutUint32(h[40:], uint32(dataBytes))
+	_, err := w.Write(h)
+	return err
+}
+
+// encodePCM16 converts float samples to little-endian 16-bit PCM, clipping at ±1.
+func encodePCM16(samples []float64) []byte {
+	b := make([]byte, len(samples)*2)
+	for i, v := range samples {
+		v = math.Max(-1, math.Min(1, v))
+		binary.LittleEndian.PutUint16(b[i*2:], uint16(int16(math.Round(v*32767))))
+	}
+	return b
+}
+
+// WriteWAV writes samples as a complete 16-bit mono WAV stream.
+func WriteWAV(w io.Writer, samples []float64, rate int) error {
+	if err := writeWAVHeader(w, rate, len(samples)*2); err != nil {
+		return err
+	}
+	_, err := w.Write(encodePCM16(samples))
+	return err
+}
+
+// WAVFile is a Sink that appends everything written to a WAV file. The
+// header is rewritten after every Write, so the file is valid even if the
+// program exits without calling Close.
+type WAVFile struct {
+	mu    sync.Mutex
+	f     *os.File
+	rate  int
+	bytes int
+}
+
+// CreateWAVFile creates (or truncates) path and returns a sink writing to it.
+func CreateWAVFile(path string, rate int) (*WAVFile, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not create wav file: %w", err)
+	}
+	if err := writeWAVHeader(f, rate, 0); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("could not write wav header: %w", err)
+	}
+	return &WAVFile{f: f, rate: rate}, nil
+}
+
+// Write implements Sink.
+func (w *WAVFile) Write(samples []float64) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.f == nil {
+		return fmt.Errorf("wav file is closed")
+	}
+	if _, err := w.f.Seek(0, io.SeekEnd); err != nil {
+		return err
+	}
+	data := encodePCM16(samples)
+	if _, err := w.f.Write(data); err != nil {
+		return err
+	}
+	w.bytes += len(data)
+	if _, err := w.f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	return writeWAVHeader(w.f, w.rate, w.bytes)
+}
+
+// SampleRate implements Sink.
+func (w *WAVFile) SampleRate() int { return w.rate }
+
+// Close implements Sink.
+func (w *WAVFile) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.f == nil {
+		return nil
+	}
+	err := w.f.Close()
+	w.f = nil
+	return err
+}
+
+// maxFmtChunk is the largest fmt chunk ReadWAV accepts. Real ones are 16 to
+// 40 bytes; the limit stops a corrupt header forcing a huge allocation.
+const maxFmtChunk = 1 << 10
+
+// ReadWAV decodes a WAV stream to mono samples in [-1, 1], averaging the
+// channels. It understands integer PCM of 8, 16, 24 or 32 bits and 32 or 64
+// bit float, including the WAVE_FORMAT_EXTENSIBLE wrapper.
+func ReadWAV(r io.Reader) (samples []float64, rate int, err error) {
+	var riff [12]byte
+	if _, err := io.ReadFull(r, riff[:]); err != nil {
+		return nil, 0, fmt.Errorf("could not read wav header: %w", err)
+	}
+	if string(riff[0:4]) != "RIFF" || string(riff[8:12]) != "WAVE" {
+		return nil, 0, fmt.Errorf("not a wav file")
+	}
+	var format, channels, bits int
+	for {
+		var hdr [8]byte
+		if _, err := io.ReadFull(r, hdr[:]); err != nil {
+			return nil, 0, fmt.Errorf("wav file has no data chunk")
+		}
+		id, size := string(hdr[0:4]), int(binary.LittleEndian.Uint32(hdr[4:]))
+		if id == "data" {
+			if channels == 0 {
+				return nil, 0, fmt.Errorf("wav data before fmt chunk")
+			}
+			// Streamed files may claim more data than they hold; take what is there.
+			data, err := io.ReadAll(io.LimitReader(r, int64(size)))
+			if err != nil {
+				return nil, 0, fmt.Errorf("could not read wav data: %w", err)
+			}
+			samples, err := decodePCM(data, format, channels, bits)
+			return samples, rate, err
+		}
+		padded := int64(size) + int64(size%2) // chunks are word aligned
+		if id != "fmt " {
+			// Skip metadata without holding it in memory, whatever size it claims.
+			if _, err := io.CopyN(io.Discard, r, padded); err != nil {
+				return nil, 0, fmt.Errorf("could not read wav %q chunk: %w", id, err)
+			}
+			continue
+		}
+		if size < 16 {
+			return nil, 0, fmt.Errorf("wav fmt chunk too short")
+		}
+		if size > maxFmtChunk {
+			return nil, 0, fmt.Errorf("wav fmt chunk too long (%d bytes)", size)
+		}
+		body := make([]byte, padded)
+		if _, err := io.ReadFull(r, body); err != nil {
+			return nil, 0, fmt.Errorf("could not read wav %q chunk: %w", id, err)
+		}
+		format = int(binary.LittleEndian.Uint16(body[0:]))
+		channels = int(binary.LittleEndian.Uint16(body[2:]))
+		rate = int(binary.LittleEndian.Uint32(body[4:]))
+		bits = int(binary.LittleEndian.Uint16(body[14:]))
+		if format == 0xFFFE && size >= 26 {
+			format = int(binary.LittleEndian.Uint16(body[24:])) // sub-format GUID starts with the format code
+		}
+	}
+}
+
+// decodePCM converts interleaved sample data to mono floats.
+func decodePCM(data []byte, format, channels, bits int) ([]float64, error) {
+	width := bits / 8
+	var sample func(b []byte) float64
+	switch {
+	case format == 1 && bits == 8:
+		sample = func(b []byte) float64 { return (float64(b[0]) - 128) / 128 }
+	case format == 1 && bits == 16:
+		sample = func(b []byte) float64 { return float64(int16(binary.LittleEndian.Uint16(b))) / 32768 }
+	case format == 1 && bits == 24:
+		sample = func(b []byte) float64 {
+			v := int32(uint32(b[0])<<8|uint32(b[1])<<16|uint32(b[2])<<24) >> 8
+			return float64(v) / (1 << 23)
+		}
+	case format == 1 && bits == 32:
+		sample = func(b []byte) float64 { return float64(int32(binary.LittleEndian.Uint32(b))) / (1 << 31) }
+	case format == 3 && bits == 32:
+		sample = func(b []byte) float64 { return float64(math.Float32frombits(binary.LittleEndian.Uint32(b))) }
+	case format == 3 && bits == 64:
+		sample = func(b []byte) float64 { return math.Float64frombits(binary.LittleEndian.Uint64(b)) }
+	default:
+		return nil, fmt.Errorf("unsupported wav encoding (format %d, %d bits)", format, bits)
+	}
+	frame := width * channels
+	out := make([]float64, len(data)/frame)
+	for i := range out {
+		sum := 0.0
+		for c := 0; c < channels; c++ {
+			off := i*frame + c*width
+			sum += sample(data[off : off+width])
+		}
+		out[i] = sum / float64(channels)
+	}
+	return out, nil
+}
@@ -0,0 +1,150 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"strings"
+	"testing"
+)
+
+// chunk is one RIFF chunk of a hand-built WAV file.
+type chunk struct {
+	id   string
+	size uint32 // size to claim; 0 means len(body)
+	body []byte
+}
+
+// buildWAV assembles a RIFF/WAVE file from chunks.
+func buildWAV(chunks ...chunk) []byte {
+	var b bytes.Buffer
+	b.WriteString("RIFF")
+	binary.Write(&b, binary.LittleEndian, uint32(0)) // readers don't rely on the RIFF size
+	b.WriteString("WAVE")
+	for _, c := range chunks {
+		size := c.size
+		if size == 0 {
+			size = uint32(len(c.body))
+		}
+		b.WriteString(c.id)
+		binary.Write(&b, binary.LittleEndian, size)
+		b.Write(c.body)
+		if len(c.body)%2 == 1 {
+			b.WriteByte(0)
+		}
+	}
+	return b.Bytes()
+}
+
+// fmtChunk is a plain fmt chunk for format, channels and bits at 8 kHz.
+func fmtChunk(format, channels, bits int) chunk {
+	body := make([]byte, 16)
+	binary.LittleEndian.PutUint16(body[0:], uint16(format))
+	binary.LittleEndian.PutUint16(body[2:], uint16(channels))
+	binary.LittleEndian.PutUint32(body[4:], 8000)
+	binary.LittleEndian.PutUint32(body[8:], uint32(8000*channels*bits/8))
+	binary.LittleEndian.PutUint16(body[12:], uint16(channels*bits/8))
+	binary.LittleEndian.PutUint16(body[14:], uint16(bits))
+	return chunk{id: "fmt ", body: body}
+}
+
+func le(v interface{}) []byte {
+	var b bytes.Buffer
+	binary.Write(&b, binary.LittleEndian, v)
+	return b.Bytes()
+}
+
+func TestReadWAVEncodings(t *testing.T) {
+	tests := []struct {
+		name     string
+		fmt      chunk
+		data     []byte
+		want     []float64
+		tolerate float64
+	}{
+		{"8-bit", fmtChunk(1, 1, 8), []byte{128, 255, 0}, []float64{0, 127.0 / 128, -1}, 1e-9},
+		{"16-bit", fmtChunk(1, 1, 16), le([]int16{0, 16384, -32768}), []float64{0, 0.5, -1}, 1e-9},
+		{"24-bit", fmtChunk(1, 1, 24), []byte{0, 0, 0, 0, 0, 0x40, 0, 0, 0x80}, []float64{0, 0.5, -1}, 1e-9},
+		{"32-bit", fmtChunk(1, 1, 32), le([]int32{0, 1 << 30, math.MinInt32}), []float64{0, 0.5, -1}, 1e-9},
+		{"32-bit float", fmtChunk(3, 1, 32), le([]float32{0, 0.25, -0.75}), []float64{0, 0.25, -0.75}, 1e-7},
+		{"64-bit float", fmtChunk(3, 1, 64), le([]float64{0, 0.25, -0.75}), []float64{0, 0.25, -0.75}, 1e-12},
+		{"stereo mixed down", fmtChunk(1, 2, 16), le([]int16{16384, -16384, 16384, 16384}), []float64{0, 0.5}, 1e-9},
+	}
+	for _, tt := range tests {
+		file := buildWAV(chunk{id: "LIST", body: []byte("INFOjunk!")}, tt.fmt, chunk{id: "data", body: tt.data})
+		got, rate, err := ReadWAV(bytes.NewReader(file))
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if rate != 8000 {
+			t.Errorf("%s: rate = %d, want 8000", tt.name, rate)
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: got %d samples %v, want %v", tt.name, len(got), got, tt.want)
+			continue
+		}
+		for i := range got {
+			if math.Abs(got[i]-tt.want[i]) > tt.tolerate {
+				t.Errorf("%s: sample %d = %v, want %v", tt.name, i, got[i], tt.want[i])
+			}
+		}
+	}
+}
+
+func TestWriteWAVRoundTrip(t *testing.T) {
+	samples := Tone(440, 0.05, 0.8, 22050)
+	var b bytes.Buffer
+	if err := WriteWAV(&b, samples, 22050); err != nil {
+		t.Fatal(err)
+	}
+	got, rate, err := ReadWAV(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rate != 22050 || len(got) != len(samples) {
+		t.Fatalf("got %d samples at %d Hz, want %d at 22050", len(got), rate, len(samples))
+	}
+	for i := range got {
+		if math.Abs(got[i]-samples[i]) > 2.0/32767 { // written at 32767, read at 32768
+			t.Fatalf("sample %d = %v, want %v", i, got[i], samples[i])
+		}
+	}
+}
+
+// TestReadWAVBadInput checks corrupt and hostile files fail cleanly, and
+// without allocating whatever a chunk header claims.
+func TestReadWAVBadInput(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    []byte
+		wantErr string
+	}{
+		{"empty", nil, "could not read wav header"},
+		{"not riff", []byte("RIFX\x00\x00\x00\x00WAVE"), "not a wav file"},
+		{"no data", buildWAV(fmtChunk(1, 1, 16)), "no data chunk"},
+		{"data before fmt", buildWAV(chunk{id: "data", body: []byte{0, 0}}), "data before fmt"},
+		{"short fmt", buildWAV(chunk{id: "fmt ", body: make([]byte, 8)}), "too short"},
+		{"huge fmt", buildWAV(chunk{id: "fmt ", size: 0xFFFFFFF0, body: make([]byte, 16)}), "too long"},
+		{"huge metadata", buildWAV(fmtChunk(1, 1, 16), chunk{id: "LIST", size: 0xFFFFFFF0, body: []byte("INFO")}), `"LIST" chunk`},
+		{"unsupported", buildWAV(fmtChunk(2, 1, 4), chunk{id: "data", body: []byte{0, 0}}), "unsupported wav encoding"},
+	}
+	for _, tt := range tests {
+		_, _, err := ReadWAV(bytes.NewReader(tt.file))
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: got error %v, want one containing %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestReadWAVTruncatedData(t *testing.T) {
+	// Streamed recordings may claim more data than they hold.
+	file := buildWAV(fmtChunk(1, 1, 16), chunk{id: "data", size: 1 << 30, body: le([]int16{16384, 16384})})
+	got, _, err := ReadWAV(bytes.NewReader(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Errorf("got %d samples, want 2", len(got))
+	}
+}
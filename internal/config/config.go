@@ -1,25 +1,385 @@
+// Package config loads settings from defaults, a config file and
+// environment variables, in that order of precedence.
 package config
 
 import (
+	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/paulgreig/guitar-training/internal/audio"
+	"github.com/paulgreig/guitar-training/internal/fretboard"
+	"github.com/paulgreig/guitar-training/internal/metronome"
+	"github.com/paulgreig/guitar-training/internal/midi"
+	"github.com/paulgreig/guitar-training/internal/obs"
 )
 
 type Config struct {
-	DataPath string // Path to data directory
+	DataPaths   []string            // Content directories layered over the built-in content, later ones winning
+	Tuning      string              // Tuning ID or name (see fretboard.Tunings)
+	Theme       string              // Colour theme (see Themes)
+	Click       string              // Metronome output: bell, none, audio or wav:<path>
+	Audio       string              // Synthesised audio output: audio, none or wav:<path>
+	Export      string              // Directory exported files are written to
+	MIDIIn      string              // MIDI input: empty, none, or a raw MIDI device, pipe or file path
+	AudioIn     string              // Audio input for pitch detection: mic, none or wav:<path>
+	A4          float64             // Reference pitch of A4 in Hz for the tuner and pitch detection
+	Practice    string              // Practice log file; empty means the XDG data directory
+	LogLevel    string              // debug, info, warn or error
+	LogPath     string              // Log file
+	MetricsPort int                 // Port serving /metrics; 0 turns the metrics server off
+	Keys        map[string][]string // Extra keys for each of KeyActions
+	File        string              // Config file the settings were read from, if any
+}
+
+// Themes are the colour themes the TUI provides.
+var Themes = []string{"dark", "light", "plain"}
+
+// KeyActions are the actions keys can be bound to in the [keys] table.
+var KeyActions = []string{"up", "down", "select", "back", "quit", "dismiss", "reload"}
+
+// setting is one value that can be set by its config file key or its
+// environment variable.
+type setting struct {
+	key   string
+	env   string
+	path  bool                        // relative paths in a config file are resolved from its directory
+	field func(c *Config) interface{} // *string, *[]string, *float64 or *int
+}
+
+var settings = []setting{
+	{"data_path", "DATA_PATH", true, func(c *Config) interface{} { return &c.DataPaths }},
+	{"tuning", "TUNING", false, func(c *Config) interface{} { return &c.Tuning }},
+	{"theme", "THEME", false, func(c *Config) interface{} { return &c.Theme }},
+	{"click_output", "CLICK_OUTPUT", false, func(c *Config) interface{} { return &c.Click }},
+	{"audio_output", "AUDIO_OUTPUT", false, func(c *Config) interface{} { return &c.Audio }},
+	{"export_dir", "EXPORT_DIR", true, func(c *Config) interface{} { return &c.Export }},
+	{"midi_input", "MIDI_INPUT", false, func(c *Config) interface{} { return &c.MIDIIn }},
+	{"audio_input", "AUDIO_INPUT", false, func(c *Config) interface{} { return &c.AudioIn }},
+	{"reference_pitch", "REFERENCE_PITCH", false, func(c *Config) interface{} { return &c.A4 }},
+	{"practice_log", "PRACTICE_LOG", true, func(c *Config) interface{} { return &c.Practice }},
+	{"log.level", "LOG_LEVEL", false, func(c *Config) interface{} { return &c.LogLevel }},
+	{"log.path", "LOG_PATH", true, func(c *Config) interface{} { return &c.LogPath }},
+	{"metrics.port", "METRICS_PORT", false, func(c *Config) interface{} { return &c.MetricsPort }},
 }
 
-// Load loads configuration from environment variables
-func Load() (*Config, error) {
-	cfg := &Config{
-		DataPath: getEnv("DATA_PATH", "data"),
+// Default returns the settings used when nothing else is configured.
+func Default() *Config {
+	return &Config{
+		Tuning:      "standard",
+		Theme:       "dark",
+		Click:       "bell",
+		Audio:       "audio",
+		Export:      ".",
+		AudioIn:     "mic",
+		A4:          440,
+		LogLevel:    "info",
+		LogPath:     filepath.Join("logs", "app.log"),
+		MetricsPort: 9090,
+		Keys:        map[string][]string{},
 	}
+}
+
+// DefaultPath returns the config file location under the XDG config
+// directory: $XDG_CONFIG_HOME/guitar-training/config.toml, falling back to
+// ~/.config when XDG_CONFIG_HOME is unset.
+func DefaultPath() (string, error) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("could not find home directory: %w", err)
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "guitar-training", "config.toml"), nil
+}
 
+// Load loads configuration from the TOML config file at path, then from
+// environment variables, which win over the file. An empty path means the
+// file at DefaultPath, which needn't exist. Every invalid setting is
+// reported, each naming the file line or variable it came from.
+func Load(path string) (*Config, error) {
+	cfg := Default()
+	from := map[string]string{} // where each setting was last set
+	if path == "" {
+		if def, err := DefaultPath(); err == nil {
+			if _, err := os.Stat(def); err == nil {
+				path = def
+			}
+		}
+	}
+	var errs []error
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not read config file: %w", err)
+		}
+		values, err := parseTOML(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		errs = append(errs, cfg.apply(values, path, from))
+		cfg.File = path
+	}
+	errs = append(errs, cfg.readEnv(from), cfg.validate(from))
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
 	return cfg, nil
 }
 
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+// apply sets the values read from the config file at path.
+func (c *Config) apply(values map[string]tomlValue, path string, from map[string]string) error {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return values[keys[i]].line < values[keys[j]].line })
+
+	var errs []error
+	for _, k := range keys {
+		v := values[k]
+		where := fmt.Sprintf("%s:%d", path, v.line)
+		if action, ok := strings.CutPrefix(k, "keys."); ok {
+			bound, err := keyList(v.v)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %s: %w", where, k, err))
+				continue
+			}
+			c.Keys[action] = bound
+			from[k] = where
+			continue
+		}
+		i := slices.IndexFunc(settings, func(s setting) bool { return s.key == k })
+		if i < 0 {
+			errs = append(errs, fmt.Errorf("%s: unknown setting %q", where, k))
+			continue
+		}
+		s := settings[i]
+		if err := set(s.field(c), v.v); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %s: %w", where, k, err))
+			continue
+		}
+		if s.path {
+			resolvePaths(s.field(c), filepath.Dir(path))
+		}
+		from[k] = where
+	}
+	return errors.Join(errs...)
+}
+
+// readEnv applies the settings given in environment variables.
+func (c *Config) readEnv(from map[string]string) error {
+	var errs []error
+	for _, s := range settings {
+		value := os.Getenv(s.env)
+		if value == "" {
+			continue
+		}
+		var v interface{} = value
+		switch s.field(c).(type) {
+		case *[]string:
+			var list []interface{}
+			for _, p := range filepath.SplitList(value) {
+				list = append(list, p)
+			}
+			v = list
+		case *float64:
+			f, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %q is not a number", s.env, value))
+				continue
+			}
+			v = f
+		case *int:
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %q is not a whole number", s.env, value))
+				continue
+			}
+			v = n
+		}
+		if err := set(s.field(c), v); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", s.env, err))
+			continue
+		}
+		if s.path {
+			resolvePaths(s.field(c), "")
+		}
+		from[s.key] = s.env
+	}
+	return errors.Join(errs...)
+}
+
+// validate checks the settings make sense together, reporting every problem.
+func (c *Config) validate(from map[string]string) error {
+	var errs []error
+	problem := func(key, format string, args ...interface{}) {
+		// Name the file line and key, or the variable, that set the value.
+		where := from[key]
+		switch {
+		case where == "":
+			where = "default " + key
+		case strings.Contains(where, ":"):
+			where += ": " + key
+		}
+		errs = append(errs, fmt.Errorf("%s: %s", where, fmt.Sprintf(format, args...)))
+	}
+
+	for _, dir := range c.DataPaths {
+		info, err := os.Stat(dir)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			problem("data_path", "directory %s does not exist", dir)
+		case err != nil:
+			problem("data_path", "%v", err)
+		case !info.IsDir():
+			problem("data_path", "%s is not a directory", dir)
+		}
+	}
+	if _, ok := fretboard.LookupTuning(c.Tuning); !ok {
+		problem("tuning", "unknown tuning %q", c.Tuning)
+	}
+	if !slices.Contains(Themes, c.Theme) {
+		problem("theme", "unknown theme %q, want one of %s", c.Theme, strings.Join(Themes, ", "))
+	}
+	if err := metronome.CheckClicker(c.Click); err != nil {
+		problem("click_output", "%v", err)
+	}
+	if err := audio.CheckOutput(c.Audio); err != nil {
+		problem("audio_output", "%v", err)
+	}
+	if err := audio.CheckInput(c.AudioIn); err != nil {
+		problem("audio_input", "%v", err)
+	}
+	if err := midi.CheckInput(c.MIDIIn); err != nil {
+		problem("midi_input", "%v", err)
+	}
+	if c.A4 <= 0 {
+		problem("reference_pitch", "invalid pitch %v: want a frequency in Hz", c.A4)
+	}
+	if _, err := obs.ParseLevel(c.LogLevel); err != nil {
+		problem("log.level", "%v", err)
+	}
+	if c.LogPath == "" {
+		problem("log.path", "must not be empty")
+	}
+	if c.MetricsPort < 0 || c.MetricsPort > 65535 {
+		problem("metrics.port", "invalid port %d: want 1-65535, or 0 to turn metrics off", c.MetricsPort)
+	}
+
+	actions := make([]string, 0, len(c.Keys))
+	for action := range c.Keys {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+	boundTo := map[string]string{}
+	for _, action := range actions {
+		key := "keys." + action
+		if !slices.Contains(KeyActions, action) {
+			problem(key, "unknown action, want one of %s", strings.Join(KeyActions, ", "))
+			continue
+		}
+		for _, k := range c.Keys[action] {
+			if other, dup := boundTo[k]; dup {
+				problem(key, "%q is already bound to %s", k, other)
+			}
+			boundTo[k] = action
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// set stores a config value in field, checking its type.
+func set(field interface{}, v interface{}) error {
+	switch p := field.(type) {
+	case *string:
+		s, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("want a string, got %v", v)
+		}
+		*p = s
+	case *[]string:
+		items, ok := v.([]interface{})
+		if !ok {
+			items = []interface{}{v}
+		}
+		list := make([]string, 0, len(items))
+		for _, item := range items {
+			s, ok := item.(string)
+			if !ok || s == "" {
+				return fmt.Errorf("want a path or a list of paths, got %v", item)
+			}
+			list = append(list, s)
+		}
+		*p = list
+	case *float64:
+		switch n := v.(type) {
+		case float64:
+			*p = n
+		case int64:
+			*p = float64(n)
+		default:
+			return fmt.Errorf("want a number, got %v", v)
+		}
+	case *int:
+		n, ok := v.(int64)
+		if !ok {
+			return fmt.Errorf("want a whole number, got %v", v)
+		}
+		*p = int(n)
+	}
+	return nil
+}
+
+// keyList reads the keys bound to an action: a key name or a list of them,
+// such as "w" or ["w", "ctrl+p"]. "space" names the space bar.
+func keyList(v interface{}) ([]string, error) {
+	items, ok := v.([]interface{})
+	if !ok {
+		items = []interface{}{v}
+	}
+	keys := make([]string, 0, len(items))
+	for _, item := range items {
+		k, ok := item.(string)
+		if !ok || k == "" {
+			return nil, fmt.Errorf("want key names such as \"w\" or \"ctrl+p\", got %v", item)
+		}
+		if k == "space" {
+			k = " "
+		}
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+// resolvePaths resolves the path or paths in field with resolvePath.
+func resolvePaths(field interface{}, dir string) {
+	switch p := field.(type) {
+	case *string:
+		*p = resolvePath(*p, dir)
+	case *[]string:
+		for i := range *p {
+			(*p)[i] = resolvePath((*p)[i], dir)
+		}
+	}
+}
+
+// resolvePath expands a leading ~ and makes a relative path relative to dir.
+func resolvePath(p, dir string) string {
+	if p == "~" || strings.HasPrefix(p, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			p = filepath.Join(home, p[1:])
+		}
+	}
+	if p == "" || dir == "" || filepath.IsAbs(p) {
+		return p
 	}
-	return defaultValue
+	return filepath.Join(dir, p)
 }
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.toml")
+	data := "tuning = \"drop-d\"\n[log]\nlevel = \"debug\"\npath = \"app.log\"\n[keys]\nup = [\"w\", \"ctrl+p\"]\nselect = \"space\"\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("THEME", "plain")
+	t.Setenv("TUNING", "")
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Tuning != "drop-d" || cfg.Theme != "plain" || cfg.LogLevel != "debug" || cfg.File != path {
+		t.Errorf("got tuning %q theme %q level %q file %q", cfg.Tuning, cfg.Theme, cfg.LogLevel, cfg.File)
+	}
+	if want := filepath.Join(dir, "app.log"); cfg.LogPath != want {
+		t.Errorf("log path %q, want %q relative to the config file", cfg.LogPath, want)
+	}
+	if got := strings.Join(cfg.Keys["up"], ","); got != "w,ctrl+p" || cfg.Keys["select"][0] != " " {
+		t.Errorf("keys %q", cfg.Keys)
+	}
+}
+
+// TestLoadErrors checks every invalid setting is reported at startup with the
+// file line and key, or the variable, it came from.
+func TestLoadErrors(t *testing.T) {
+	dir := t.TempDir()
+	missing := filepath.Join(dir, "missing")
+	tests := []struct {
+		name string
+		data string
+		env  map[string]string
+		want []string
+	}{
+		{"unknown values", "tuning = \"drop-x\"\ntheme = \"neon\"\n", nil, []string{
+			`config.toml:1: tuning: unknown tuning "drop-x"`,
+			`config.toml:2: theme: unknown theme "neon"`,
+		}},
+		{"outputs and inputs", "click_output = \"drum\"\naudio_output = \"wav:" + missing + "/out.wav\"\n" +
+			"audio_input = \"wav:" + missing + ".wav\"\nmidi_input = \"" + missing + "\"\n", nil, []string{
+			`config.toml:1: click_output: unknown click output "drum"`,
+			"config.toml:2: audio_output: directory " + missing + " for",
+			"config.toml:3: audio_input: could not open audio input",
+			"config.toml:4: midi_input: could not open midi input",
+		}},
+		{"environment", "", map[string]string{"AUDIO_OUTPUT": "speakers", "METRICS_PORT": "ninety"}, []string{
+			`AUDIO_OUTPUT: unknown audio output "speakers"`,
+			`METRICS_PORT: "ninety" is not a whole number`,
+		}},
+		{"types", "[metrics]\nport = \"9090\"\n[log]\nlevel = 3\n", nil, []string{
+			"config.toml:2: metrics.port: want a whole number",
+			"config.toml:4: log.level: want a string",
+		}},
+		{"unknown settings and keys", "colour = \"red\"\n[keys]\njump = \"x\"\nup = \"k\"\ndown = \"k\"\n", nil, []string{
+			`config.toml:1: unknown setting "colour"`,
+			"config.toml:3: keys.jump: unknown action",
+			`config.toml:4: keys.up: "k" is already bound to down`,
+		}},
+		{"syntax", "a = 1\nb = \n", nil, []string{"config.toml: line 2:"}},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "config.toml")
+		if err := os.WriteFile(path, []byte(tt.data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		for _, s := range settings {
+			t.Setenv(s.env, tt.env[s.env])
+		}
+		_, err := Load(path)
+		if err == nil {
+			t.Errorf("%s: no error", tt.name)
+			continue
+		}
+		lines := strings.Split(err.Error(), "\n")
+		if len(lines) != len(tt.want) {
+			t.Errorf("%s: got %d errors, want %d:\n%v", tt.name, len(lines), len(tt.want), err)
+		}
+		for _, want := range tt.want {
+			found := false
+			for _, line := range lines {
+				found = found || strings.Contains(line, want)
+			}
+			if !found {
+				t.Errorf("%s: no error containing %q in:\n%v", tt.name, want, err)
+			}
+		}
+	}
+}
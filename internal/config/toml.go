@@ -0,0 +1,150 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/pelletier/go-toml/v2/unstable"
+)
+
+// tomlValue is a value read from a config file: a string, int64, float64,
+// bool, date or time, or []interface{} of those, with the line it was on.
+type tomlValue struct {
+	v    interface{}
+	line int
+}
+
+// parseTOML decodes a TOML document and flattens its tables into dotted keys,
+// so [log] level = "debug", log.level = "debug" and log = { level = "debug" }
+// all read as "log.level". Arrays, including arrays of tables, are values.
+// Each value comes with the line its key is on, and syntax errors name the
+// line at fault.
+func parseTOML(data string) (map[string]tomlValue, error) {
+	var doc map[string]interface{}
+	if err := toml.Unmarshal([]byte(data), &doc); err != nil {
+		var derr *toml.DecodeError
+		if errors.As(err, &derr) {
+			line, _ := derr.Position()
+			return nil, fmt.Errorf("line %d: %s", line, strings.TrimPrefix(derr.Error(), "toml: "))
+		}
+		// Keys set twice aren't syntax errors, so come without a position.
+		if _, lerr := keyLines([]byte(data)); lerr != nil {
+			return nil, lerr
+		}
+		return nil, err
+	}
+	lines, err := keyLines([]byte(data))
+	if err != nil {
+		return nil, err
+	}
+	values := map[string]tomlValue{}
+	flatten(values, "", doc, lines)
+	return values, nil
+}
+
+// flatten adds the values in table to values under dotted keys starting with prefix.
+func flatten(values map[string]tomlValue, prefix string, table map[string]interface{}, lines map[string]int) {
+	for k, v := range table {
+		key := joinKey(prefix, k)
+		if sub, ok := v.(map[string]interface{}); ok {
+			flatten(values, key, sub, lines)
+			continue
+		}
+		values[key] = tomlValue{v, lines[key]}
+	}
+}
+
+// keyLines maps the dotted keys set in a TOML document to their lines. It
+// reports keys and tables that are set twice.
+func keyLines(data []byte) (map[string]int, error) {
+	lines := map[string]int{}
+	tables := map[string]int{}
+	var p unstable.Parser
+	p.Reset(data)
+	table := ""
+	scope := lines // where the current table's keys are recorded
+	for p.NextExpression() {
+		e := p.Expression()
+		switch e.Kind {
+		case unstable.Table:
+			var line int
+			table, line = nodeKey(&p, e.Key(), "")
+			if prev, dup := tables[table]; dup {
+				return nil, fmt.Errorf("line %d: table [%s] is already defined on line %d", line, table, prev)
+			}
+			tables[table] = line
+			scope = lines
+		case unstable.ArrayTable:
+			// The array is the value; each of its tables has keys of its own.
+			var line int
+			table, line = nodeKey(&p, e.Key(), "")
+			if _, seen := lines[table]; !seen {
+				lines[table] = line
+			}
+			scope = map[string]int{}
+		case unstable.KeyValue:
+			if err := keyValueLines(&p, e, table, scope); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return lines, nil
+}
+
+// keyValueLines records the line of a key = value pair, and of the keys of
+// an inline table value.
+func keyValueLines(p *unstable.Parser, kv *unstable.Node, prefix string, lines map[string]int) error {
+	key, line := nodeKey(p, kv.Key(), prefix)
+	if prev, dup := lines[key]; dup {
+		return fmt.Errorf("line %d: %s is already set on line %d", line, key, prev)
+	}
+	lines[key] = line
+	if v := kv.Value(); v.Kind == unstable.InlineTable {
+		children := v.Children()
+		for children.Next() {
+			if err := keyValueLines(p, children.Node(), key, lines); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// nodeKey returns the dotted key made by the parts in it, after prefix, and
+// the line it starts on.
+func nodeKey(p *unstable.Parser, it unstable.Iterator, prefix string) (string, int) {
+	key, line := prefix, 0
+	for it.Next() {
+		n := it.Node()
+		if line == 0 {
+			line = p.Shape(n.Raw).Start.Line
+		}
+		key = joinKey(key, string(n.Data))
+	}
+	return key, line
+}
+
+// joinKey appends part to a dotted key, quoting it unless it is a bare key,
+// so a quoted "log.level" key isn't read as the level in the [log] table.
+func joinKey(prefix, part string) string {
+	if !bareKey(part) {
+		part = strconv.Quote(part)
+	}
+	if prefix == "" {
+		return part
+	}
+	return prefix + "." + part
+}
+
+// bareKey reports whether k can be written unquoted: a-z, A-Z, 0-9, _ and -.
+func bareKey(k string) bool {
+	for _, r := range k {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-') {
+			return false
+		}
+	}
+	return k != ""
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+func TestParseTOML(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want map[string]tomlValue
+	}{
+		{"empty", "", map[string]tomlValue{}},
+		{"comments and blank lines", "# a comment\n\n   # indented\n", map[string]tomlValue{}},
+		{"basic string", `tuning = "drop-d"`, map[string]tomlValue{"tuning": {"drop-d", 1}}},
+		{"escapes", `s = "a\"b\\c\n\t\b\f\r"`, map[string]tomlValue{"s": {"a\"b\\c\n\t\b\f\r", 1}}},
+		{"unicode escapes", `s = "\u00e9\U0001F3B8"`, map[string]tomlValue{"s": {"é🎸", 1}}},
+		{"literal string", `path = 'C:\music\'`, map[string]tomlValue{"path": {`C:\music\`, 1}}},
+		{"multi-line basic string", "s = \"\"\"\nfirst \\\n  second\"\"\"", map[string]tomlValue{"s": {"first second", 1}}},
+		{"multi-line literal string", "s = '''\n\\n\nx'''", map[string]tomlValue{"s": {"\\n\nx", 1}}},
+		{"integer", "port = 9_091", map[string]tomlValue{"port": {int64(9091), 1}}},
+		{"negative integer", "n = -3", map[string]tomlValue{"n": {int64(-3), 1}}},
+		{"hex integer", "n = 0xff", map[string]tomlValue{"n": {int64(255), 1}}},
+		{"float", "reference_pitch = 442.5", map[string]tomlValue{"reference_pitch": {442.5, 1}}},
+		{"booleans", "a = true\nb = false", map[string]tomlValue{"a": {true, 1}, "b": {false, 2}}},
+		{"date", "d = 1979-05-27", map[string]tomlValue{"d": {toml.LocalDate{Year: 1979, Month: 5, Day: 27}, 1}}},
+		{"array", `up = ["w", "ctrl+p"]`, map[string]tomlValue{"up": {[]interface{}{"w", "ctrl+p"}, 1}}},
+		{"empty array", "up = []", map[string]tomlValue{"up": {[]interface{}{}, 1}}},
+		{"multi-line array", "a = 1\nup = [\n  \"w\", # first\n  \"k\",\n]",
+			map[string]tomlValue{"a": {int64(1), 1}, "up": {[]interface{}{"w", "k"}, 2}}},
+		{"trailing comment", `theme = "light"   # or dark`, map[string]tomlValue{"theme": {"light", 1}}},
+		{"hash in string", `s = "#1" # comment`, map[string]tomlValue{"s": {"#1", 1}}},
+		{"tables", "a = 1\n[log]\nlevel = \"debug\"\n[metrics] # server\nport = 0",
+			map[string]tomlValue{"a": {int64(1), 1}, "log.level": {"debug", 3}, "metrics.port": {int64(0), 5}}},
+		{"dotted keys", "log.level = \"warn\"\n[a.b]\nc-d = 1",
+			map[string]tomlValue{"log.level": {"warn", 1}, "a.b.c-d": {int64(1), 3}}},
+		{"spaced dotted key", "log . level = 1", map[string]tomlValue{"log.level": {int64(1), 1}}},
+		{"quoted key", `"tuning" = "standard"`, map[string]tomlValue{"tuning": {"standard", 1}}},
+		{"quoted key with a dot", `"log.level" = 1`, map[string]tomlValue{`"log.level"`: {int64(1), 1}}},
+		{"inline table", "\nlog = { level = \"debug\", path = 'x' }",
+			map[string]tomlValue{"log.level": {"debug", 2}, "log.path": {"x", 2}}},
+		{"array of tables", "[[p]]\nn = 1\n[[p]]\nn = 2",
+			map[string]tomlValue{"p": {[]interface{}{map[string]interface{}{"n": int64(1)}, map[string]interface{}{"n": int64(2)}}, 1}}},
+		{"CRLF", "a = 1\r\nb = 2\r\n", map[string]tomlValue{"a": {int64(1), 1}, "b": {int64(2), 2}}},
+	}
+	for _, tt := range tests {
+		got, err := parseTOML(tt.data)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %#v, want %#v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestParseTOMLErrors checks invalid TOML, including escapes Go would
+// accept, is rejected with the line at fault.
+func TestParseTOMLErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{"escaped single quote", `s = "\'"`, "line 1: invalid escaped character"},
+		{"hex escape", `s = "\x41"`, "line 1: invalid escaped character"},
+		{"octal escape", `s = "\101"`, "line 1: invalid escaped character"},
+		{"short unicode escape", "\ns = \"\\u00e\"", "line 2:"},
+		{"surrogate escape", `s = "\uD800"`, "line 1:"},
+		{"unicode escape out of range", `s = "\U00110000"`, "line 1:"},
+		{"escape in literal key", `s = '\'x'`, "line 1:"},
+		{"bare string", "\ntuning = drop-d", "line 2:"},
+		{"missing value", "\n\ntuning =", "line 3:"},
+		{"missing equals", "tuning", "line 1:"},
+		{"bad key", "my key = 1", "line 1:"},
+		{"unterminated string", "a = 1\ns = \"abc", "line 2:"},
+		{"unterminated array", "up = [\"w\",\n", "line 2:"},
+		{"text after value", "a = 1 2", "line 1:"},
+		{"malformed header", "[log", "line 1:"},
+		{"duplicate key", "a = 1\n[log]\nlevel = 1\nlevel = 2", "line 4: log.level is already set on line 3"},
+		{"duplicate dotted key", "log.level = 1\n[log]\nlevel = 2", "line 3: log.level is already set on line 1"},
+		{"duplicate table", "[log]\nlevel = 1\n[log]\npath = 'x'", "line 3: table [log] is already defined on line 1"},
+	}
+	for _, tt := range tests {
+		_, err := parseTOML(tt.data)
+		if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+			t.Errorf("%s: got error %v, want one starting %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package eartrain
+
+import (
+	"math/rand"
+
+	"github.com/paulgreig/guitar-training/internal/audio"
+	"github.com/paulgreig/guitar-training/internal/synth"
+	"github.com/paulgreig/guitar-training/internal/theory"
+)
+
+// Category is a kind of ear-training question.
+type Category int
+
+// Question categories.
+const (
+	Intervals Category = iota
+	Triads
+	Scales
+)
+
+// Categories lists every category in display order.
+var Categories = []Category{Intervals, Triads, Scales}
+
+// String returns the category's display name.
+func (c Category) String() string {
+	switch c {
+	case Triads:
+		return "Triads"
+	case Scales:
+		return "Scales"
+	default:
+		return "Intervals"
+	}
+}
+
+// ChoiceCount is how many options each question offers.
+const ChoiceCount = 4
+
+// Timing of the rendered audio, in seconds.
+const (
+	noteGap     = 0.6 // between melodic notes
+	ringTime    = 1.6 // how long each note sounds
+	chordStrum  = 0.03
+	harmonicGap = 0.9 // between the broken and the block form of a triad
+)
+
+// interval is an answer in the Intervals category.
+type interval struct {
+	name      string
+	semitones int
+}
+
+var intervalAnswers = []interval{
+	{"Minor 2nd", 1},
+	{"Major 2nd", 2},
+	{"Minor 3rd", 3},
+	{"Major 3rd", 4},
+	{"Perfect 4th", 5},
+	{"Tritone", 6},
+	{"Perfect 5th", 7},
+	{"Minor 6th", 8},
+	{"Major 6th", 9},
+	{"Minor 7th", 10},
+	{"Major 7th", 11},
+	{"Octave", 12},
+}
+
+// triadQualities are the chord qualities asked about in the Triads category.
+var triadQualities = []string{"major", "minor", "dim", "aug"}
+
+// scaleAnswers are the scale types asked about in the Scales category.
+var scaleAnswers = []string{
+	"Major", "Natural Minor", "Dorian", "Phrygian", "Lydian", "Mixolydian",
+	"Harmonic Minor", "Major Pentatonic", "Minor Pentatonic", "Blues",
+}
+
+// Question is one ear-training question. Steps are played in order; each
+// step's pitches sound together.
+type Question struct {
+	Category Category
+	Steps    [][]theory.Pitch
+	Answer   string
+	Choices  []string // ChoiceCount options including Answer, shuffled
+}
+
+// Correct reports whether choice is the right answer.
+func (q Question) Correct(choice int) bool {
+	return choice >= 0 && choice < len(q.Choices) && q.Choices[choice] == q.Answer
+}
+
+// Generator makes random questions.
+type Generator struct {
+	rng *rand.Rand
+}
+
+// NewGenerator returns a generator seeded with seed.
+func NewGenerator(seed int64) *Generator {
+	return &Generator{rng: rand.New(rand.NewSource(seed))}
+}
+
+// root picks a random starting pitch between E2 and E3, leaving room above
+// for an octave in the guitar's comfortable range.
+func (g *Generator) root() theory.Pitch {
+	return theory.MustParsePitch("E2").Transpose(g.rng.Intn(13))
+}
+
+// Next returns a new question in category c.
+func (g *Generator) Next(c Category) Question {
+	root := g.root()
+	var q Question
+	var pool []string
+	switch c {
+	case Triads:
+		quality := triadQualities[g.rng.Intn(len(triadQualities))]
+		cq, _ := theory.LookupChordQuality(quality)
+		var chord []theory.Pitch
+		for _, iv := range cq.Intervals {
+			p := root.Transpose(iv.Semitones)
+			chord = append(chord, p)
+			q.Steps = append(q.Steps, []theory.Pitch{p})
+		}
+		q.Steps = append(q.Steps, chord)
+		q.Answer = cq.Name
+		for _, name := range triadQualities {
+			cq, _ := theory.LookupChordQuality(name)
+			pool = append(pool, cq.Name)
+		}
+	case Scales:
+		name := scaleAnswers[g.rng.Intn(len(scaleAnswers))]
+		st, _ := theory.LookupScaleType(name)
+		for _, iv := range st.Intervals {
+			q.Steps = append(q.Steps, []theory.Pitch{root.Transpose(iv.Semitones)})
+		}
+		q.Steps = append(q.Steps, []theory.Pitch{root.Transpose(12)})
+		q.Answer = st.Name
+		for _, name := range scaleAnswers {
+			st, _ := theory.LookupScaleType(name)
+			pool = append(pool, st.Name)
+		}
+	default:
+		iv := intervalAnswers[g.rng.Intn(len(intervalAnswers))]
+		q.Steps = [][]theory.Pitch{{root}, {root.Transpose(iv.semitones)}}
+		q.Answer = iv.name
+		for _, a := range intervalAnswers {
+			pool = append(pool, a.name)
+		}
+	}
+	q.Category = c
+	q.Choices = g.choices(q.Answer, pool)
+	return q
+}
+
+// choices picks ChoiceCount-1 distractors from pool and shuffles in the answer.
+func (g *Generator) choices(answer string, pool []string) []string {
+	out := []string{answer}
+	for _, i := range g.rng.Perm(len(pool)) {
+		if len(out) == ChoiceCount {
+			break
+		}
+		if pool[i] != answer {
+			out = append(out, pool[i])
+		}
+	}
+	g.rng.Shuffle(len(out), func(i, j int) { out[i], out[j] = out[j], out[i] })
+	return out
+}
+
+// Render synthesises the question as plucked-string audio at the given rate.
+func Render(q Question, rate int) []float64 {
+	var out []float64
+	offset := 0.0
+	for i, step := range q.Steps {
+		if len(step) > 1 && i > 0 {
+			offset += harmonicGap - noteGap
+		}
+		for j, p := range step {
+			note := synth.Pluck(p.Frequency(theory.StandardA4), ringTime, rate)
+			for k := range note {
+				note[k] *= 0.5
+			}
+			start := offset + float64(j)*chordStrum
+			out = audio.Mix(out, note, int(start*float64(rate)))
+		}
+		offset += noteGap
+	}
+	audio.Normalize(out, 0.8)
+	return out
+}
@@ -0,0 +1,146 @@
+package eartrain
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/paulgreig/guitar-training/internal/pitch"
+	"github.com/paulgreig/guitar-training/internal/theory"
+)
+
+func TestNext(t *testing.T) {
+	tests := []struct {
+		category Category
+		check    func(q Question) bool // the steps sound the answer
+	}{
+		{Intervals, func(q Question) bool {
+			if len(q.Steps) != 2 {
+				return false
+			}
+			i := slices.IndexFunc(intervalAnswers, func(iv interval) bool { return iv.name == q.Answer })
+			return i >= 0 && int(q.Steps[1][0]-q.Steps[0][0]) == intervalAnswers[i].semitones
+		}},
+		{Triads, func(q Question) bool {
+			if len(q.Steps) != 4 {
+				return false
+			}
+			chord := q.Steps[3]
+			for i, step := range q.Steps[:3] {
+				if len(step) != 1 || step[0] != chord[i] {
+					return false
+				}
+			}
+			var names []string
+			for _, name := range triadQualities {
+				cq, _ := theory.LookupChordQuality(name)
+				names = append(names, cq.Name)
+			}
+			return slices.Contains(names, q.Answer)
+		}},
+		{Scales, func(q Question) bool {
+			st, ok := theory.LookupScaleType(q.Answer)
+			if !ok || len(q.Steps) != len(st.Intervals)+1 {
+				return false
+			}
+			root := q.Steps[0][0]
+			for i, iv := range st.Intervals {
+				if q.Steps[i][0] != root.Transpose(iv.Semitones) {
+					return false
+				}
+			}
+			return q.Steps[len(q.Steps)-1][0] == root.Transpose(12)
+		}},
+	}
+	low, high := theory.MustParsePitch("E2"), theory.MustParsePitch("E3")
+	for _, tt := range tests {
+		g := NewGenerator(1)
+		for range 50 {
+			q := g.Next(tt.category)
+			if q.Category != tt.category {
+				t.Errorf("%s: question in %s", tt.category, q.Category)
+			}
+			if root := q.Steps[0][0]; root < low || root > high {
+				t.Errorf("%s: root %s outside E2-E3", tt.category, root)
+			}
+			if !tt.check(q) {
+				t.Errorf("%s: steps %v don't sound %q", tt.category, q.Steps, q.Answer)
+			}
+			if len(q.Choices) != ChoiceCount {
+				t.Errorf("%s: %d choices, want %d", tt.category, len(q.Choices), ChoiceCount)
+			}
+			seen := map[string]bool{}
+			right := 0
+			for i, c := range q.Choices {
+				if seen[c] {
+					t.Errorf("%s: choice %q offered twice", tt.category, c)
+				}
+				seen[c] = true
+				if q.Correct(i) {
+					right++
+				}
+			}
+			if right != 1 || !seen[q.Answer] {
+				t.Errorf("%s: choices %v for %q have %d right answers", tt.category, q.Choices, q.Answer, right)
+			}
+		}
+	}
+}
+
+func TestNextIsSeeded(t *testing.T) {
+	a, b := NewGenerator(42), NewGenerator(42)
+	for _, c := range Categories {
+		qa, qb := a.Next(c), b.Next(c)
+		if qa.Answer != qb.Answer || !slices.Equal(qa.Choices, qb.Choices) {
+			t.Errorf("%s: same seed gave %q %v and %q %v", c, qa.Answer, qa.Choices, qb.Answer, qb.Choices)
+		}
+	}
+}
+
+func TestCorrect(t *testing.T) {
+	q := Question{Answer: "Octave", Choices: []string{"Tritone", "Octave", "Major 3rd", "Minor 2nd"}}
+	for choice, want := range map[int]bool{-1: false, 0: false, 1: true, 3: false, 4: false} {
+		if got := q.Correct(choice); got != want {
+			t.Errorf("Correct(%d) = %v, want %v", choice, got, want)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	const rate = 44100
+	e2 := theory.MustParsePitch("E2")
+	tests := []struct {
+		name    string
+		steps   [][]theory.Pitch
+		seconds float64
+	}{
+		{"interval", [][]theory.Pitch{{e2}, {e2.Transpose(7)}}, noteGap + ringTime},
+		{"triad", [][]theory.Pitch{{e2}, {e2.Transpose(4)}, {e2.Transpose(7)}, {e2, e2.Transpose(4), e2.Transpose(7)}},
+			2*noteGap + harmonicGap + 2*chordStrum + ringTime},
+	}
+	for _, tt := range tests {
+		out := Render(Question{Steps: tt.steps}, rate)
+		if want := int(tt.seconds * rate); abs(len(out)-want) > 1 {
+			t.Errorf("%s: %d samples, want %d", tt.name, len(out), want)
+		}
+		peak := 0.0
+		for _, s := range out {
+			peak = max(peak, s, -s)
+		}
+		if peak < 0.79 || peak > 0.81 {
+			t.Errorf("%s: peak %.3f, want 0.8", tt.name, peak)
+		}
+		// The first note sounds alone until the second starts.
+		frame := out[rate/10 : rate/10+pitch.FrameSize(rate)]
+		est, ok := pitch.NewDetector(rate, theory.StandardA4).Detect(frame)
+		if !ok || est.Pitch != e2 {
+			t.Errorf("%s: first note heard as %s (ok=%v), want %s", tt.name, est.Pitch, ok, e2)
+		}
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
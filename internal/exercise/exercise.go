@@ -0,0 +1,118 @@
+package exercise
+
+import (
+	"github.com/paulgreig/guitar-training/internal/fretboard"
+	"github.com/paulgreig/guitar-training/internal/theory"
+)
+
+// Pattern turns n notes, ordered low to high, into the order they are played
+// as indices into that ordering.
+type Pattern struct {
+	Name  string
+	Order func(n int) []int
+}
+
+// Patterns lists the built-in scale-run patterns.
+var Patterns = []Pattern{
+	{"Ascending", ascending},
+	{"Descending", descending},
+	{"Up and down", upAndDown},
+	{"Thirds", intervalPairs(2)},
+	{"Fourths", intervalPairs(3)},
+	{"Groups of 3", groups(3)},
+	{"Groups of 4", groups(4)},
+}
+
+// LookupPattern finds a pattern by name, case-sensitively.
+func LookupPattern(name string) (Pattern, bool) {
+	for _, p := range Patterns {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return Pattern{}, false
+}
+
+func ascending(n int) []int {
+	out := make([]int, n)
+	for i := range out {
+		out[i] = i
+	}
+	return out
+}
+
+func descending(n int) []int {
+	out := make([]int, n)
+	for i := range out {
+		out[i] = n - 1 - i
+	}
+	return out
+}
+
+func upAndDown(n int) []int {
+	if n == 0 {
+		return nil
+	}
+	return append(ascending(n), descending(n)[1:]...)
+}
+
+// intervalPairs plays each note followed by the note step scale degrees
+// above it, going up, then mirrors the figure coming down.
+func intervalPairs(step int) func(n int) []int {
+	return func(n int) []int {
+		var out []int
+		for i := 0; i+step < n; i++ {
+			out = append(out, i, i+step)
+		}
+		for i := n - 1; i-step >= 0; i-- {
+			out = append(out, i, i-step)
+		}
+		return out
+	}
+}
+
+// groups plays runs of size consecutive notes starting on each degree going
+// up, then the mirrored runs coming down.
+func groups(size int) func(n int) []int {
+	return func(n int) []int {
+		var out []int
+		for i := 0; i+size <= n; i++ {
+			for j := 0; j < size; j++ {
+				out = append(out, i+j)
+			}
+		}
+		for i := n - 1; i-size+1 >= 0; i-- {
+			for j := 0; j < size; j++ {
+				out = append(out, i-j)
+			}
+		}
+		return out
+	}
+}
+
+// Note is one step of an exercise: where it is played and what it sounds.
+type Note struct {
+	Location fretboard.Location
+	Pitch    theory.Pitch
+}
+
+// Run orders the locations by pitch, keeps one location per pitch and lays
+// them out using the pattern.
+func Run(t fretboard.Tuning, locs []fretboard.Location, p Pattern) []Note {
+	sorted := append([]fretboard.Location(nil), locs...)
+	t.SortByPitch(sorted)
+	var notes []Note
+	for _, l := range sorted {
+		pitch := t.PitchAt(l.String, l.Fret)
+		if len(notes) > 0 && notes[len(notes)-1].Pitch == pitch {
+			continue
+		}
+		notes = append(notes, Note{Location: l, Pitch: pitch})
+	}
+	order := p.Order(len(notes))
+	out := make([]Note, len(order))
+	for i, idx := range order {
+		out[i] = notes[idx]
+	}
+	return out
+}
@@ -0,0 +1,136 @@
+package exercise
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/paulgreig/guitar-training/internal/fretboard"
+)
+
+func TestPatterns(t *testing.T) {
+	tests := []struct {
+		name string
+		n    int
+		want []int
+	}{
+		{"Ascending", 4, []int{0, 1, 2, 3}},
+		{"Ascending", 1, []int{0}},
+		{"Ascending", 0, []int{}},
+		{"Descending", 4, []int{3, 2, 1, 0}},
+		{"Descending", 1, []int{0}},
+		{"Descending", 0, []int{}},
+		{"Up and down", 4, []int{0, 1, 2, 3, 2, 1, 0}},
+		{"Up and down", 1, []int{0}},
+		{"Up and down", 0, nil},
+		{"Thirds", 4, []int{0, 2, 1, 3, 3, 1, 2, 0}},
+		{"Thirds", 5, []int{0, 2, 1, 3, 2, 4, 4, 2, 3, 1, 2, 0}},
+		{"Thirds", 2, nil},
+		{"Fourths", 4, []int{0, 3, 3, 0}},
+		{"Fourths", 5, []int{0, 3, 1, 4, 4, 1, 3, 0}},
+		{"Fourths", 3, nil},
+		{"Groups of 3", 4, []int{0, 1, 2, 1, 2, 3, 3, 2, 1, 2, 1, 0}},
+		{"Groups of 3", 3, []int{0, 1, 2, 2, 1, 0}},
+		{"Groups of 3", 2, nil},
+		{"Groups of 4", 5, []int{0, 1, 2, 3, 1, 2, 3, 4, 4, 3, 2, 1, 3, 2, 1, 0}},
+		{"Groups of 4", 3, nil},
+	}
+	for _, tt := range tests {
+		p, ok := LookupPattern(tt.name)
+		if !ok {
+			t.Errorf("LookupPattern(%q): not found", tt.name)
+			continue
+		}
+		if got := p.Order(tt.n); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s of %d = %v, want %v", tt.name, tt.n, got, tt.want)
+		}
+	}
+}
+
+// TestPatternsInRange checks every pattern plays only notes it was given and
+// plays each of them, for a typical two-octave scale.
+func TestPatternsInRange(t *testing.T) {
+	const n = 15
+	for _, p := range Patterns {
+		played := map[int]bool{}
+		for _, i := range p.Order(n) {
+			if i < 0 || i >= n {
+				t.Errorf("%s: index %d out of range for %d notes", p.Name, i, n)
+			}
+			played[i] = true
+		}
+		if len(played) != n {
+			t.Errorf("%s: plays %d of %d notes", p.Name, len(played), n)
+		}
+	}
+}
+
+func TestLookupPattern(t *testing.T) {
+	for _, p := range Patterns {
+		if got, ok := LookupPattern(p.Name); !ok || got.Name != p.Name {
+			t.Errorf("LookupPattern(%q) = %q, %v", p.Name, got.Name, ok)
+		}
+	}
+	for _, name := range []string{"thirds", "Sixths", ""} {
+		if _, ok := LookupPattern(name); ok {
+			t.Errorf("LookupPattern(%q) found a pattern", name)
+		}
+	}
+}
+
+// TestRun checks a run sorts by pitch and plays a pitch found on two
+// strings only once.
+func TestRun(t *testing.T) {
+	locs := []fretboard.Location{
+		{String: 1, Fret: 0}, // A2
+		{String: 0, Fret: 5}, // A2 again
+		{String: 0, Fret: 3}, // G2
+		{String: 1, Fret: 2}, // B2
+	}
+	up, _ := LookupPattern("Up and down")
+	run := Run(fretboard.Standard, locs, up)
+	var got []string
+	for _, n := range run {
+		got = append(got, n.Pitch.String())
+	}
+	if want := []string{"G2", "A2", "B2", "A2", "G2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("run = %v, want %v", got, want)
+	}
+	for _, n := range run {
+		if fretboard.Standard.PitchAt(n.Location.String, n.Location.Fret) != n.Pitch {
+			t.Errorf("%s is not at string %d fret %d", n.Pitch, n.Location.String, n.Location.Fret)
+		}
+	}
+}
+
+// TestRunPatterns checks a run plays the notes in the pattern's order.
+func TestRunPatterns(t *testing.T) {
+	locs := []fretboard.Location{
+		{String: 0, Fret: 5}, // A2
+		{String: 0, Fret: 8}, // C3
+		{String: 1, Fret: 5}, // D3
+		{String: 1, Fret: 7}, // E3
+	}
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{"Ascending", []string{"A2", "C3", "D3", "E3"}},
+		{"Descending", []string{"E3", "D3", "C3", "A2"}},
+		{"Thirds", []string{"A2", "D3", "C3", "E3", "E3", "C3", "D3", "A2"}},
+		{"Groups of 3", []string{"A2", "C3", "D3", "C3", "D3", "E3", "E3", "D3", "C3", "D3", "C3", "A2"}},
+	}
+	for _, tt := range tests {
+		p, _ := LookupPattern(tt.pattern)
+		var got []string
+		for _, n := range Run(fretboard.Standard, locs, p) {
+			got = append(got, n.Pitch.String())
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: run = %v, want %v", tt.pattern, got, tt.want)
+		}
+	}
+	up, _ := LookupPattern("Ascending")
+	if run := Run(fretboard.Standard, nil, up); len(run) != 0 {
+		t.Errorf("a run of no locations = %v", run)
+	}
+}
@@ -0,0 +1,163 @@
+package exercise
+
+import (
+	"time"
+
+	"github.com/paulgreig/guitar-training/internal/theory"
+)
+
+// Verdict is how a note of a run was played.
+type Verdict int
+
+// Verdicts, from not yet judged to not played at all.
+const (
+	Pending Verdict = iota
+	Correct
+	Late
+	Wrong
+	Missed
+)
+
+// Symbol returns a one-character marker for the verdict.
+func (v Verdict) Symbol() string {
+	switch v {
+	case Correct:
+		return "✓"
+	case Late:
+		return "~"
+	case Wrong:
+		return "✗"
+	case Missed:
+		return "–"
+	default:
+		return "·"
+	}
+}
+
+// DefaultTolerance is how far into a beat, as a fraction of the beat, a
+// note still counts as on time.
+const DefaultTolerance = 0.35
+
+// Score counts the verdicts of every judged note.
+type Score struct {
+	Correct, Late, Wrong, Missed int
+}
+
+// Judged is the number of notes with a verdict.
+func (s Score) Judged() int {
+	return s.Correct + s.Late + s.Wrong + s.Missed
+}
+
+// Percent is the share of judged notes played right, with late notes counting half.
+func (s Score) Percent() int {
+	if s.Judged() == 0 {
+		return 0
+	}
+	return (200*s.Correct + 100*s.Late) / (2 * s.Judged())
+}
+
+func (s *Score) add(v Verdict, delta int) {
+	switch v {
+	case Correct:
+		s.Correct += delta
+	case Late:
+		s.Late += delta
+	case Wrong:
+		s.Wrong += delta
+	case Missed:
+		s.Missed += delta
+	}
+}
+
+// Judge compares played notes with a run as it is played step by step. The
+// caller moves it along with Advance each time the run moves to a new note.
+type Judge struct {
+	Notes     []Note
+	Verdicts  []Verdict // for the current lap
+	Score     Score     // over every lap
+	Tolerance float64   // fraction of a beat; DefaultTolerance when zero
+
+	step      int
+	stepStart time.Time
+	beat      time.Duration
+	started   bool
+	early     bool // the next note was played just before its step
+}
+
+// NewJudge returns a judge for run.
+func NewJudge(run []Note) Judge {
+	return Judge{Notes: run, Verdicts: make([]Verdict, len(run))}
+}
+
+// Advance moves to step, due at time at, with beat until the next step.
+// The previous note is marked Missed if nothing was played for it, and
+// starting a new lap clears the lap's verdicts.
+func (j *Judge) Advance(step int, at time.Time, beat time.Duration) {
+	if j.started && j.Verdicts[j.step] == Pending {
+		j.set(j.step, Missed)
+	}
+	if step == 0 && j.started {
+		j.Verdicts = make([]Verdict, len(j.Notes))
+	}
+	j.step, j.stepStart, j.beat, j.started = step, at, beat, true
+	if j.early {
+		j.set(step, Correct)
+		j.early = false
+	}
+}
+
+// Retime restarts the timing of the current step from at, e.g. after the
+// run was paused or its tempo changed.
+func (j *Judge) Retime(at time.Time, beat time.Duration) {
+	j.stepStart, j.beat = at, beat
+}
+
+// Play judges a note played at time at and returns the verdict it earned,
+// or Pending when it was ignored (the note had already been judged).
+// The expected note earns Correct within the tolerance and Late after it.
+// The previous note played just after the step moved on counts as Late, and
+// the next note played just before its step as Correct. Anything else is
+// Wrong.
+func (j *Judge) Play(p theory.Pitch, at time.Time) Verdict {
+	if !j.started || len(j.Notes) == 0 {
+		return Pending
+	}
+	window := time.Duration(j.tolerance() * float64(j.beat))
+	into := at.Sub(j.stepStart)
+	if j.step > 0 && j.Verdicts[j.step-1] == Missed && into <= window && p == j.Notes[j.step-1].Pitch {
+		j.set(j.step-1, Late)
+		return Late
+	}
+	if j.Verdicts[j.step] == Pending && p == j.Notes[j.step].Pitch {
+		if into <= window {
+			j.set(j.step, Correct)
+		} else {
+			j.set(j.step, Late)
+		}
+		return j.Verdicts[j.step]
+	}
+	next := (j.step + 1) % len(j.Notes)
+	if !j.early && j.beat-into <= window && p == j.Notes[next].Pitch {
+		j.early = true
+		return Correct
+	}
+	if j.Verdicts[j.step] == Pending {
+		j.set(j.step, Wrong)
+		return Wrong
+	}
+	return Pending
+}
+
+// set records a verdict, replacing any earlier one in the score.
+func (j *Judge) set(step int, v Verdict) {
+	j.Score.add(j.Verdicts[step], -1)
+	j.Verdicts[step] = v
+	j.Score.add(v, 1)
+}
+
+func (j *Judge) tolerance() float64 {
+	if j.Tolerance <= 0 {
+		return DefaultTolerance
+	}
+	return j.Tolerance
+}
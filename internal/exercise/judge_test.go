@@ -0,0 +1,113 @@
+package exercise
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/paulgreig/guitar-training/internal/theory"
+)
+
+// play is a note played ms milliseconds after the start of a step.
+type play struct {
+	pitch string
+	ms    int
+}
+
+// TestJudge plays three steps of a C D E run at 120 BPM, where a note
+// counts as on time within 175ms of its step.
+func TestJudge(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps [][]play // notes played during each step
+		want  []Verdict
+		score Score
+	}{
+		{"all on time", [][]play{{{"C4", 0}}, {{"D4", 100}}, {{"E4", 170}}},
+			[]Verdict{Correct, Correct, Correct}, Score{Correct: 3}},
+		{"late", [][]play{{{"C4", 200}}, {{"D4", 400}}, {{"E4", 0}}},
+			[]Verdict{Late, Late, Correct}, Score{Correct: 1, Late: 2}},
+		{"wrong note", [][]play{{{"C#4", 0}}, {{"D4", 0}}, {{"F4", 0}, {"E4", 50}}},
+			[]Verdict{Wrong, Correct, Wrong}, Score{Correct: 1, Wrong: 2}},
+		{"missed", [][]play{{}, {{"D4", 0}}, {}},
+			[]Verdict{Missed, Correct, Pending}, Score{Correct: 1, Missed: 1}},
+		{"previous note just after the step", [][]play{{}, {{"C4", 100}, {"D4", 120}}, {{"E4", 0}}},
+			[]Verdict{Late, Correct, Correct}, Score{Correct: 2, Late: 1}},
+		{"next note just before its step", [][]play{{{"C4", 0}, {"D4", 400}}, {}, {{"E4", 0}}},
+			[]Verdict{Correct, Correct, Correct}, Score{Correct: 3}},
+		{"next note too early", [][]play{{{"C4", 0}, {"D4", 200}}, {{"D4", 0}}, {}},
+			[]Verdict{Correct, Correct, Pending}, Score{Correct: 2}},
+		{"repeated note ignored", [][]play{{{"C4", 0}, {"C4", 50}, {"G4", 60}}, {}, {}},
+			[]Verdict{Correct, Missed, Pending}, Score{Correct: 1, Missed: 1}},
+	}
+	run := []Note{
+		{Pitch: theory.MustParsePitch("C4")},
+		{Pitch: theory.MustParsePitch("D4")},
+		{Pitch: theory.MustParsePitch("E4")},
+	}
+	beat := 500 * time.Millisecond
+	for _, tt := range tests {
+		j := NewJudge(run)
+		start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		for step, plays := range tt.steps {
+			at := start.Add(time.Duration(step) * beat)
+			j.Advance(step, at, beat)
+			for _, p := range plays {
+				j.Play(theory.MustParsePitch(p.pitch), at.Add(time.Duration(p.ms)*time.Millisecond))
+			}
+		}
+		if !reflect.DeepEqual(j.Verdicts, tt.want) {
+			t.Errorf("%s: verdicts %v, want %v", tt.name, j.Verdicts, tt.want)
+		}
+		if j.Score != tt.score {
+			t.Errorf("%s: score %+v, want %+v", tt.name, j.Score, tt.score)
+		}
+	}
+}
+
+func TestJudgeLaps(t *testing.T) {
+	run := []Note{{Pitch: theory.MustParsePitch("A3")}, {Pitch: theory.MustParsePitch("C4")}}
+	j := NewJudge(run)
+	at, beat := time.Now(), time.Second
+	for lap := 0; lap < 2; lap++ {
+		for step, n := range run {
+			j.Advance(step, at, beat)
+			if lap == 0 || step == 0 {
+				j.Play(n.Pitch, at)
+			}
+			at = at.Add(beat)
+		}
+	}
+	j.Advance(0, at, beat)
+	if want := []Verdict{Pending, Pending}; !reflect.DeepEqual(j.Verdicts, want) {
+		t.Errorf("new lap: verdicts %v, want %v", j.Verdicts, want)
+	}
+	if want := (Score{Correct: 3, Missed: 1}); j.Score != want {
+		t.Errorf("score over laps %+v, want %+v", j.Score, want)
+	}
+}
+
+func TestJudgeNotStarted(t *testing.T) {
+	j := NewJudge([]Note{{Pitch: 60}})
+	if v := j.Play(60, time.Now()); v != Pending {
+		t.Errorf("before Advance: got %v, want Pending", v)
+	}
+}
+
+func TestScorePercent(t *testing.T) {
+	tests := []struct {
+		s    Score
+		want int
+	}{
+		{Score{}, 0},
+		{Score{Correct: 4}, 100},
+		{Score{Correct: 1, Late: 1}, 75},
+		{Score{Correct: 1, Wrong: 1, Missed: 2}, 25},
+		{Score{Late: 1, Wrong: 1}, 25},
+	}
+	for _, tt := range tests {
+		if got := tt.s.Percent(); got != tt.want {
+			t.Errorf("%+v.Percent() = %d, want %d", tt.s, got, tt.want)
+		}
+	}
+}
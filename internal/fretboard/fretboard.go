@@ -0,0 +1,104 @@
+package fretboard
+
+import (
+	"math"
+	"sort"
+
+	"github.com/paulgreig/guitar-training/internal/theory"
+)
+
+// DefaultFrets is the number of frets (after the open string) shown by default.
+const DefaultFrets = 12
+
+// Tuning is the open pitch of each string, ordered from the lowest-pitched
+// string (index 0) to the highest.
+type Tuning struct {
+	ID          string
+	Name        string
+	Strings     []theory.Pitch
+	PreferFlats bool // spell string names with flats (Eb rather than D#)
+}
+
+// Location is a single (string, fret) cell on the neck. Fret 0 is the open string.
+type Location struct {
+	String int
+	Fret   int
+}
+
+// PitchAt returns the pitch sounded at the given string and fret.
+func (t Tuning) PitchAt(str, fret int) theory.Pitch {
+	return t.Strings[str].Transpose(fret)
+}
+
+// NearestString returns the string whose open pitch is closest to freq
+// (with A4 at a4 Hz), and how many cents freq is above it. It is the string
+// someone tuning up is most likely playing.
+func (t Tuning) NearestString(freq, a4 float64) (str int, cents float64) {
+	best := math.Inf(1)
+	for i, p := range t.Strings {
+		c := 1200 * math.Log2(freq/p.Frequency(a4))
+		if math.Abs(c) < math.Abs(best) {
+			str, best = i, c
+		}
+	}
+	return str, best
+}
+
+// StringLabels returns one label per string, low to high, using the open
+// note name. The top string is lower-cased when it shares a name with the
+// bottom string, following the usual tab convention (E A D G B e).
+func (t Tuning) StringLabels() []string {
+	labels := make([]string, len(t.Strings))
+	for i, p := range t.Strings {
+		labels[i] = theory.SpellPitchClass(p.PitchClass(), t.PreferFlats).String()
+	}
+	if n := len(labels); n > 1 && labels[0] == labels[n-1] {
+		b := []byte(labels[n-1])
+		b[0] += 'a' - 'A'
+		labels[n-1] = string(b)
+	}
+	return labels
+}
+
+// Find returns every location from fret 0 to maxFret whose pitch class is in
+// pcs, ordered by string then fret.
+func (t Tuning) Find(pcs []theory.PitchClass, maxFret int) []Location {
+	want := make(map[theory.PitchClass]bool, len(pcs))
+	for _, pc := range pcs {
+		want[pc] = true
+	}
+	var out []Location
+	for s := range t.Strings {
+		for f := 0; f <= maxFret; f++ {
+			if want[t.PitchAt(s, f).PitchClass()] {
+				out = append(out, Location{String: s, Fret: f})
+			}
+		}
+	}
+	return out
+}
+
+// SortByPitch orders locations from lowest to highest sounding pitch,
+// breaking ties by string.
+func (t Tuning) SortByPitch(locs []Location) {
+	sort.SliceStable(locs, func(i, j int) bool {
+		pi, pj := t.PitchAt(locs[i].String, locs[i].Fret), t.PitchAt(locs[j].String, locs[j].Fret)
+		if pi != pj {
+			return pi < pj
+		}
+		return locs[i].String < locs[j].String
+	})
+}
+
+// PitchClasses parses note names into pitch classes, skipping any it cannot parse.
+func PitchClasses(names []string) []theory.PitchClass {
+	out := make([]theory.PitchClass, 0, len(names))
+	for _, name := range names {
+		n, err := theory.ParseNote(name)
+		if err != nil {
+			continue
+		}
+		out = append(out, n.PitchClass())
+	}
+	return out
+}
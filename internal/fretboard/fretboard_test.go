@@ -0,0 +1,96 @@
+package fretboard
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/paulgreig/guitar-training/internal/theory"
+)
+
+// TestNearestString checks the tuner picks the string being tuned, even
+// when it is well out of tune.
+func TestNearestString(t *testing.T) {
+	tests := []struct {
+		tuning    string
+		freq, a4  float64
+		want      int
+		wantCents float64
+	}{
+		{"standard", 82.41, 440, 0, 0},
+		{"standard", 110 * math.Pow(2, -40.0/1200), 440, 1, -40},
+		{"standard", 246.94 * math.Pow(2, 25.0/1200), 440, 4, 25},
+		{"standard", 329.63, 440, 5, 0},
+		{"standard", 442, 442, 5, 500},
+		{"drop-d", 73.42, 440, 0, 0},
+		{"drop-d", 82.41, 440, 0, 200},
+	}
+	for _, tt := range tests {
+		tuning, ok := LookupTuning(tt.tuning)
+		if !ok {
+			t.Fatalf("no tuning %q", tt.tuning)
+		}
+		str, cents := tuning.NearestString(tt.freq, tt.a4)
+		if str != tt.want || math.Abs(cents-tt.wantCents) > 1 {
+			t.Errorf("%s %.2f Hz (A4=%v): string %d %+.1f cents, want %d %+.1f",
+				tt.tuning, tt.freq, tt.a4, str, cents, tt.want, tt.wantCents)
+		}
+	}
+}
+
+func TestStringLabels(t *testing.T) {
+	dropD := Tuning{Name: "Drop D", Strings: append([]theory.Pitch{theory.MustParsePitch("D2")}, Standard.Strings[1:]...)}
+	tests := []struct {
+		tuning Tuning
+		want   []string
+	}{
+		{Standard, []string{"E", "A", "D", "G", "B", "e"}},
+		{dropD, []string{"D", "A", "D", "G", "B", "E"}},
+	}
+	for _, tt := range tests {
+		if got := tt.tuning.StringLabels(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: labels %v, want %v", tt.tuning.Name, got, tt.want)
+		}
+	}
+}
+
+func TestPitchAt(t *testing.T) {
+	tests := []struct {
+		str, fret int
+		want      string
+	}{
+		{0, 0, "E2"}, {0, 5, "A2"}, {2, 7, "A3"}, {5, 12, "E5"}, {4, 1, "C4"},
+	}
+	for _, tt := range tests {
+		if got := Standard.PitchAt(tt.str, tt.fret); got != theory.MustParsePitch(tt.want) {
+			t.Errorf("PitchAt(%d, %d) = %s, want %s", tt.str, tt.fret, got, tt.want)
+		}
+	}
+}
+
+func TestFind(t *testing.T) {
+	a := theory.MustParseNote("A").PitchClass()
+	want := []Location{{0, 5}, {1, 0}, {3, 2}, {5, 5}}
+	if got := Standard.Find([]theory.PitchClass{a}, 5); !reflect.DeepEqual(got, want) {
+		t.Errorf("Find(A, 5) = %v, want %v", got, want)
+	}
+	if got := Standard.Find(nil, 12); len(got) != 0 {
+		t.Errorf("Find(nothing) = %v", got)
+	}
+}
+
+func TestSortByPitch(t *testing.T) {
+	// E4, E2, E3 on the A string and E3 on the D string.
+	locs := []Location{{5, 0}, {0, 0}, {2, 2}, {1, 7}}
+	Standard.SortByPitch(locs)
+	if want := []Location{{0, 0}, {1, 7}, {2, 2}, {5, 0}}; !reflect.DeepEqual(locs, want) {
+		t.Errorf("sorted %v, want %v", locs, want)
+	}
+}
+
+func TestPitchClasses(t *testing.T) {
+	got := PitchClasses([]string{"C", "H", "F#", "Gb"})
+	if want := []theory.PitchClass{0, 6, 6}; !reflect.DeepEqual(got, want) {
+		t.Errorf("PitchClasses = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,218 @@
+package fretboard
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/paulgreig/guitar-training/internal/theory"
+)
+
+// Shape is a named playable position of a scale: a subset of the neck that
+// covers the scale across all strings within reach of one hand position.
+type Shape struct {
+	Name      string
+	Locations []Location
+}
+
+// FretRange returns the lowest and highest fret used by the shape.
+func (s Shape) FretRange() (lo, hi int) {
+	if len(s.Locations) == 0 {
+		return 0, 0
+	}
+	lo, hi = s.Locations[0].Fret, s.Locations[0].Fret
+	for _, l := range s.Locations[1:] {
+		if l.Fret < lo {
+			lo = l.Fret
+		}
+		if l.Fret > hi {
+			hi = l.Fret
+		}
+	}
+	return lo, hi
+}
+
+// cagedWindowWidth is the number of frets covered by one CAGED position
+// before a stretch is needed.
+const cagedWindowWidth = 4
+
+// maxWindowWidth is the widest a CAGED window grows to on tunings where the
+// usual four frets leave a note with nowhere to go.
+const maxWindowWidth = 6
+
+// cagedOffsets places each CAGED window relative to the parent major root on
+// the lowest string, in the usual C-A-G-E-D order up the neck.
+var cagedOffsets = []struct {
+	name   string
+	offset int
+}{
+	{"C shape", 4},
+	{"A shape", 6},
+	{"G shape", 8},
+	{"E shape", 11},
+	{"D shape", 1},
+}
+
+// Shapes splits a scale into its standard playable shapes for the tuning:
+// five pentatonic boxes for five-note scales; five CAGED positions followed
+// by seven three-notes-per-string patterns for seven-note scales; and the
+// five CAGED windows for anything else. notes must start with the root.
+func (t Tuning) Shapes(notes []theory.PitchClass) []Shape {
+	if len(notes) == 0 || len(t.Strings) == 0 {
+		return nil
+	}
+	switch len(notes) {
+	case 5:
+		return t.notesPerString(notes, 2, "Box")
+	case 7:
+		return append(t.caged(notes), t.notesPerString(notes, 3, "3NPS pattern")...)
+	default:
+		return t.caged(notes)
+	}
+}
+
+// notesPerString builds one shape per scale degree, each starting on that
+// degree on the lowest string and taking exactly perString consecutive scale
+// notes on every string.
+func (t Tuning) notesPerString(notes []theory.PitchClass, perString int, label string) []Shape {
+	shapes := make([]Shape, 0, len(notes))
+	for d, pc := range notes {
+		p := t.Strings[0].Transpose(int(pc.Add(-int(t.Strings[0].PitchClass()))))
+		locs := t.layoutFixed(notes, p, perString)
+		if minFret(locs) < 0 {
+			locs = t.layoutFixed(notes, p.Transpose(12), perString)
+		}
+		shapes = append(shapes, Shape{Name: fmt.Sprintf("%s %d", label, d+1), Locations: locs})
+	}
+	return shapes
+}
+
+func (t Tuning) layoutFixed(notes []theory.PitchClass, p theory.Pitch, perString int) []Location {
+	var locs []Location
+	for s, open := range t.Strings {
+		for i := 0; i < perString; i++ {
+			locs = append(locs, Location{String: s, Fret: int(p - open)})
+			p = nextInScale(notes, p)
+		}
+	}
+	return locs
+}
+
+// caged builds the five CAGED positions, anchored on the major key the scale
+// belongs to (so A minor and E Dorian use the C and D major shapes).
+func (t Tuning) caged(notes []theory.PitchClass) []Shape {
+	parent := parentMajor(notes)
+	rootFret := int(parent.Add(-int(t.Strings[0].PitchClass())))
+	shapes := make([]Shape, 0, len(cagedOffsets))
+	for _, c := range cagedOffsets {
+		lo := (rootFret + c.offset) % 12
+		locs, ok := t.layoutWindow(notes, lo, lo+cagedWindowWidth-1)
+		for width := cagedWindowWidth + 1; !ok && width <= maxWindowWidth; width++ {
+			locs, ok = t.layoutWindow(notes, lo, lo+width-1)
+		}
+		shapes = append(shapes, Shape{Name: c.name, Locations: locs})
+	}
+	sort.SliceStable(shapes, func(i, j int) bool {
+		li, _ := shapes[i].FretRange()
+		lj, _ := shapes[j].FretRange()
+		return li < lj
+	})
+	return shapes
+}
+
+// layoutWindow walks the scale upwards from the lowest string, keeping notes
+// inside frets lo..hi. A note one fret past hi is kept on the current string
+// (a stretch) when moving it to the next string would fall below lo. ok is
+// false when a note fits on neither string, and the shape ends before it.
+func (t Tuning) layoutWindow(notes []theory.PitchClass, lo, hi int) (locs []Location, ok bool) {
+	open := t.Strings[0]
+	p := open.Transpose(lo)
+	for !inScale(notes, p) {
+		p++
+	}
+	last := len(t.Strings) - 1
+	for s := 0; s <= last; {
+		f := int(p - t.Strings[s])
+		if f > hi {
+			stretch := f == hi+1
+			switch {
+			case s == last:
+				if !stretch {
+					return locs, true
+				}
+			case int(p-t.Strings[s+1]) >= lo:
+				s++
+				continue
+			case !stretch:
+				return locs, false
+			}
+		}
+		locs = append(locs, Location{String: s, Fret: f})
+		p = nextInScale(notes, p)
+	}
+	return locs, true
+}
+
+// parentMajor returns the root of the major scale containing every note,
+// preferring the scale root itself and then its relative major. Scales that
+// fit no major key (harmonic minor, blues) use the root when they have a
+// major third and the relative major otherwise.
+func parentMajor(notes []theory.PitchClass) theory.PitchClass {
+	root := notes[0]
+	major := []int{0, 2, 4, 5, 7, 9, 11}
+	fits := func(k theory.PitchClass) bool {
+		set := make(map[theory.PitchClass]bool, 7)
+		for _, iv := range major {
+			set[k.Add(iv)] = true
+		}
+		for _, n := range notes {
+			if !set[n] {
+				return false
+			}
+		}
+		return true
+	}
+	candidates := []int{0, 3}
+	for i := 1; i < 12; i++ {
+		if i != 3 {
+			candidates = append(candidates, i)
+		}
+	}
+	for _, c := range candidates {
+		if fits(root.Add(c)) {
+			return root.Add(c)
+		}
+	}
+	if inScale(notes, theory.Pitch(root.Add(4))) {
+		return root
+	}
+	return root.Add(3)
+}
+
+func inScale(notes []theory.PitchClass, p theory.Pitch) bool {
+	for _, n := range notes {
+		if n == p.PitchClass() {
+			return true
+		}
+	}
+	return false
+}
+
+// nextInScale returns the lowest pitch above p whose pitch class is in notes.
+func nextInScale(notes []theory.PitchClass, p theory.Pitch) theory.Pitch {
+	for i := 1; i <= 12; i++ {
+		if inScale(notes, p.Transpose(i)) {
+			return p.Transpose(i)
+		}
+	}
+	return p.Transpose(12)
+}
+
+func minFret(locs []Location) int {
+	m := 0
+	for i, l := range locs {
+		if i == 0 || l.Fret < m {
+			m = l.Fret
+		}
+	}
+	return m
+}
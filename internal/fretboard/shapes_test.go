@@ -0,0 +1,128 @@
+package fretboard
+
+import (
+	"testing"
+
+	"github.com/paulgreig/guitar-training/internal/theory"
+)
+
+// scalePitchClasses builds the named scale, failing the test if it can't.
+func scalePitchClasses(t *testing.T, root, scaleType string) []theory.PitchClass {
+	t.Helper()
+	_, notes, err := theory.BuildScale(root, scaleType)
+	if err != nil {
+		t.Fatalf("BuildScale(%q, %q): %v", root, scaleType, err)
+	}
+	pcs := make([]theory.PitchClass, len(notes))
+	for i, n := range notes {
+		pcs[i] = n.PitchClass()
+	}
+	return pcs
+}
+
+func TestShapesNames(t *testing.T) {
+	tests := []struct {
+		root, scaleType string
+		want            []string
+	}{
+		{"C", "major", []string{"C shape", "A shape", "G shape", "E shape", "D shape",
+			"3NPS pattern 1", "3NPS pattern 2", "3NPS pattern 3", "3NPS pattern 4",
+			"3NPS pattern 5", "3NPS pattern 6", "3NPS pattern 7"}},
+		{"A", "minor pentatonic", []string{"Box 1", "Box 2", "Box 3", "Box 4", "Box 5"}},
+		{"A", "blues", []string{"C shape", "A shape", "G shape", "E shape", "D shape"}},
+	}
+	for _, tt := range tests {
+		shapes := Standard.Shapes(scalePitchClasses(t, tt.root, tt.scaleType))
+		var got []string
+		for _, s := range shapes {
+			got = append(got, s.Name)
+		}
+		// CAGED shapes are ordered up the neck, so compare them as a set.
+		if len(got) != len(tt.want) {
+			t.Fatalf("%s %s: got shapes %v, want %v", tt.root, tt.scaleType, got, tt.want)
+		}
+		seen := map[string]bool{}
+		for _, name := range got {
+			seen[name] = true
+		}
+		for _, name := range tt.want {
+			if !seen[name] {
+				t.Errorf("%s %s: missing %q in %v", tt.root, tt.scaleType, name, got)
+			}
+		}
+	}
+}
+
+func TestShapesHoldScaleNotes(t *testing.T) {
+	notes := scalePitchClasses(t, "G", "major")
+	for _, s := range Standard.Shapes(notes) {
+		for _, loc := range s.Locations {
+			if !inScale(notes, Standard.PitchAt(loc.String, loc.Fret)) {
+				t.Errorf("%s: string %d fret %d is not in G major", s.Name, loc.String, loc.Fret)
+			}
+		}
+	}
+}
+
+// TestShapesOnEveryTuning checks every shape of every scale stays on the
+// neck, including tunings whose strings aren't all a fourth apart.
+func TestShapesOnEveryTuning(t *testing.T) {
+	for _, tuning := range Tunings() {
+		for _, st := range theory.ScaleTypes() {
+			for r := 0; r < 12; r++ {
+				root := theory.NewPitchClass(r).String()
+				for _, s := range tuning.Shapes(scalePitchClasses(t, root, st.Name)) {
+					if len(s.Locations) == 0 {
+						t.Errorf("%s %s %s %s: no locations", tuning.ID, root, st.Name, s.Name)
+					}
+					for _, loc := range s.Locations {
+						if loc.Fret < 0 || loc.String < 0 || loc.String >= len(tuning.Strings) {
+							t.Errorf("%s %s %s %s: location %+v is off the neck", tuning.ID, root, st.Name, s.Name, loc)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// TestLayoutWindowStaysInWindow checks CAGED windows at every position keep
+// their notes between lo and one fret past hi on every tuning.
+func TestLayoutWindowStaysInWindow(t *testing.T) {
+	for _, tuning := range Tunings() {
+		for _, st := range theory.ScaleTypes() {
+			notes := scalePitchClasses(t, "C", st.Name)
+			for lo := 0; lo < 12; lo++ {
+				for width := cagedWindowWidth; width <= maxWindowWidth; width++ {
+					hi := lo + width - 1
+					locs, _ := tuning.layoutWindow(notes, lo, hi)
+					for _, loc := range locs {
+						if loc.Fret < 0 || loc.Fret < lo || loc.Fret > hi+1 {
+							t.Errorf("%s C %s window %d-%d: fret %d on string %d is outside it",
+								tuning.ID, st.Name, lo, hi, loc.Fret, loc.String)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestShapesDropD(t *testing.T) {
+	dropD, _ := LookupTuning("drop-d")
+	tests := []struct{ root, shape string }{
+		{"E", "E shape"},
+		{"A", "A shape"},
+	}
+	for _, tt := range tests {
+		for _, s := range dropD.Shapes(scalePitchClasses(t, tt.root, "major")) {
+			if s.Name != tt.shape {
+				continue
+			}
+			lo, hi := s.FretRange()
+			if lo < 0 || hi-lo > maxWindowWidth {
+				t.Errorf("drop D %s major %s: frets %d-%d", tt.root, tt.shape, lo, hi)
+			}
+		}
+	}
+}
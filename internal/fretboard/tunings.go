@@ -0,0 +1,54 @@
+package fretboard
+
+import (
+	"strings"
+
+	"github.com/paulgreig/guitar-training/internal/theory"
+)
+
+func pitches(names ...string) []theory.Pitch {
+	out := make([]theory.Pitch, len(names))
+	for i, n := range names {
+		out[i] = theory.MustParsePitch(n)
+	}
+	return out
+}
+
+// Standard is standard six-string guitar tuning (E2 A2 D3 G3 B3 E4).
+var Standard = Tuning{ID: "standard", Name: "Standard", Strings: pitches("E2", "A2", "D3", "G3", "B3", "E4")}
+
+// tunings is the built-in registry in display order.
+var tunings = []Tuning{
+	Standard,
+	{ID: "drop-d", Name: "Drop D", Strings: pitches("D2", "A2", "D3", "G3", "B3", "E4")},
+	{ID: "dadgad", Name: "DADGAD", Strings: pitches("D2", "A2", "D3", "G3", "A3", "D4")},
+	{ID: "open-g", Name: "Open G", Strings: pitches("D2", "G2", "D3", "G3", "B3", "D4")},
+	{ID: "half-step-down", Name: "Half-step down", Strings: pitches("Eb2", "Ab2", "Db3", "Gb3", "Bb3", "Eb4"), PreferFlats: true},
+	{ID: "7-string", Name: "7-string standard", Strings: pitches("B1", "E2", "A2", "D3", "G3", "B3", "E4")},
+	{ID: "8-string", Name: "8-string standard", Strings: pitches("F#1", "B1", "E2", "A2", "D3", "G3", "B3", "E4")},
+	{ID: "bass-4", Name: "4-string bass", Strings: pitches("E1", "A1", "D2", "G2")},
+	{ID: "bass-5", Name: "5-string bass", Strings: pitches("B0", "E1", "A1", "D2", "G2")},
+}
+
+// Tunings returns all built-in tunings in display order.
+func Tunings() []Tuning {
+	out := make([]Tuning, len(tunings))
+	copy(out, tunings)
+	return out
+}
+
+// LookupTuning finds a tuning by ID or display name, ignoring case, spaces,
+// hyphens and underscores ("Drop D", "drop_d" and "dropd" all match).
+func LookupTuning(name string) (Tuning, bool) {
+	key := normaliseTuningName(name)
+	for _, t := range tunings {
+		if normaliseTuningName(t.ID) == key || normaliseTuningName(t.Name) == key {
+			return t, true
+		}
+	}
+	return Tuning{}, false
+}
+
+func normaliseTuningName(name string) string {
+	return strings.NewReplacer(" ", "", "-", "", "_", "").Replace(strings.ToLower(name))
+}
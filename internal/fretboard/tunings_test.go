@@ -0,0 +1,86 @@
+package fretboard
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/paulgreig/guitar-training/internal/theory"
+)
+
+func TestLookupTuning(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantID  string
+		strings []string
+	}{
+		{"standard", "standard", []string{"E2", "A2", "D3", "G3", "B3", "E4"}},
+		{"Standard", "standard", nil},
+		{"drop-d", "drop-d", []string{"D2", "A2", "D3", "G3", "B3", "E4"}},
+		{"Drop D", "drop-d", nil},
+		{"drop_d", "drop-d", nil},
+		{"DROPD", "drop-d", nil},
+		{"dadgad", "dadgad", []string{"D2", "A2", "D3", "G3", "A3", "D4"}},
+		{"Half-step down", "half-step-down", []string{"D#2", "G#2", "C#3", "F#3", "A#3", "D#4"}},
+		{"7-string", "7-string", []string{"B1", "E2", "A2", "D3", "G3", "B3", "E4"}},
+		{"7 String Standard", "7-string", nil},
+		{"8-string", "8-string", []string{"F#1", "B1", "E2", "A2", "D3", "G3", "B3", "E4"}},
+		{"bass 4", "bass-4", []string{"E1", "A1", "D2", "G2"}},
+		{"drop c", "", nil},
+		{"", "", nil},
+	}
+	for _, tt := range tests {
+		got, ok := LookupTuning(tt.name)
+		if ok != (tt.wantID != "") || got.ID != tt.wantID {
+			t.Errorf("LookupTuning(%q) = %q, %v; want %q", tt.name, got.ID, ok, tt.wantID)
+			continue
+		}
+		if tt.strings == nil {
+			continue
+		}
+		var names []string
+		for _, p := range got.Strings {
+			names = append(names, p.String())
+		}
+		if !reflect.DeepEqual(names, tt.strings) {
+			t.Errorf("LookupTuning(%q) strings = %v, want %v", tt.name, names, tt.strings)
+		}
+	}
+}
+
+// TestTunings checks every tuning in the registry can be found by its ID and
+// name, and runs from its lowest string up.
+func TestTunings(t *testing.T) {
+	seen := map[string]bool{}
+	for _, tuning := range Tunings() {
+		if seen[tuning.ID] {
+			t.Errorf("%s: listed twice", tuning.ID)
+		}
+		seen[tuning.ID] = true
+		for _, key := range []string{tuning.ID, tuning.Name} {
+			if got, ok := LookupTuning(key); !ok || got.ID != tuning.ID {
+				t.Errorf("%s: LookupTuning(%q) = %q, %v", tuning.ID, key, got.ID, ok)
+			}
+		}
+		for i := 1; i < len(tuning.Strings); i++ {
+			if tuning.Strings[i] <= tuning.Strings[i-1] {
+				t.Errorf("%s: string %d (%s) isn't above string %d (%s)",
+					tuning.ID, i, tuning.Strings[i], i-1, tuning.Strings[i-1])
+			}
+		}
+	}
+	if !seen["standard"] || Tunings()[0].ID != "standard" {
+		t.Errorf("standard isn't listed first")
+	}
+}
+
+// TestTuningsCopy checks changing the returned list leaves the registry be.
+func TestTuningsCopy(t *testing.T) {
+	list := Tunings()
+	list[0] = Tuning{ID: "changed"}
+	if got := Tunings()[0].ID; got != "standard" {
+		t.Errorf("after changing the list the first tuning is %q", got)
+	}
+	if got, _ := LookupTuning("standard"); got.Strings[0] != theory.MustParsePitch("E2") {
+		t.Errorf("standard's lowest string is %s", got.Strings[0])
+	}
+}
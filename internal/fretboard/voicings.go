@@ -0,0 +1,266 @@
+package fretboard
+
+import (
+	"sort"
+
+	"github.com/paulgreig/guitar-training/internal/theory"
+)
+
+// Muted marks a string that is not played in a voicing.
+const Muted = -1
+
+// Voicing is one way to finger a chord: a fret per string (low to high,
+// Muted for unplayed strings) and the finger used on each (0 for open or
+// muted strings, 1-4 index to little finger).
+type Voicing struct {
+	Frets   []int
+	Fingers []int
+}
+
+// BaseFret returns the lowest fretted (non-open) fret in the voicing, or 1
+// for voicings made only of open strings.
+func (v Voicing) BaseFret() int {
+	base := 0
+	for _, f := range v.Frets {
+		if f > 0 && (base == 0 || f < base) {
+			base = f
+		}
+	}
+	if base == 0 {
+		return 1
+	}
+	return base
+}
+
+// voicingSpan is the number of frets a hand can cover without stretching.
+const voicingSpan = 4
+
+// maxVoicingFret is the highest base fret searched when generating voicings.
+const maxVoicingFret = 12
+
+// maxOpenPositionFret is the highest fret a voicing with open strings may use.
+const maxOpenPositionFret = 4
+
+// Voicings generates up to limit playable voicings of a chord, best first.
+// The chord is given as pitch classes, root first. A voicing must have the
+// root in the bass, contain every chord tone (the fifth may be dropped from
+// chords of four or more notes), mute only strings below the bass note, stay
+// within a four-fret span and need at most four fingers, treating a shared
+// lowest fret as a barre.
+func (t Tuning) Voicings(chord []theory.PitchClass, limit int) []Voicing {
+	if len(chord) == 0 || len(t.Strings) == 0 {
+		return nil
+	}
+	type scored struct {
+		v     Voicing
+		score int
+	}
+	seen := make(map[string]bool)
+	var found []scored
+
+	minStrings := len(t.Strings) - 2
+	if minStrings < 3 {
+		minStrings = len(t.Strings)
+	}
+	frets := make([]int, len(t.Strings))
+
+	for base := 1; base <= maxVoicingFret; base++ {
+		var walk func(s int)
+		walk = func(s int) {
+			if s == len(t.Strings) {
+				v, ok := t.checkVoicing(chord, frets, minStrings)
+				if !ok {
+					return
+				}
+				key := voicingKey(v.Frets)
+				if seen[key] {
+					return
+				}
+				seen[key] = true
+				found = append(found, scored{v, voicingScore(v)})
+				return
+			}
+			for _, f := range t.candidateFrets(chord, s, base) {
+				frets[s] = f
+				walk(s + 1)
+			}
+		}
+		walk(0)
+	}
+
+	sort.SliceStable(found, func(i, j int) bool {
+		if found[i].score != found[j].score {
+			return found[i].score > found[j].score
+		}
+		return found[i].v.BaseFret() < found[j].v.BaseFret()
+	})
+	if limit > 0 && len(found) > limit {
+		found = found[:limit]
+	}
+	out := make([]Voicing, len(found))
+	for i, f := range found {
+		out[i] = f.v
+	}
+	return out
+}
+
+// candidateFrets lists the frets worth trying on a string for a window
+// starting at base: muted, open (when it is a chord tone) and chord tones
+// inside the window.
+func (t Tuning) candidateFrets(chord []theory.PitchClass, s, base int) []int {
+	out := []int{Muted}
+	if base <= maxOpenPositionFret && containsPC(chord, t.PitchAt(s, 0).PitchClass()) {
+		out = append(out, 0)
+	}
+	for f := base; f < base+voicingSpan; f++ {
+		if containsPC(chord, t.PitchAt(s, f).PitchClass()) {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+func (t Tuning) checkVoicing(chord []theory.PitchClass, frets []int, minStrings int) (Voicing, bool) {
+	// Mutes are only allowed below the bass note.
+	bass := -1
+	for s, f := range frets {
+		if f == Muted {
+			if bass >= 0 {
+				return Voicing{}, false
+			}
+			continue
+		}
+		if bass < 0 {
+			bass = s
+		}
+	}
+	if bass < 0 || len(frets)-bass < minStrings {
+		return Voicing{}, false
+	}
+	// Open strings only mix with fretted notes in open position.
+	hasOpen, top := false, 0
+	for _, f := range frets {
+		hasOpen = hasOpen || f == 0
+		if f > top {
+			top = f
+		}
+	}
+	if hasOpen && top > maxOpenPositionFret {
+		return Voicing{}, false
+	}
+	if t.PitchAt(bass, frets[bass]).PitchClass() != chord[0] {
+		return Voicing{}, false
+	}
+
+	present := make(map[theory.PitchClass]bool)
+	for s := bass; s < len(frets); s++ {
+		present[t.PitchAt(s, frets[s]).PitchClass()] = true
+	}
+	for i, pc := range chord {
+		// The fifth (third note of a triad-based formula) may be omitted from larger chords.
+		if !present[pc] && !(len(chord) >= 4 && i == 2) {
+			return Voicing{}, false
+		}
+	}
+
+	fingers, ok := assignFingers(frets)
+	if !ok {
+		return Voicing{}, false
+	}
+	v := Voicing{Frets: append([]int(nil), frets...), Fingers: fingers}
+	return v, true
+}
+
+// assignFingers gives each fretted note a finger in fret order. When the
+// lowest fret appears on more than one string the first finger barres it,
+// which rules out open strings under the barre.
+func assignFingers(frets []int) ([]int, bool) {
+	fingers := make([]int, len(frets))
+	type note struct{ s, f int }
+	var notes []note
+	low := 0
+	for s, f := range frets {
+		if f > 0 {
+			notes = append(notes, note{s, f})
+			if low == 0 || f < low {
+				low = f
+			}
+		}
+	}
+	sort.SliceStable(notes, func(i, j int) bool {
+		if notes[i].f != notes[j].f {
+			return notes[i].f < notes[j].f
+		}
+		return notes[i].s < notes[j].s
+	})
+	finger := 0
+	for _, n := range notes {
+		if n.f == low && finger == 1 {
+			fingers[n.s] = 1
+			continue
+		}
+		finger++
+		if finger > 4 {
+			return nil, false
+		}
+		fingers[n.s] = finger
+	}
+	first, last := -1, -1
+	for s, f := range fingers {
+		if f == 1 {
+			if first < 0 {
+				first = s
+			}
+			last = s
+		}
+	}
+	for s := first + 1; s < last; s++ {
+		if frets[s] < low {
+			return nil, false
+		}
+	}
+	return fingers, true
+}
+
+// voicingScore prefers voicings that sound more strings, sit lower on the
+// neck and need less of a stretch. Open strings count slightly extra.
+func voicingScore(v Voicing) int {
+	score := 0
+	lo, hi := 0, 0
+	for _, f := range v.Frets {
+		switch {
+		case f == Muted:
+		case f == 0:
+			score += 4
+		default:
+			score += 3
+			if lo == 0 || f < lo {
+				lo = f
+			}
+			if f > hi {
+				hi = f
+			}
+		}
+	}
+	if lo > 0 {
+		score -= 2*lo + (hi - lo)
+	}
+	return score
+}
+
+func voicingKey(frets []int) string {
+	b := make([]byte, 0, len(frets)*3)
+	for _, f := range frets {
+		b = append(b, byte(f+2), ',')
+	}
+	return string(b)
+}
+
+func containsPC(pcs []theory.PitchClass, pc theory.PitchClass) bool {
+	for _, p := range pcs {
+		if p == pc {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,174 @@
+package fretboard
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/paulgreig/guitar-training/internal/theory"
+)
+
+// chordPitchClasses returns the pitch classes of a chord symbol, root first.
+func chordPitchClasses(t *testing.T, sym string) []theory.PitchClass {
+	t.Helper()
+	root, q, err := theory.ParseChordSymbol(sym)
+	if err != nil {
+		t.Fatalf("ParseChordSymbol(%q): %v", sym, err)
+	}
+	var pcs []theory.PitchClass
+	for _, n := range q.Notes(root) {
+		pcs = append(pcs, n.PitchClass())
+	}
+	return pcs
+}
+
+// TestVoicingsOpenChords checks the best voicing of the common open chords
+// is the shape a guitarist learns first.
+func TestVoicingsOpenChords(t *testing.T) {
+	tests := []struct {
+		chord string
+		want  []int
+	}{
+		{"C", []int{Muted, 3, 2, 0, 1, 0}},
+		{"G", []int{3, 2, 0, 0, 0, 3}},
+		{"D", []int{Muted, Muted, 0, 2, 3, 2}},
+		{"A", []int{Muted, 0, 2, 2, 2, 0}},
+		{"E", []int{0, 2, 2, 1, 0, 0}},
+		{"Am", []int{Muted, 0, 2, 2, 1, 0}},
+		{"Em", []int{0, 2, 2, 0, 0, 0}},
+		{"Dm", []int{Muted, Muted, 0, 2, 3, 1}},
+		{"F", []int{1, 3, 3, 2, 1, 1}},
+	}
+	for _, tt := range tests {
+		v := Standard.Voicings(chordPitchClasses(t, tt.chord), 1)
+		if len(v) != 1 {
+			t.Errorf("%s: got %d voicings, want 1", tt.chord, len(v))
+			continue
+		}
+		if !reflect.DeepEqual(v[0].Frets, tt.want) {
+			t.Errorf("%s: best voicing %v, want %v", tt.chord, v[0].Frets, tt.want)
+		}
+	}
+}
+
+// TestVoicingsPlayable checks every generated voicing follows the rules in
+// the Voicings doc comment, for every chord quality, root and tuning.
+func TestVoicingsPlayable(t *testing.T) {
+	for _, tuning := range Tunings() {
+		for _, q := range theory.ChordQualities() {
+			for r := 0; r < 12; r++ {
+				root := theory.SpellPitchClass(theory.PitchClass(r), false)
+				var chord []theory.PitchClass
+				for _, n := range q.Notes(root) {
+					chord = append(chord, n.PitchClass())
+				}
+				name := tuning.ID + " " + root.String() + q.Symbol
+				for _, v := range tuning.Voicings(chord, 5) {
+					checkVoicingRules(t, name, tuning, chord, v)
+				}
+			}
+		}
+	}
+}
+
+func checkVoicingRules(t *testing.T, name string, tuning Tuning, chord []theory.PitchClass, v Voicing) {
+	t.Helper()
+	if len(v.Frets) != len(tuning.Strings) || len(v.Fingers) != len(tuning.Strings) {
+		t.Errorf("%s %v: wrong number of strings", name, v.Frets)
+		return
+	}
+	bass, lo, hi := -1, 0, 0
+	have := map[theory.PitchClass]bool{}
+	for s, f := range v.Frets {
+		if f == Muted {
+			if bass >= 0 {
+				t.Errorf("%s %v: string %d muted above the bass", name, v.Frets, s)
+			}
+			continue
+		}
+		pc := tuning.PitchAt(s, f).PitchClass()
+		if bass < 0 {
+			bass = s
+			if pc != chord[0] {
+				t.Errorf("%s %v: bass is %s, not the root", name, v.Frets, pc)
+			}
+		}
+		if !containsPC(chord, pc) {
+			t.Errorf("%s %v: %s is not in the chord", name, v.Frets, pc)
+		}
+		have[pc] = true
+		if f > 0 {
+			if lo == 0 || f < lo {
+				lo = f
+			}
+			hi = max(hi, f)
+		}
+		if finger := v.Fingers[s]; f == 0 && finger != 0 || f > 0 && (finger < 1 || finger > 4) {
+			t.Errorf("%s %v: finger %d on fret %d", name, v.Frets, finger, f)
+		}
+	}
+	for i, pc := range chord {
+		// The fifth may be left out of chords of four or more notes.
+		if !have[pc] && !(len(chord) >= 4 && i == 2) {
+			t.Errorf("%s %v: missing %s", name, v.Frets, pc)
+		}
+	}
+	if lo > 0 && hi-lo >= voicingSpan {
+		t.Errorf("%s %v: spans frets %d-%d", name, v.Frets, lo, hi)
+	}
+}
+
+func TestVoicingsLimit(t *testing.T) {
+	chord := chordPitchClasses(t, "G")
+	if got := len(Standard.Voicings(chord, 2)); got != 2 {
+		t.Errorf("limit 2: got %d voicings", got)
+	}
+	if all := Standard.Voicings(chord, 0); len(all) <= 2 {
+		t.Errorf("no limit: got %d voicings", len(all))
+	}
+	if got := Standard.Voicings(nil, 3); got != nil {
+		t.Errorf("no chord: got %v", got)
+	}
+}
+
+func TestBaseFret(t *testing.T) {
+	tests := []struct {
+		frets []int
+		want  int
+	}{
+		{[]int{Muted, 3, 2, 0, 1, 0}, 1},
+		{[]int{0, 2, 2, 0, 0, 0}, 2},
+		{[]int{Muted, 5, 7, 7, 7, 5}, 5},
+		{[]int{0, 0, 0, 0, 0, 0}, 1},
+		{[]int{Muted, Muted, Muted, Muted, Muted, Muted}, 1},
+	}
+	for _, tt := range tests {
+		if got := (Voicing{Frets: tt.frets}).BaseFret(); got != tt.want {
+			t.Errorf("BaseFret(%v) = %d, want %d", tt.frets, got, tt.want)
+		}
+	}
+}
+
+func TestAssignFingers(t *testing.T) {
+	tests := []struct {
+		frets []int
+		want  []int // nil when unplayable
+	}{
+		{[]int{Muted, 3, 2, 0, 1, 0}, []int{0, 3, 2, 0, 1, 0}},
+		{[]int{1, 3, 3, 2, 1, 1}, []int{1, 3, 4, 2, 1, 1}},
+		{[]int{Muted, 0, 2, 2, 2, 0}, []int{0, 0, 1, 1, 1, 0}},
+		{[]int{Muted, 2, 2, 0, 2, Muted}, nil}, // open string under the barre
+		{[]int{1, 2, 3, 4, 5, Muted}, nil},     // five fingers
+	}
+	for _, tt := range tests {
+		got, ok := assignFingers(tt.frets)
+		if tt.want == nil {
+			if ok {
+				t.Errorf("assignFingers(%v) = %v, want unplayable", tt.frets, got)
+			}
+			continue
+		}
+		if !ok || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("assignFingers(%v) = %v, %v, want %v", tt.frets, got, ok, tt.want)
+		}
+	}
+}
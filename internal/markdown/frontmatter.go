@@ -0,0 +1,87 @@
+// Package markdown renders the Markdown used in lessons as styled terminal
+// text and reads the front-matter at the top of lesson files.
+package markdown
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FrontMatter is the key/value header of a document. Each key maps to its
+// values: one for a scalar, any number for a list.
+type FrontMatter map[string][]string
+
+// Get returns the first value of key, or "".
+func (f FrontMatter) Get(key string) string {
+	if v := f[key]; len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// List returns every value of key.
+func (f FrontMatter) List(key string) []string {
+	return f[key]
+}
+
+// SplitFrontMatter separates a document into its front-matter and body. The
+// front-matter is a block of "key: value" lines between two "---" lines at
+// the very top; lists are written inline ("tags: [scales, theory]") or as
+// "- item" lines under the key. A document without front-matter is returned
+// unchanged with no fields. Errors name the line of the document at fault.
+func SplitFrontMatter(doc string) (FrontMatter, string, error) {
+	doc = strings.ReplaceAll(doc, "\r\n", "\n")
+	lines := strings.Split(doc, "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return FrontMatter{}, doc, nil
+	}
+	fm := FrontMatter{}
+	key := ""
+	for i := 1; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], " \t")
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "---" || trimmed == "...":
+			return fm, strings.TrimLeft(strings.Join(lines[i+1:], "\n"), "\n"), nil
+		case trimmed == "" || strings.HasPrefix(trimmed, "#"):
+			continue
+		case strings.HasPrefix(trimmed, "- "):
+			if key == "" {
+				return nil, "", fmt.Errorf("line %d: list item without a key", i+1)
+			}
+			fm[key] = append(fm[key], unquote(strings.TrimSpace(trimmed[2:])))
+			continue
+		}
+		k, v, ok := strings.Cut(line, ":")
+		if !ok || strings.TrimSpace(k) == "" {
+			return nil, "", fmt.Errorf("line %d: expected \"key: value\", got %q", i+1, trimmed)
+		}
+		key = strings.ToLower(strings.TrimSpace(k))
+		fm[key] = parseValue(strings.TrimSpace(v))
+	}
+	return nil, "", fmt.Errorf("front-matter starting on line 1 is not closed with ---")
+}
+
+// parseValue reads a scalar or an inline [a, b] list.
+func parseValue(v string) []string {
+	if v == "" {
+		return nil
+	}
+	if !strings.HasPrefix(v, "[") || !strings.HasSuffix(v, "]") {
+		return []string{unquote(v)}
+	}
+	var out []string
+	for _, item := range strings.Split(v[1:len(v)-1], ",") {
+		if item = unquote(strings.TrimSpace(item)); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,81 @@
+package markdown
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitFrontMatter(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		fm   FrontMatter
+		body string
+	}{
+		{"none", "# Title\n\nText", FrontMatter{}, "# Title\n\nText"},
+		{"empty document", "", FrontMatter{}, ""},
+		{"later rule", "Text\n---\nMore", FrontMatter{}, "Text\n---\nMore"},
+		{"scalars", "---\nid: intro\nTitle: \"The: Basics\"\nlevel: 'beginner'\n---\n\n# Hi", FrontMatter{
+			"id":    {"intro"},
+			"title": {"The: Basics"},
+			"level": {"beginner"},
+		}, "# Hi"},
+		{"inline list", "---\ntags: [scales, \"modes\", , theory]\n---\nBody", FrontMatter{
+			"tags": {"scales", "modes", "theory"},
+		}, "Body"},
+		{"block list", "---\nprerequisites:\n  - intro\n  - 'scales'\nid: next\n---\nBody", FrontMatter{
+			"prerequisites": {"intro", "scales"},
+			"id":            {"next"},
+		}, "Body"},
+		{"empty value", "---\ntags:\n---\nBody", FrontMatter{"tags": nil}, "Body"},
+		{"comments and blanks", "---\n# a comment\n\nid: x\n...\nBody", FrontMatter{"id": {"x"}}, "Body"},
+		{"CRLF", "---\r\nid: x\r\n---\r\nBody\r\n", FrontMatter{"id": {"x"}}, "Body\n"},
+		{"empty front-matter", "---\n---\nBody", FrontMatter{}, "Body"},
+	}
+	for _, tt := range tests {
+		fm, body, err := SplitFrontMatter(tt.doc)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(fm, tt.fm) || body != tt.body {
+			t.Errorf("%s: got %v %q, want %v %q", tt.name, fm, body, tt.fm, tt.body)
+		}
+	}
+}
+
+func TestSplitFrontMatterErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want string
+	}{
+		{"unterminated", "---\nid: x\n\n# Title", "front-matter starting on line 1 is not closed with ---"},
+		{"only opening", "---", "front-matter starting on line 1 is not closed with ---"},
+		{"no colon", "---\nid: x\njust words\n---\n", `line 3: expected "key: value", got "just words"`},
+		{"no key", "---\n: value\n---\n", `line 2: expected "key: value", got ": value"`},
+		{"item without key", "---\n- stray\n---\n", "line 2: list item without a key"},
+	}
+	for _, tt := range tests {
+		fm, body, err := SplitFrontMatter(tt.doc)
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("%s: error %v, want %q", tt.name, err, tt.want)
+		}
+		if fm != nil || body != "" {
+			t.Errorf("%s: got %v %q with the error", tt.name, fm, body)
+		}
+	}
+}
+
+func TestFrontMatterGet(t *testing.T) {
+	fm := FrontMatter{"tags": {"a", "b"}, "empty": nil}
+	if got := fm.Get("tags"); got != "a" {
+		t.Errorf("Get(tags) = %q", got)
+	}
+	if got := fm.Get("empty") + fm.Get("missing"); got != "" {
+		t.Errorf("Get of empty and missing keys = %q", got)
+	}
+	if got := fm.List("tags"); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("List(tags) = %v", got)
+	}
+}
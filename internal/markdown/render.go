@@ -0,0 +1,290 @@
+package markdown
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Styles are the styles applied to each kind of element.
+type Styles struct {
+	Headings []lipgloss.Style // by level, # first; the last is reused for deeper levels
+	Text     lipgloss.Style
+	Bold     lipgloss.Style
+	Italic   lipgloss.Style
+	Code     lipgloss.Style
+	Quote    lipgloss.Style
+	Rule     lipgloss.Style
+}
+
+// DefaultStyles returns the styles used for lessons.
+func DefaultStyles() Styles {
+	return Styles{
+		Headings: []lipgloss.Style{
+			lipgloss.NewStyle().Bold(true).Underline(true).Foreground(lipgloss.Color("63")),
+			lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("39")),
+			lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("252")),
+		},
+		Text:   lipgloss.NewStyle().Foreground(lipgloss.Color("252")),
+		Bold:   lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("255")),
+		Italic: lipgloss.NewStyle().Italic(true).Foreground(lipgloss.Color("252")),
+		Code:   lipgloss.NewStyle().Foreground(lipgloss.Color("214")),
+		Quote:  lipgloss.NewStyle().Italic(true).Foreground(lipgloss.Color("245")),
+		Rule:   lipgloss.NewStyle().Foreground(lipgloss.Color("240")),
+	}
+}
+
+// BlockFunc renders a fenced block given its info string (the text after the
+// opening ```, such as "scale C major") and the lines inside it. It returns
+// false to have the block shown as code.
+type BlockFunc func(info, body string) (string, bool)
+
+// Renderer lays out Markdown for the terminal: headings, paragraphs wrapped
+// to Width, bulleted and numbered lists, block quotes, rules, **bold**,
+// *italic*, `code` and fenced blocks. Fenced blocks go to Block first, if
+// set; a fence may also open and close on one line (```chord Am```).
+type Renderer struct {
+	Width  int // wrap width; zero or less means no wrapping
+	Styles Styles
+	Block  BlockFunc
+}
+
+var (
+	headingLine = regexp.MustCompile(`^(#{1,6})\s+(.*?)(\s+#+)?$`)
+	itemLine    = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+	ruleLine    = regexp.MustCompile(`^(?:(?:-\s*){3,}|(?:\*\s*){3,}|(?:_\s*){3,})$`)
+)
+
+// Heading is a heading found while laying out a document.
+type Heading struct {
+	Level int    // 1 for #, 2 for ## and so on
+	Text  string // without inline markers
+	Line  int    // index of its first output line
+}
+
+// Render renders doc.
+func (r Renderer) Render(doc string) string {
+	lines, _ := r.Layout(doc)
+	return strings.Join(lines, "\n")
+}
+
+// Layout renders doc as output lines and lists its headings, for callers
+// that show part of a document at a time.
+func (r Renderer) Layout(doc string) ([]string, []Heading) {
+	lines := strings.Split(strings.ReplaceAll(doc, "\r\n", "\n"), "\n")
+	var out []string
+	var headings []Heading
+	emit := func(block ...string) {
+		for _, b := range block {
+			out = append(out, strings.Split(b, "\n")...)
+		}
+	}
+	blank := func() {
+		if len(out) > 0 && out[len(out)-1] != "" {
+			out = append(out, "")
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], " \t")
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			blank()
+		case strings.HasPrefix(trimmed, "```"):
+			info := strings.TrimSpace(trimmed[3:])
+			var body []string
+			if strings.HasSuffix(info, "```") {
+				info = strings.TrimSpace(strings.TrimSuffix(info, "```"))
+			} else {
+				for i++; i < len(lines) && strings.TrimSpace(lines[i]) != "```"; i++ {
+					body = append(body, strings.TrimRight(lines[i], " \t"))
+				}
+			}
+			emit(r.fenced(info, strings.Join(body, "\n")))
+		case headingLine.MatchString(trimmed):
+			m := headingLine.FindStringSubmatch(trimmed)
+			level := min(len(m[1]), len(r.Styles.Headings)) - 1
+			style := lipgloss.NewStyle()
+			if level >= 0 {
+				style = r.Styles.Headings[level]
+			}
+			headings = append(headings, Heading{Level: len(m[1]), Text: plainText(m[2], r.Styles), Line: len(out)})
+			emit(r.wrap(m[2], "", "", style)...)
+		case ruleLine.MatchString(trimmed):
+			emit(r.Styles.Rule.Render(strings.Repeat("─", r.ruleWidth())))
+		case itemLine.MatchString(line):
+			m := itemLine.FindStringSubmatch(line)
+			text := []string{m[3]}
+			for i+1 < len(lines) && r.continues(lines[i+1]) {
+				i++
+				text = append(text, strings.TrimSpace(lines[i]))
+			}
+			indent := strings.Repeat(" ", len(m[1]))
+			marker := m[2]
+			if !strings.ContainsAny(marker[len(marker)-1:], ".)") {
+				marker = "•"
+			}
+			hang := indent + strings.Repeat(" ", utf8.RuneCountInString(marker)+1)
+			emit(r.wrap(strings.Join(text, " "), indent+marker+" ", hang, r.Styles.Text)...)
+		case strings.HasPrefix(trimmed, ">"):
+			var text []string
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">"); i++ {
+				text = append(text, strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(lines[i]), ">")))
+			}
+			i--
+			emit(r.wrap(strings.Join(text, " "), "│ ", "│ ", r.Styles.Quote)...)
+		default:
+			text := []string{trimmed}
+			for i+1 < len(lines) && r.continues(lines[i+1]) {
+				i++
+				text = append(text, strings.TrimSpace(lines[i]))
+			}
+			emit(r.wrap(strings.Join(text, " "), "", "", r.Styles.Text)...)
+		}
+	}
+	for len(out) > 0 && out[len(out)-1] == "" {
+		out = out[:len(out)-1]
+	}
+	return out, headings
+}
+
+// continues reports whether line carries on the paragraph or list item above
+// it rather than starting a new block.
+func (r Renderer) continues(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return trimmed != "" &&
+		!strings.HasPrefix(trimmed, "```") &&
+		!strings.HasPrefix(trimmed, ">") &&
+		!headingLine.MatchString(trimmed) &&
+		!ruleLine.MatchString(trimmed) &&
+		!itemLine.MatchString(line)
+}
+
+func (r Renderer) fenced(info, body string) string {
+	if r.Block != nil {
+		if s, ok := r.Block(info, body); ok {
+			return s
+		}
+	}
+	lines := strings.Split(body, "\n")
+	for i, l := range lines {
+		lines[i] = "  " + r.Styles.Code.Render(l)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (r Renderer) ruleWidth() int {
+	if r.Width <= 0 || r.Width > 40 {
+		return 40
+	}
+	return r.Width
+}
+
+// span is a run of text in one style.
+type span struct {
+	text  string
+	style lipgloss.Style
+}
+
+// inline splits text into styled spans at **bold**, __bold__, *italic* and
+// `code` markers. A backslash escapes the character after it.
+func inline(text string, base lipgloss.Style, s Styles) []span {
+	var spans []span
+	var plain strings.Builder
+	flush := func() {
+		if plain.Len() > 0 {
+			spans = append(spans, span{plain.String(), base})
+			plain.Reset()
+		}
+	}
+	for i := 0; i < len(text); {
+		switch {
+		case text[i] == '\\' && i+1 < len(text):
+			plain.WriteByte(text[i+1])
+			i += 2
+			continue
+		case text[i] == '`':
+			if end := strings.IndexByte(text[i+1:], '`'); end >= 0 {
+				flush()
+				spans = append(spans, span{text[i+1 : i+1+end], s.Code})
+				i += end + 2
+				continue
+			}
+		case strings.HasPrefix(text[i:], "**") || strings.HasPrefix(text[i:], "__"):
+			marker := text[i : i+2]
+			if end := strings.Index(text[i+2:], marker); end > 0 {
+				flush()
+				spans = append(spans, span{text[i+2 : i+2+end], s.Bold})
+				i += end + 4
+				continue
+			}
+		case text[i] == '*' && i+1 < len(text) && text[i+1] != ' ':
+			if end := strings.IndexByte(text[i+1:], '*'); end > 0 && text[i+end] != ' ' {
+				flush()
+				spans = append(spans, span{text[i+1 : i+1+end], s.Italic})
+				i += end + 2
+				continue
+			}
+		}
+		plain.WriteByte(text[i])
+		i++
+	}
+	flush()
+	return spans
+}
+
+// plainText is text with its inline markers removed.
+func plainText(text string, s Styles) string {
+	var b strings.Builder
+	for _, sp := range inline(text, lipgloss.NewStyle(), s) {
+		b.WriteString(sp.text)
+	}
+	return b.String()
+}
+
+// wrap lays out text in style, breaking lines at spaces so they fit the
+// width. The first line starts with first and the rest with rest.
+func (r Renderer) wrap(text, first, rest string, style lipgloss.Style) []string {
+	// Split the spans into words, keeping the style of every piece.
+	type word struct {
+		rendered string
+		width    int
+	}
+	var words []word
+	var cur word
+	for _, sp := range inline(text, style, r.Styles) {
+		for j, part := range strings.Split(sp.text, " ") {
+			if j > 0 && cur.width > 0 {
+				words, cur = append(words, cur), word{}
+			}
+			if part != "" {
+				cur.rendered += sp.style.Render(part)
+				cur.width += utf8.RuneCountInString(part)
+			}
+		}
+	}
+	if cur.width > 0 {
+		words = append(words, cur)
+	}
+
+	var lines []string
+	line, width := first, utf8.RuneCountInString(first)
+	empty := true
+	for _, w := range words {
+		if !empty && r.Width > 0 && width+1+w.width > r.Width {
+			lines = append(lines, line)
+			line, width, empty = rest, utf8.RuneCountInString(rest), true
+		}
+		if !empty {
+			line += style.Render(" ")
+			width++
+		}
+		line += w.rendered
+		width += w.width
+		empty = false
+	}
+	return append(lines, line)
+}
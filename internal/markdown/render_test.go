@@ -0,0 +1,81 @@
+package markdown
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestLayout checks the layout of each kind of block, with plain styles so
+// only the text and its arrangement are compared.
+func TestLayout(t *testing.T) {
+	tests := []struct {
+		name  string
+		width int
+		doc   string
+		want  []string
+	}{
+		{"paragraph", 0, "One\ntwo  \nthree\n\nFour", []string{"One two three", "", "Four"}},
+		{"wrapped", 12, "the quick brown fox jumps", []string{"the quick", "brown fox", "jumps"}},
+		{"heading", 0, "# Title #\nText\n## Sub", []string{"Title", "Text", "Sub"}},
+		{"inline", 0, "**bold**, __also__, *it*, `co de` and \\*not\\*", []string{"bold, also, it, co de and *not*"}},
+		{"unclosed markers", 0, "a * b ** c `d", []string{"a * b ** c `d"}},
+		{"bullets", 10, "- one\n* two two two\n  more\n+ three", []string{"• one", "• two two", "  two more", "• three"}},
+		{"numbered", 0, "1. first\n2) second", []string{"1. first", "2) second"}},
+		{"nested", 0, "- outer\n  - inner", []string{"• outer", "  • inner"}},
+		{"quote", 12, "> to be or\n> not to be", []string{"│ to be or", "│ not to be"}},
+		{"rule", 10, "a\n\n---\n\nb", []string{"a", "", "──────────", "", "b"}},
+		{"wide rule", 100, "***", []string{strings.Repeat("─", 40)}},
+		{"code", 0, "```go\nx := 1\n\n  y\n```\nafter", []string{"  x := 1", "  ", "    y", "after"}},
+		{"unclosed fence", 0, "```\ncode", []string{"  code"}},
+		{"blank lines", 0, "\n\n a \n\n\n b \n\n", []string{"a", "", "b"}},
+		{"CRLF", 0, "# T\r\nx\r\n", []string{"T", "x"}},
+	}
+	for _, tt := range tests {
+		r := Renderer{Width: tt.width}
+		got, _ := r.Layout(tt.doc)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLayoutHeadings(t *testing.T) {
+	doc := "# One\n\nSome text that wraps\n\n### *Three*\n\n```\n# not a heading\n```\n\n## Two **bold**"
+	_, got := Renderer{Width: 10}.Layout(doc)
+	want := []Heading{
+		{Level: 1, Text: "One", Line: 0},
+		{Level: 3, Text: "Three", Line: 5},
+		{Level: 2, Text: "Two bold", Line: 9},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("headings = %+v, want %+v", got, want)
+	}
+}
+
+// TestLayoutBlocks checks fenced blocks go to Block, on one line or many,
+// and fall back to code when it declines them.
+func TestLayoutBlocks(t *testing.T) {
+	var calls []string
+	r := Renderer{Block: func(info, body string) (string, bool) {
+		calls = append(calls, info+"|"+body)
+		if strings.HasPrefix(info, "chord") {
+			return "[" + info + "]\n[diagram]", true
+		}
+		return "", false
+	}}
+	got, _ := r.Layout("```chord Am```\n\n```tab\ne|-0-|\nB|-1-|\n```\n\n```  scale C major  ```")
+	want := []string{"[chord Am]", "[diagram]", "", "  e|-0-|", "  B|-1-|", "", "  "}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if wantCalls := []string{"chord Am|", "tab|e|-0-|\nB|-1-|", "scale C major|"}; !reflect.DeepEqual(calls, wantCalls) {
+		t.Errorf("blocks = %q, want %q", calls, wantCalls)
+	}
+}
+
+func TestRender(t *testing.T) {
+	if got := (Renderer{}).Render("# T\n\n- a\n- b\n\n"); got != "T\n\n• a\n• b" {
+		t.Errorf("Render = %q", got)
+	}
+}
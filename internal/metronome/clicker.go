@@ -0,0 +1,104 @@
+package metronome
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/paulgreig/guitar-training/internal/audio"
+)
+
+// Clicker makes the sound of a tick. Implementations must be safe to call
+// from a goroutine other than the UI's.
+type Clicker interface {
+	Click(level Level) error
+}
+
+// Bell rings the terminal bell on beats. Subdivisions are silent because a
+// bell cannot be made quieter.
+type Bell struct {
+	Out io.Writer
+}
+
+// Click implements Clicker.
+func (b Bell) Click(level Level) error {
+	if level == Subdiv {
+		return nil
+	}
+	_, err := io.WriteString(b.Out, "\a")
+	return err
+}
+
+// Silent is a Clicker that makes no sound.
+type Silent struct{}
+
+// Click implements Clicker.
+func (Silent) Click(Level) error { return nil }
+
+// PCM synthesises a short click per tick and writes it to an audio sink.
+// Accents are higher and louder than beats, which are louder than subdivisions.
+type PCM struct {
+	Sink audio.Sink
+}
+
+// clickSound is the pitch and loudness of each level.
+var clickSound = map[Level]struct{ freq, amp float64 }{
+	Accent: {1760, 0.9},
+	Beat:   {880, 0.7},
+	Subdiv: {880, 0.3},
+}
+
+// Click implements Clicker.
+func (p PCM) Click(level Level) error {
+	s := clickSound[level]
+	return p.Sink.Write(audio.Tone(s.freq, 0.03, s.amp, p.Sink.SampleRate()))
+}
+
+// Recorder remembers every click it receives. Useful for headless checks.
+type Recorder struct {
+	mu     sync.Mutex
+	Clicks []Level
+}
+
+// Click implements Clicker.
+func (r *Recorder) Click(level Level) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Clicks = append(r.Clicks, level)
+	return nil
+}
+
+// NewClicker builds a Clicker from a CLICK_OUTPUT style description:
+// "bell" (default), "none", "audio" (system audio player) or "wav:<path>"
+// (append clicks to a WAV file).
+func NewClicker(output string) (Clicker, error) {
+	if err := CheckClicker(output); err != nil {
+		return nil, err
+	}
+	switch output {
+	case "", "bell":
+		return Bell{Out: os.Stderr}, nil
+	case "none":
+		return Silent{}, nil
+	}
+	sink, err := audio.OpenSink(output)
+	if err != nil {
+		return nil, err
+	}
+	return PCM{Sink: sink}, nil
+}
+
+// CheckClicker reports whether NewClicker accepts an output description,
+// without opening it.
+func CheckClicker(output string) error {
+	switch {
+	case output == "" || output == "bell" || output == "none":
+		return nil
+	case output == "audio" || strings.HasPrefix(output, "wav:"):
+		return audio.CheckOutput(output)
+	default:
+		return fmt.Errorf("unknown click output %q (want bell, none, audio or wav:<path>)", output)
+	}
+}
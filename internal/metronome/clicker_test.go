@@ -0,0 +1,90 @@
+package metronome
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paulgreig/guitar-training/internal/audio"
+)
+
+func TestBell(t *testing.T) {
+	var b bytes.Buffer
+	bell := Bell{Out: &b}
+	for _, l := range []Level{Accent, Subdiv, Beat, Subdiv} {
+		if err := bell.Click(l); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if b.String() != "\a\a" {
+		t.Errorf("bell wrote %q, want a bell per beat", b.String())
+	}
+}
+
+// TestWAVClicker checks clicks can be written to a WAV file headless, each
+// level at its own loudness.
+func TestWAVClicker(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "clicks.wav")
+	c, err := NewClicker("wav:" + path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pcm, ok := c.(PCM)
+	if !ok {
+		t.Fatalf("NewClicker(wav:) = %T, want PCM", c)
+	}
+	order := []Level{Accent, Beat, Subdiv}
+	for _, l := range order {
+		if err := c.Click(l); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := pcm.Sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	samples, rate, err := audio.ReadWAV(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	click := len(audio.Tone(880, 0.03, 1, rate))
+	if len(samples) != click*len(order) {
+		t.Fatalf("got %d samples, want %d clicks of %d", len(samples), len(order), click)
+	}
+	var peaks []float64
+	for i := range order {
+		peak := 0.0
+		for _, s := range samples[i*click : (i+1)*click] {
+			peak = max(peak, s, -s)
+		}
+		peaks = append(peaks, peak)
+	}
+	if !(peaks[0] > peaks[1] && peaks[1] > peaks[2]) {
+		t.Errorf("peaks %v, want accent > beat > subdivision", peaks)
+	}
+}
+
+func TestNewClicker(t *testing.T) {
+	tests := []struct {
+		output  string
+		want    Clicker
+		wantErr bool
+	}{
+		{"", Bell{Out: os.Stderr}, false},
+		{"bell", Bell{Out: os.Stderr}, false},
+		{"none", Silent{}, false},
+		{"drum", nil, true},
+		{"wav:" + filepath.Join(t.TempDir(), "missing", "x.wav"), nil, true},
+	}
+	for _, tt := range tests {
+		c, err := NewClicker(tt.output)
+		if (err != nil) != tt.wantErr || c != tt.want {
+			t.Errorf("NewClicker(%q) = %#v, %v, want %#v", tt.output, c, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package metronome
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Tempo limits in beats per minute.
+const (
+	MinBPM     = 20
+	MaxBPM     = 300
+	DefaultBPM = 80
+)
+
+// tapTimeout is the gap after which tap tempo starts a new measurement.
+const tapTimeout = 2 * time.Second
+
+// maxTaps is how many recent taps are averaged.
+const maxTaps = 5
+
+// TimeSignature is beats per bar over the note value that gets the beat.
+type TimeSignature struct {
+	Beats int
+	Unit  int
+}
+
+func (ts TimeSignature) String() string {
+	return fmt.Sprintf("%d/%d", ts.Beats, ts.Unit)
+}
+
+// TimeSignatures lists the signatures the metronome cycles through.
+var TimeSignatures = []TimeSignature{{4, 4}, {3, 4}, {2, 4}, {5, 4}, {6, 8}, {7, 8}}
+
+// Subdivision is how many clicks sound per beat.
+type Subdivision struct {
+	Name  string
+	Parts int
+}
+
+// Subdivisions lists the subdivisions the metronome cycles through.
+var Subdivisions = []Subdivision{
+	{"quarters", 1},
+	{"8ths", 2},
+	{"triplets", 3},
+	{"16ths", 4},
+}
+
+// Level is how strongly a tick is played.
+type Level int
+
+const (
+	Subdiv Level = iota // a subdivision between beats
+	Beat                // a main beat
+	Accent              // beat one of the bar
+)
+
+// Tick is one click of the metronome.
+type Tick struct {
+	Beat  int // 0-based beat in the bar
+	Part  int // 0-based subdivision within the beat
+	Level Level
+}
+
+// Metronome tracks tempo, meter and the current position in the bar. It has
+// no clock of its own; callers schedule Next every Interval.
+type Metronome struct {
+	BPM         int
+	Signature   TimeSignature
+	Subdivision Subdivision
+	Running     bool
+
+	beat, part int
+	started    bool
+	taps       []time.Time
+}
+
+// New returns a stopped metronome at the default tempo in 4/4 quarters.
+func New() Metronome {
+	return Metronome{
+		BPM:         DefaultBPM,
+		Signature:   TimeSignatures[0],
+		Subdivision: Subdivisions[0],
+	}
+}
+
+// SetBPM sets the tempo, clamped to MinBPM..MaxBPM.
+func (m *Metronome) SetBPM(bpm int) {
+	if bpm < MinBPM {
+		bpm = MinBPM
+	}
+	if bpm > MaxBPM {
+		bpm = MaxBPM
+	}
+	m.BPM = bpm
+}
+
+// Interval is the time between ticks at the current tempo and subdivision.
+func (m *Metronome) Interval() time.Duration {
+	return time.Duration(float64(time.Minute) / float64(m.BPM*m.Subdivision.Parts))
+}
+
+// Start begins a new bar. The first Next returns the accented downbeat.
+func (m *Metronome) Start() {
+	m.Running = true
+	m.Reset()
+}
+
+// Stop halts the metronome.
+func (m *Metronome) Stop() {
+	m.Running = false
+}
+
+// Reset moves back to the start of the bar without changing Running.
+func (m *Metronome) Reset() {
+	m.beat, m.part, m.started = 0, 0, false
+}
+
+// Next advances to the next tick and returns it.
+func (m *Metronome) Next() Tick {
+	if m.started {
+		m.part++
+		if m.part >= m.Subdivision.Parts {
+			m.part = 0
+			m.beat = (m.beat + 1) % m.Signature.Beats
+		}
+	}
+	m.started = true
+	return m.Current()
+}
+
+// Current returns the tick at the current position.
+func (m *Metronome) Current() Tick {
+	level := Subdiv
+	switch {
+	case m.part == 0 && m.beat == 0:
+		level = Accent
+	case m.part == 0:
+		level = Beat
+	}
+	return Tick{Beat: m.beat, Part: m.part, Level: level}
+}
+
+// CycleSignature switches to the next time signature and restarts the bar.
+func (m *Metronome) CycleSignature() {
+	m.Signature = TimeSignatures[(indexOfSignature(m.Signature)+1)%len(TimeSignatures)]
+	m.Reset()
+}
+
+// CycleSubdivision switches to the next subdivision and restarts the bar.
+func (m *Metronome) CycleSubdivision() {
+	i := 0
+	for j, s := range Subdivisions {
+		if s == m.Subdivision {
+			i = j
+		}
+	}
+	m.Subdivision = Subdivisions[(i+1)%len(Subdivisions)]
+	m.Reset()
+}
+
+// Tap records a tap at now and, once two or more taps are close enough
+// together, sets the tempo to their average spacing. It reports whether the
+// tempo changed.
+func (m *Metronome) Tap(now time.Time) bool {
+	if n := len(m.taps); n > 0 && now.Sub(m.taps[n-1]) > tapTimeout {
+		m.taps = m.taps[:0]
+	}
+	m.taps = append(m.taps, now)
+	if len(m.taps) > maxTaps {
+		m.taps = m.taps[len(m.taps)-maxTaps:]
+	}
+	if len(m.taps) < 2 {
+		return false
+	}
+	avg := m.taps[len(m.taps)-1].Sub(m.taps[0]) / time.Duration(len(m.taps)-1)
+	if avg <= 0 {
+		return false
+	}
+	m.SetBPM(int(math.Round(float64(time.Minute) / float64(avg))))
+	return true
+}
+
+func indexOfSignature(ts TimeSignature) int {
+	for i, s := range TimeSignatures {
+		if s == ts {
+			return i
+		}
+	}
+	return 0
+}
@@ -0,0 +1,125 @@
+package metronome
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// levels runs the metronome for n ticks and returns their levels.
+func levels(m *Metronome, n int) []Level {
+	out := make([]Level, n)
+	for i := range out {
+		out[i] = m.Next().Level
+	}
+	return out
+}
+
+func TestNext(t *testing.T) {
+	A, B, S := Accent, Beat, Subdiv
+	tests := []struct {
+		sig  TimeSignature
+		sub  Subdivision
+		want []Level
+	}{
+		{TimeSignature{4, 4}, Subdivisions[0], []Level{A, B, B, B, A, B}},
+		{TimeSignature{3, 4}, Subdivisions[0], []Level{A, B, B, A, B, B}},
+		{TimeSignature{2, 4}, Subdivisions[1], []Level{A, S, B, S, A, S}},
+		{TimeSignature{2, 4}, Subdivisions[2], []Level{A, S, S, B, S, S, A}},
+		{TimeSignature{7, 8}, Subdivisions[0], []Level{A, B, B, B, B, B, B, A}},
+		{TimeSignature{1, 4}, Subdivisions[3], []Level{A, S, S, S, A}},
+	}
+	for _, tt := range tests {
+		m := New()
+		m.Signature, m.Subdivision = tt.sig, tt.sub
+		m.Start()
+		got := levels(&m, len(tt.want))
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s %s: levels %v, want %v", tt.sig, tt.sub.Name, got, tt.want)
+		}
+	}
+}
+
+func TestResetRestartsTheBar(t *testing.T) {
+	m := New()
+	m.Start()
+	levels(&m, 3)
+	m.CycleSignature()
+	if got := m.Next(); got != (Tick{Level: Accent}) {
+		t.Errorf("after CycleSignature: %+v, want the downbeat", got)
+	}
+	if m.Signature != (TimeSignature{3, 4}) {
+		t.Errorf("signature %s, want 3/4", m.Signature)
+	}
+	levels(&m, 2)
+	m.CycleSubdivision()
+	if got := m.Next(); got != (Tick{Level: Accent}) || m.Subdivision.Parts != 2 {
+		t.Errorf("after CycleSubdivision: %+v in %s, want the downbeat in 8ths", got, m.Subdivision.Name)
+	}
+	for i := 0; i < len(TimeSignatures)-1; i++ {
+		m.CycleSignature()
+	}
+	if m.Signature != TimeSignatures[0] {
+		t.Errorf("signatures don't cycle back to %s", TimeSignatures[0])
+	}
+}
+
+func TestInterval(t *testing.T) {
+	tests := []struct {
+		bpm, parts int
+		want       time.Duration
+	}{
+		{60, 1, time.Second},
+		{120, 1, 500 * time.Millisecond},
+		{120, 2, 250 * time.Millisecond},
+		{100, 3, 200 * time.Millisecond},
+		{80, 4, 187500 * time.Microsecond},
+	}
+	for _, tt := range tests {
+		m := New()
+		m.SetBPM(tt.bpm)
+		m.Subdivision = Subdivision{Parts: tt.parts}
+		if got := m.Interval(); got != tt.want {
+			t.Errorf("%d BPM in %d parts: interval %v, want %v", tt.bpm, tt.parts, got, tt.want)
+		}
+	}
+}
+
+func TestSetBPM(t *testing.T) {
+	tests := []struct{ in, want int }{{100, 100}, {5, MinBPM}, {1000, MaxBPM}, {MinBPM, MinBPM}}
+	for _, tt := range tests {
+		m := New()
+		m.SetBPM(tt.in)
+		if m.BPM != tt.want {
+			t.Errorf("SetBPM(%d): BPM %d, want %d", tt.in, m.BPM, tt.want)
+		}
+	}
+}
+
+func TestTap(t *testing.T) {
+	tests := []struct {
+		name    string
+		gaps    []time.Duration // between taps
+		want    int
+		changed bool
+	}{
+		{"one tap", nil, DefaultBPM, false},
+		{"two taps", []time.Duration{500 * time.Millisecond}, 120, true},
+		{"averaged", []time.Duration{600 * time.Millisecond, 400 * time.Millisecond, 500 * time.Millisecond}, 120, true},
+		{"only the last five", []time.Duration{2 * time.Second, time.Second, time.Second, time.Second, time.Second}, 60, true},
+		{"pause starts over", []time.Duration{500 * time.Millisecond, 3 * time.Second}, 120, false},
+		{"clamped", []time.Duration{100 * time.Millisecond}, MaxBPM, true},
+	}
+	for _, tt := range tests {
+		m := New()
+		at := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		changed := m.Tap(at)
+		for _, gap := range tt.gaps {
+			at = at.Add(gap)
+			changed = m.Tap(at)
+		}
+		if m.BPM != tt.want || changed != tt.changed {
+			t.Errorf("%s: BPM %d changed=%v, want %d changed=%v", tt.name, m.BPM, changed, tt.want, tt.changed)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package midi
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/paulgreig/guitar-training/internal/theory"
+)
+
+// NoteEvent is a note pressed or released on a MIDI input.
+type NoteEvent struct {
+	Pitch    theory.Pitch
+	Velocity int
+	On       bool
+	Channel  int
+	Time     time.Time
+}
+
+// Input is a source of played notes. Events is closed when the input ends.
+type Input interface {
+	Events() <-chan NoteEvent
+	Close() error
+}
+
+// inputBuffer is how many events an input queues before the reader blocks.
+const inputBuffer = 64
+
+// Virtual is an in-memory Input: notes are injected with Play and Release.
+// Useful for tests without MIDI hardware.
+type Virtual struct {
+	mu     sync.Mutex
+	ch     chan NoteEvent
+	closed bool
+}
+
+// NewVirtual returns an open virtual input.
+func NewVirtual() *Virtual {
+	return &Virtual{ch: make(chan NoteEvent, inputBuffer)}
+}
+
+// Play sends a note-on. It is dropped once the input is closed, or when
+// inputBuffer events are already waiting to be read.
+func (v *Virtual) Play(p theory.Pitch, velocity int) {
+	v.send(NoteEvent{Pitch: p, Velocity: velocity, On: true, Time: time.Now()})
+}
+
+// Release sends a note-off.
+func (v *Virtual) Release(p theory.Pitch) {
+	v.send(NoteEvent{Pitch: p, Time: time.Now()})
+}
+
+func (v *Virtual) send(e NoteEvent) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.closed {
+		return
+	}
+	// Never block holding mu: Close would wait on a reader that may be gone.
+	select {
+	case v.ch <- e:
+	default:
+	}
+}
+
+// Events implements Input.
+func (v *Virtual) Events() <-chan NoteEvent { return v.ch }
+
+// Close implements Input.
+func (v *Virtual) Close() error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if !v.closed {
+		v.closed = true
+		close(v.ch)
+	}
+	return nil
+}
+
+// Stream is an Input that parses a raw MIDI byte stream, such as an ALSA
+// raw MIDI device (/dev/snd/midiC1D0), a named pipe or a recorded file.
+// Notes are timestamped as they are read.
+type Stream struct {
+	mu     sync.Mutex
+	r      io.ReadCloser
+	ch     chan NoteEvent
+	done   chan struct{} // closed by Close
+	closed bool
+}
+
+// NewStream starts reading MIDI bytes from r.
+func NewStream(r io.ReadCloser) *Stream {
+	s := &Stream{r: r, ch: make(chan NoteEvent, inputBuffer), done: make(chan struct{})}
+	go s.read(r)
+	return s
+}
+
+// OpenStream opens path in the background and reads MIDI bytes from it.
+// Opening a named pipe blocks until something writes to it, so this returns
+// straight away; open errors end the stream.
+func OpenStream(path string) *Stream {
+	s := &Stream{ch: make(chan NoteEvent, inputBuffer), done: make(chan struct{})}
+	go func() {
+		f, err := os.Open(path)
+		if err != nil {
+			close(s.ch)
+			return
+		}
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			f.Close()
+			close(s.ch)
+			return
+		}
+		s.r = f
+		s.mu.Unlock()
+		s.read(f)
+	}()
+	return s
+}
+
+// dataBytes is the number of data bytes after each channel status nibble.
+var dataBytes = map[byte]int{0x80: 2, 0x90: 2, 0xA0: 2, 0xB0: 2, 0xC0: 1, 0xD0: 1, 0xE0: 2}
+
+// read decodes channel messages (with running status), skipping system
+// exclusive, system common and real-time messages, until r fails or the
+// stream is closed.
+func (s *Stream) read(r io.Reader) {
+	defer close(s.ch)
+	br := bufio.NewReader(r)
+	var status byte
+	var data []byte
+	inSysEx := false
+	for {
+		b, err := br.ReadByte()
+		if err != nil {
+			return
+		}
+		switch {
+		case b >= 0xF8: // real-time, may appear anywhere
+			continue
+		case b == 0xF0:
+			inSysEx = true
+			continue
+		case b == 0xF7:
+			inSysEx = false
+			continue
+		case b >= 0xF1: // system common cancels running status
+			status, data, inSysEx = 0, nil, false
+			continue
+		case b >= 0x80:
+			status, data, inSysEx = b, nil, false
+			continue
+		case inSysEx || status == 0:
+			continue
+		}
+		data = append(data, b)
+		if len(data) < dataBytes[status&0xF0] {
+			continue
+		}
+		kind, ch := status&0xF0, int(status&0x0F)
+		if kind == 0x90 || kind == 0x80 {
+			on := kind == 0x90 && data[1] > 0
+			e := NoteEvent{Pitch: theory.Pitch(data[0]), Velocity: int(data[1]), On: on, Channel: ch, Time: time.Now()}
+			select {
+			case s.ch <- e:
+			case <-s.done:
+				return
+			}
+		}
+		data = nil
+	}
+}
+
+// Events implements Input.
+func (s *Stream) Events() <-chan NoteEvent { return s.ch }
+
+// Close implements Input.
+func (s *Stream) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	close(s.done)
+	if s.r == nil {
+		return nil
+	}
+	return s.r.Close()
+}
+
+// OpenInput opens a MIDI input from a MIDI_INPUT style description: a path
+// to a raw MIDI device, named pipe or file (optionally prefixed with
+// "file:"). An empty description or "none" means no input.
+func OpenInput(spec string) (Input, error) {
+	if err := CheckInput(spec); err != nil {
+		return nil, err
+	}
+	if spec == "" || spec == "none" {
+		return nil, nil
+	}
+	return OpenStream(strings.TrimPrefix(spec, "file:")), nil
+}
+
+// CheckInput reports whether OpenInput accepts a description, without
+// opening it: a device, pipe or file must exist.
+func CheckInput(spec string) error {
+	switch spec {
+	case "", "none":
+		return nil
+	}
+	if _, err := os.Stat(strings.TrimPrefix(spec, "file:")); err != nil {
+		return fmt.Errorf("could not open midi input: %w", err)
+	}
+	return nil
+}
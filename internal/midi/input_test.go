@@ -0,0 +1,185 @@
+package midi
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/paulgreig/guitar-training/internal/exercise"
+	"github.com/paulgreig/guitar-training/internal/fretboard"
+	"github.com/paulgreig/guitar-training/internal/theory"
+)
+
+// TestVirtualJudging plays a run into a virtual input and judges the notes
+// that come out, as the scale run screen does.
+func TestVirtualJudging(t *testing.T) {
+	tests := []struct {
+		name   string
+		played []string // "" plays nothing for the step
+		want   []exercise.Verdict
+	}{
+		{"clean", []string{"A2", "B2", "C3"}, []exercise.Verdict{exercise.Correct, exercise.Correct, exercise.Correct}},
+		{"wrong and missed", []string{"A2", "A#2", ""}, []exercise.Verdict{exercise.Correct, exercise.Wrong, exercise.Missed}},
+	}
+	locs := []fretboard.Location{{String: 1, Fret: 0}, {String: 1, Fret: 2}, {String: 1, Fret: 3}}
+	asc, _ := exercise.LookupPattern("Ascending")
+	run := exercise.Run(fretboard.Standard, locs, asc)
+	for _, tt := range tests {
+		in := NewVirtual()
+		j := exercise.NewJudge(run)
+		for step, note := range tt.played {
+			j.Advance(step, time.Now(), time.Second)
+			if note == "" {
+				continue
+			}
+			p := theory.MustParsePitch(note)
+			in.Play(p, 100)
+			in.Release(p)
+			for _, want := range []bool{true, false} {
+				e := <-in.Events()
+				if e.Pitch != p || e.On != want {
+					t.Fatalf("%s: got event %+v, want %s on=%v", tt.name, e, note, want)
+				}
+				if e.On {
+					j.Play(e.Pitch, e.Time)
+				}
+			}
+		}
+		j.Advance(0, time.Now(), time.Second) // the run loops, judging its last note
+		if want := scoreOf(tt.want); j.Score != want {
+			t.Errorf("%s: score %+v, want %+v", tt.name, j.Score, want)
+		}
+		in.Close()
+		in.Play(60, 100) // dropped once closed
+		if _, open := <-in.Events(); open {
+			t.Errorf("%s: events still open after Close", tt.name)
+		}
+	}
+}
+
+func scoreOf(verdicts []exercise.Verdict) exercise.Score {
+	var s exercise.Score
+	for _, v := range verdicts {
+		switch v {
+		case exercise.Correct:
+			s.Correct++
+		case exercise.Late:
+			s.Late++
+		case exercise.Wrong:
+			s.Wrong++
+		case exercise.Missed:
+			s.Missed++
+		}
+	}
+	return s
+}
+
+func TestStream(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want []NoteEvent
+	}{
+		{"note on and off", []byte{0x90, 60, 100, 0x80, 60, 64},
+			[]NoteEvent{{Pitch: 60, Velocity: 100, On: true}, {Pitch: 60, Velocity: 64}}},
+		{"running status, velocity 0 is off", []byte{0x91, 64, 90, 67, 80, 64, 0},
+			[]NoteEvent{{Pitch: 64, Velocity: 90, On: true, Channel: 1}, {Pitch: 67, Velocity: 80, On: true, Channel: 1}, {Pitch: 64, Channel: 1}}},
+		{"real-time inside a message", []byte{0x90, 60, 0xF8, 100},
+			[]NoteEvent{{Pitch: 60, Velocity: 100, On: true}}},
+		{"sysex skipped", []byte{0xF0, 0x7E, 0x90, 0x01, 0xF7, 0x92, 50, 70},
+			[]NoteEvent{{Pitch: 50, Velocity: 70, On: true, Channel: 2}}},
+		{"other messages skipped", []byte{0xB0, 7, 100, 0xC0, 25, 0xE0, 0, 64, 0x90, 40, 1},
+			[]NoteEvent{{Pitch: 40, Velocity: 1, On: true}}},
+		{"system common cancels running status", []byte{0x90, 60, 100, 0xF3, 1, 62, 100},
+			[]NoteEvent{{Pitch: 60, Velocity: 100, On: true}}},
+		{"data before status", []byte{60, 100, 0x90, 61, 100},
+			[]NoteEvent{{Pitch: 61, Velocity: 100, On: true}}},
+	}
+	for _, tt := range tests {
+		s := NewStream(io.NopCloser(bytes.NewReader(tt.data)))
+		var got []NoteEvent
+		for e := range s.Events() {
+			e.Time = time.Time{}
+			got = append(got, e)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestVirtualCloseWhenFull checks Close doesn't wait for someone to read
+// the events.
+func TestVirtualCloseWhenFull(t *testing.T) {
+	in := NewVirtual()
+	done := make(chan struct{})
+	go func() {
+		for range inputBuffer + 10 {
+			in.Play(60, 100)
+		}
+		in.Close()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Play or Close blocked on a full input")
+	}
+	n := 0
+	for range in.Events() {
+		n++
+	}
+	if n != inputBuffer {
+		t.Errorf("got %d events, want the first %d", n, inputBuffer)
+	}
+}
+
+// notes is an endless MIDI stream of middle C note-ons.
+type notes struct{ n int }
+
+func (r *notes) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = []byte{0x90, 60, 100}[r.n%3]
+		r.n++
+	}
+	return len(p), nil
+}
+
+// TestStreamClose checks closing a stream ends its reader, even one blocked
+// on a full buffer nobody reads.
+func TestStreamClose(t *testing.T) {
+	s := NewStream(io.NopCloser(&notes{}))
+	deadline := time.Now().Add(time.Second)
+	for len(s.Events()) < inputBuffer && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond) // let the reader fill the buffer and block
+	}
+	s.Close()
+	s.Close()
+	ended := make(chan struct{})
+	go func() {
+		for range s.Events() {
+		}
+		close(ended)
+	}()
+	select {
+	case <-ended:
+	case <-time.After(time.Second):
+		t.Fatal("events still open after Close")
+	}
+}
+
+func TestOpenInput(t *testing.T) {
+	for _, spec := range []string{"", "none"} {
+		if in, err := OpenInput(spec); in != nil || err != nil {
+			t.Errorf("OpenInput(%q) = %v, %v, want no input", spec, in, err)
+		}
+	}
+	if in, err := OpenInput("virtual"); err == nil || in != nil {
+		t.Errorf("OpenInput(virtual) = %v, %v, want an error", in, err)
+	}
+	if in, err := OpenInput("file:" + t.TempDir() + "/missing.mid"); err == nil || in != nil {
+		t.Errorf("OpenInput(missing file) = %v, %v, want an error", in, err)
+	}
+}
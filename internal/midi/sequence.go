@@ -0,0 +1,144 @@
+package midi
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/paulgreig/guitar-training/internal/exercise"
+	"github.com/paulgreig/guitar-training/internal/fretboard"
+	"github.com/paulgreig/guitar-training/internal/theory"
+)
+
+// DefaultVelocity is used for notes without a velocity.
+const DefaultVelocity = 90
+
+// strumBeats is the delay between strings when a chord is strummed.
+const strumBeats = 1.0 / 32
+
+// Note is a note to export, timed in quarter-note beats.
+type Note struct {
+	Pitch    theory.Pitch
+	String   int // string index, low to high; selects the channel
+	Beat     float64
+	Beats    float64
+	Velocity int // DefaultVelocity when zero
+}
+
+// Sequence is a guitar part ready for export.
+type Sequence struct {
+	Name        string
+	BPM         int
+	Numerator   int // time signature; 4/4 when zero
+	Denominator int
+	Notes       []Note
+}
+
+// StringChannel returns the channel a string plays on: one channel per
+// string, skipping the drum channel, so each string can be edited on its own.
+func StringChannel(str int) int {
+	ch := str
+	if ch >= DrumChannel {
+		ch++
+	}
+	return ch % 16
+}
+
+// File lays the sequence out as a Standard MIDI File. Format 0 puts every
+// event on one track; format 1 has a conductor track with the name, tempo
+// and time signature followed by one track per string played.
+func (s Sequence) File(format int) (File, error) {
+	if format != 0 && format != 1 {
+		return File{}, fmt.Errorf("unsupported midi format %d (want 0 or 1)", format)
+	}
+	if s.BPM <= 0 {
+		return File{}, fmt.Errorf("invalid tempo %d", s.BPM)
+	}
+	num, den := s.Numerator, s.Denominator
+	if num == 0 || den == 0 {
+		num, den = 4, 4
+	}
+
+	conductor := Track{}
+	conductor.Add(TrackName(0, s.Name), Tempo(0, s.BPM), TimeSignature(0, num, den))
+
+	byString := make(map[int]*Track)
+	var strings []int
+	for _, n := range s.Notes {
+		t := byString[n.String]
+		if t == nil {
+			ch := StringChannel(n.String)
+			t = &Track{}
+			if format == 1 {
+				t.Add(TrackName(0, fmt.Sprintf("String %d", n.String+1)))
+			}
+			t.Add(ProgramChange(0, ch, GuitarProgram))
+			byString[n.String] = t
+			strings = append(strings, n.String)
+		}
+		ch := StringChannel(n.String)
+		vel := n.Velocity
+		if vel == 0 {
+			vel = DefaultVelocity
+		}
+		on := ticks(n.Beat)
+		off := ticks(n.Beat + n.Beats)
+		if off <= on {
+			off = on + 1
+		}
+		t.Add(NoteOn(on, ch, int(n.Pitch), vel), NoteOff(off, ch, int(n.Pitch)))
+	}
+	sort.Ints(strings)
+
+	f := File{Format: format, Division: DefaultDivision}
+	if format == 0 {
+		for _, str := range strings {
+			conductor.Add(byString[str].Events...)
+		}
+		f.Tracks = []Track{conductor}
+		return f, nil
+	}
+	f.Tracks = append(f.Tracks, conductor)
+	for _, str := range strings {
+		f.Tracks = append(f.Tracks, *byString[str])
+	}
+	return f, nil
+}
+
+func ticks(beats float64) int {
+	return int(math.Round(beats * DefaultDivision))
+}
+
+// FromRun turns a scale run or exercise into a sequence of one note per beat.
+func FromRun(name string, run []exercise.Note, bpm int) Sequence {
+	s := Sequence{Name: name, BPM: bpm}
+	for i, n := range run {
+		s.Notes = append(s.Notes, Note{Pitch: n.Pitch, String: n.Location.String, Beat: float64(i), Beats: 1})
+	}
+	return s
+}
+
+// Progression turns a chord progression into a sequence: each voicing is
+// strummed low to high at the start of its slot and held for beatsPerChord
+// quarter-note beats.
+func Progression(name string, t fretboard.Tuning, voicings []fretboard.Voicing, bpm int, beatsPerChord float64) Sequence {
+	s := Sequence{Name: name, BPM: bpm}
+	for i, v := range voicings {
+		start := float64(i) * beatsPerChord
+		played := 0
+		for str, fret := range v.Frets {
+			if fret == fretboard.Muted || str >= len(t.Strings) {
+				continue
+			}
+			offset := float64(played) * strumBeats
+			s.Notes = append(s.Notes, Note{
+				Pitch:  t.PitchAt(str, fret),
+				String: str,
+				Beat:   start + offset,
+				Beats:  beatsPerChord - offset,
+			})
+			played++
+		}
+	}
+	return s
+}
@@ -0,0 +1,46 @@
+package midi
+
+import (
+	"math"
+	"testing"
+
+	"github.com/paulgreig/guitar-training/internal/fretboard"
+)
+
+// TestProgressionBarLength checks chords start on the bar lines for
+// lengths that aren't a whole number of quarter notes.
+func TestProgressionBarLength(t *testing.T) {
+	c := fretboard.Voicing{Frets: []int{fretboard.Muted, 3, 2, 0, 1, 0}}
+	g := fretboard.Voicing{Frets: []int{3, 2, 0, 0, 0, 3}}
+	tests := []struct {
+		name          string
+		beatsPerChord float64
+		wantStarts    []float64
+	}{
+		{"4/4", 4, []float64{0, 4, 8}},
+		{"3/4", 3, []float64{0, 3, 6}},
+		{"6/8", 3, []float64{0, 3, 6}},
+		{"7/8", 3.5, []float64{0, 3.5, 7}},
+	}
+	for _, tt := range tests {
+		seq := Progression("test", fretboard.Standard, []fretboard.Voicing{c, g, c}, 120, tt.beatsPerChord)
+		var starts []float64
+		for i, n := range seq.Notes {
+			// The first string of each strum starts the chord.
+			if i == 0 || n.Beat-seq.Notes[i-1].Beat > 1 {
+				starts = append(starts, n.Beat)
+			}
+			if end := n.Beat + n.Beats; math.Abs(end-float64(len(starts))*tt.beatsPerChord) > 1e-9 {
+				t.Errorf("%s: note %d ends at beat %v, want %v", tt.name, i, end, float64(len(starts))*tt.beatsPerChord)
+			}
+		}
+		if len(starts) != len(tt.wantStarts) {
+			t.Fatalf("%s: chords start at %v, want %v", tt.name, starts, tt.wantStarts)
+		}
+		for i := range starts {
+			if starts[i] != tt.wantStarts[i] {
+				t.Errorf("%s: chord %d starts at beat %v, want %v", tt.name, i, starts[i], tt.wantStarts[i])
+			}
+		}
+	}
+}
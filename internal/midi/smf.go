@@ -0,0 +1,176 @@
+package midi
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// DefaultDivision is the number of ticks per quarter note used for export.
+const DefaultDivision = 480
+
+// GuitarProgram is the General MIDI program for Acoustic Guitar (steel), zero-based.
+const GuitarProgram = 25
+
+// DrumChannel is the General MIDI percussion channel (zero-based), which
+// guitar parts must avoid.
+const DrumChannel = 9
+
+// Event is a MIDI channel or meta event at an absolute time in ticks.
+type Event struct {
+	Tick int
+	Data []byte // status byte and data, or 0xFF, type and payload for meta events
+}
+
+// Track is one MIDI track.
+type Track struct {
+	Events []Event
+}
+
+// Add appends events to the track.
+func (t *Track) Add(events ...Event) {
+	t.Events = append(t.Events, events...)
+}
+
+// File is a Standard MIDI File.
+type File struct {
+	Format   int // 0 (single track) or 1 (simultaneous tracks)
+	Division int // ticks per quarter note
+	Tracks   []Track
+}
+
+// NoteOn starts key on channel ch.
+func NoteOn(tick, ch, key, velocity int) Event {
+	return Event{tick, []byte{0x90 | byte(ch&0x0F), byte(key & 0x7F), byte(velocity & 0x7F)}}
+}
+
+// NoteOff releases key on channel ch.
+func NoteOff(tick, ch, key int) Event {
+	return Event{tick, []byte{0x80 | byte(ch&0x0F), byte(key & 0x7F), 0x40}}
+}
+
+// ProgramChange selects the instrument on channel ch.
+func ProgramChange(tick, ch, program int) Event {
+	return Event{tick, []byte{0xC0 | byte(ch&0x0F), byte(program & 0x7F)}}
+}
+
+// Tempo sets the tempo in quarter notes per minute.
+func Tempo(tick, bpm int) Event {
+	us := 60000000 / bpm
+	return meta(tick, 0x51, []byte{byte(us >> 16), byte(us >> 8), byte(us)})
+}
+
+// TimeSignature sets the time signature num/den, e.g. 6/8. den must be a power of two.
+func TimeSignature(tick, num, den int) Event {
+	pow := 0
+	for d := den; d > 1; d >>= 1 {
+		pow++
+	}
+	// 24 MIDI clocks per metronome click, 8 thirty-seconds per quarter.
+	return meta(tick, 0x58, []byte{byte(num), byte(pow), 24, 8})
+}
+
+// TrackName names the track.
+func TrackName(tick int, name string) Event {
+	return meta(tick, 0x03, []byte(name))
+}
+
+func meta(tick int, kind byte, payload []byte) Event {
+	data := []byte{0xFF, kind}
+	data = appendVarLen(data, uint32(len(payload)))
+	return Event{tick, append(data, payload...)}
+}
+
+// appendVarLen appends v as a MIDI variable-length quantity.
+func appendVarLen(b []byte, v uint32) []byte {
+	var tmp [5]byte
+	i := len(tmp) - 1
+	tmp[i] = byte(v & 0x7F)
+	for v >>= 7; v > 0; v >>= 7 {
+		i--
+		tmp[i] = byte(v&0x7F) | 0x80
+	}
+	return append(b, tmp[i:]...)
+}
+
+// encode returns the MTrk chunk body: events in time order with delta times
+// and a closing end-of-track. At equal ticks, note-offs go before other
+// events so a repeated note is released before it is struck again.
+func (t Track) encode() []byte {
+	events := append([]Event(nil), t.Events...)
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].Tick != events[j].Tick {
+			return events[i].Tick < events[j].Tick
+		}
+		return isNoteOff(events[i]) && !isNoteOff(events[j])
+	})
+	var out []byte
+	last := 0
+	for _, e := range events {
+		out = appendVarLen(out, uint32(e.Tick-last))
+		out = append(out, e.Data...)
+		last = e.Tick
+	}
+	out = appendVarLen(out, 0)
+	return append(out, 0xFF, 0x2F, 0x00)
+}
+
+func isNoteOff(e Event) bool {
+	return len(e.Data) > 0 && e.Data[0]&0xF0 == 0x80
+}
+
+// WriteTo writes the file in Standard MIDI File format.
+func (f File) WriteTo(w io.Writer) (int64, error) {
+	if f.Format == 0 && len(f.Tracks) != 1 {
+		return 0, fmt.Errorf("format 0 midi file needs exactly one track, got %d", len(f.Tracks))
+	}
+	var n int64
+	write := func(b []byte) error {
+		m, err := w.Write(b)
+		n += int64(m)
+		return err
+	}
+	header := make([]byte, 14)
+	copy(header, "MThd")
+	binary.BigEndian.PutUint32(header[4:], 6)
+	binary.BigEndian.PutUint16(header[8:], uint16(f.Format))
+	binary.BigEndian.PutUint16(header[10:], uint16(len(f.Tracks)))
+	binary.BigEndian.PutUint16(header[12:], uint16(f.Division))
+	if err := write(header); err != nil {
+		return n, err
+	}
+	for _, t := range f.Tracks {
+		body := t.encode()
+		chunk := make([]byte, 8)
+		copy(chunk, "MTrk")
+		binary.BigEndian.PutUint32(chunk[4:], uint32(len(body)))
+		if err := write(chunk); err != nil {
+			return n, err
+		}
+		if err := write(body); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// Save writes the file to path.
+func (f File) Save(path string) error {
+	out, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("could not create %s: %w", path, err)
+	}
+	bw := bufio.NewWriter(out)
+	if _, err := f.WriteTo(bw); err != nil {
+		out.Close()
+		return fmt.Errorf("could not write %s: %w", path, err)
+	}
+	if err := bw.Flush(); err != nil {
+		out.Close()
+		return fmt.Errorf("could not write %s: %w", path, err)
+	}
+	return out.Close()
+}
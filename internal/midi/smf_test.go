@@ -0,0 +1,191 @@
+package midi
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paulgreig/guitar-training/internal/fretboard"
+	"github.com/paulgreig/guitar-training/internal/theory"
+)
+
+func TestAppendVarLen(t *testing.T) {
+	// Examples from the Standard MIDI File specification.
+	tests := []struct {
+		v    uint32
+		want []byte
+	}{
+		{0x00, []byte{0x00}},
+		{0x40, []byte{0x40}},
+		{0x7F, []byte{0x7F}},
+		{0x80, []byte{0x81, 0x00}},
+		{0x2000, []byte{0xC0, 0x00}},
+		{0x3FFF, []byte{0xFF, 0x7F}},
+		{0x4000, []byte{0x81, 0x80, 0x00}},
+		{0x100000, []byte{0xC0, 0x80, 0x00}},
+		{0x1FFFFF, []byte{0xFF, 0xFF, 0x7F}},
+		{0x200000, []byte{0x81, 0x80, 0x80, 0x00}},
+		{0x0FFFFFFF, []byte{0xFF, 0xFF, 0xFF, 0x7F}},
+	}
+	for _, tt := range tests {
+		if got := appendVarLen(nil, tt.v); !bytes.Equal(got, tt.want) {
+			t.Errorf("appendVarLen(%#x) = % X, want % X", tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestEvents(t *testing.T) {
+	tests := []struct {
+		name string
+		e    Event
+		want []byte
+	}{
+		{"note on", NoteOn(0, 2, 60, 100), []byte{0x92, 60, 100}},
+		{"note off", NoteOff(0, 15, 64), []byte{0x8F, 64, 0x40}},
+		{"program change", ProgramChange(0, 1, GuitarProgram), []byte{0xC1, 25}},
+		{"tempo 120", Tempo(0, 120), []byte{0xFF, 0x51, 0x03, 0x07, 0xA1, 0x20}},
+		{"tempo 60", Tempo(0, 60), []byte{0xFF, 0x51, 0x03, 0x0F, 0x42, 0x40}},
+		{"4/4", TimeSignature(0, 4, 4), []byte{0xFF, 0x58, 0x04, 4, 2, 24, 8}},
+		{"7/8", TimeSignature(0, 7, 8), []byte{0xFF, 0x58, 0x04, 7, 3, 24, 8}},
+		{"track name", TrackName(0, "Riff"), []byte{0xFF, 0x03, 0x04, 'R', 'i', 'f', 'f'}},
+	}
+	for _, tt := range tests {
+		if !bytes.Equal(tt.e.Data, tt.want) {
+			t.Errorf("%s: % X, want % X", tt.name, tt.e.Data, tt.want)
+		}
+	}
+}
+
+func TestWriteTo(t *testing.T) {
+	tr := Track{}
+	// Added out of order, with a repeated note struck as the first ends.
+	tr.Add(NoteOn(480, 0, 60, 90), NoteOff(480, 0, 60), NoteOn(0, 0, 60, 90), NoteOff(960, 0, 60))
+	f := File{Format: 0, Division: 480, Tracks: []Track{tr}}
+	var b bytes.Buffer
+	n, err := f.WriteTo(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []byte{
+		'M', 'T', 'h', 'd', 0, 0, 0, 6, 0, 0, 0, 1, 0x01, 0xE0,
+		'M', 'T', 'r', 'k', 0, 0, 0, 22,
+		0x00, 0x90, 60, 90,
+		0x83, 0x60, 0x80, 60, 0x40, // off before the repeated on
+		0x00, 0x90, 60, 90,
+		0x83, 0x60, 0x80, 60, 0x40,
+		0x00, 0xFF, 0x2F, 0x00,
+	}
+	if !bytes.Equal(b.Bytes(), want) {
+		t.Errorf("got\n% X\nwant\n% X", b.Bytes(), want)
+	}
+	if n != int64(len(want)) {
+		t.Errorf("WriteTo returned %d, wrote %d", n, len(want))
+	}
+}
+
+func TestWriteToFormat0NeedsOneTrack(t *testing.T) {
+	f := File{Format: 0, Division: 480, Tracks: []Track{{}, {}}}
+	if _, err := f.WriteTo(&bytes.Buffer{}); err == nil {
+		t.Error("format 0 with two tracks: no error")
+	}
+}
+
+// readChunks splits a written file into its header and track bodies.
+func readChunks(t *testing.T, data []byte) (format, tracks, division int, bodies [][]byte) {
+	t.Helper()
+	if len(data) < 14 || string(data[:4]) != "MThd" {
+		t.Fatalf("no MThd header")
+	}
+	format = int(binary.BigEndian.Uint16(data[8:]))
+	tracks = int(binary.BigEndian.Uint16(data[10:]))
+	division = int(binary.BigEndian.Uint16(data[12:]))
+	data = data[14:]
+	for len(data) > 0 {
+		if len(data) < 8 || string(data[:4]) != "MTrk" {
+			t.Fatalf("bad track chunk % X", data[:min(len(data), 8)])
+		}
+		size := int(binary.BigEndian.Uint32(data[4:]))
+		if len(data) < 8+size {
+			t.Fatalf("track claims %d bytes, %d left", size, len(data)-8)
+		}
+		body := data[8 : 8+size]
+		if !bytes.HasSuffix(body, []byte{0xFF, 0x2F, 0x00}) {
+			t.Errorf("track %d doesn't end with end-of-track", len(bodies))
+		}
+		bodies = append(bodies, body)
+		data = data[8+size:]
+	}
+	return format, tracks, division, bodies
+}
+
+// TestSequenceFile checks a chord export in both formats: the conductor
+// track, one track per string on its own channel, and the drum channel left
+// free.
+func TestSequenceFile(t *testing.T) {
+	g := fretboard.Voicing{Frets: []int{3, 2, 0, 0, 0, 3}}
+	seq := Progression("G", fretboard.Standard, []fretboard.Voicing{g}, 90, 4)
+	seq.Numerator, seq.Denominator = 6, 8
+	tests := []struct {
+		format, wantTracks int
+	}{
+		{0, 1},
+		{1, 7},
+	}
+	for _, tt := range tests {
+		f, err := seq.File(tt.format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(t.TempDir(), "g.mid")
+		if err := f.Save(path); err != nil {
+			t.Fatal(err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		format, tracks, division, bodies := readChunks(t, data)
+		if format != tt.format || tracks != tt.wantTracks || len(bodies) != tt.wantTracks || division != DefaultDivision {
+			t.Errorf("format %d: header says format %d, %d tracks, division %d; found %d tracks",
+				tt.format, format, tracks, division, len(bodies))
+		}
+		all := bytes.Join(bodies, nil)
+		for _, want := range [][]byte{{0xFF, 0x03, 0x01, 'G'}, Tempo(0, 90).Data, TimeSignature(0, 6, 8).Data} {
+			if !bytes.Contains(bodies[0], want) {
+				t.Errorf("format %d: first track lacks % X", tt.format, want)
+			}
+		}
+		for str := range fretboard.Standard.Strings {
+			ch := StringChannel(str)
+			on := NoteOn(0, ch, int(fretboard.Standard.PitchAt(str, g.Frets[str])), DefaultVelocity).Data
+			if !bytes.Contains(all, on) {
+				t.Errorf("format %d: no note on % X for string %d", tt.format, on, str)
+			}
+		}
+	}
+}
+
+func TestSequenceFileErrors(t *testing.T) {
+	seq := Sequence{Name: "x", BPM: 120, Notes: []Note{{Pitch: theory.MustParsePitch("E2"), Beats: 1}}}
+	if _, err := seq.File(2); err == nil {
+		t.Error("format 2: no error")
+	}
+	seq.BPM = 0
+	if _, err := seq.File(1); err == nil {
+		t.Error("tempo 0: no error")
+	}
+}
+
+func TestStringChannel(t *testing.T) {
+	for str := 0; str < 12; str++ {
+		ch := StringChannel(str)
+		if ch == DrumChannel {
+			t.Errorf("string %d plays on the drum channel", str)
+		}
+		if str < DrumChannel && ch != str || str >= DrumChannel && ch != str+1 {
+			t.Errorf("StringChannel(%d) = %d", str, ch)
+		}
+	}
+}
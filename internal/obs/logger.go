@@ -26,29 +26,37 @@ var (
 	level    = LevelInfo
 )
 
-// InitLogger initialises the global logger.
-// Logs are written to logs/app.log and also to stderr.
-func InitLogger() {
+// ParseLevel returns the level called name: debug, info, warn (or
+// warning) or error.
+func ParseLevel(name string) (Level, error) {
+	switch strings.ToLower(name) {
+	case "debug":
+		return LevelDebug, nil
+	case "info", "":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	}
+	return LevelInfo, fmt.Errorf("unknown log level %q, want debug, info, warn or error", name)
+}
+
+// InitLogger initialises the global logger to write messages at levelName
+// and above to the file at path (logs/app.log if empty), falling back to
+// stderr if the file can't be opened. Only the first call has any effect.
+func InitLogger(levelName, path string) {
 	initOnce.Do(func() {
-		// Determine log level from environment.
-		switch strings.ToLower(os.Getenv("LOG_LEVEL")) {
-		case "debug":
-			level = LevelDebug
-		case "info", "":
-			level = LevelInfo
-		case "warn", "warning":
-			level = LevelWarn
-		case "error":
-			level = LevelError
-		default:
-			level = LevelInfo
-		}
+		// Unknown levels fall back to info.
+		level, _ = ParseLevel(levelName)
 
+		logPath := path
+		if logPath == "" {
+			logPath = filepath.Join("logs", "app.log")
+		}
 		// Ensure logs directory exists.
-		logDir := "logs"
-		_ = os.MkdirAll(logDir, 0o755)
+		_ = os.MkdirAll(filepath.Dir(logPath), 0o755)
 
-		logPath := filepath.Join(logDir, "app.log")
 		file, err := os.OpenFile(logPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
 		if err != nil {
 			// Fallback to stderr only if file can't be opened.
@@ -83,7 +91,7 @@ func levelString(l Level) string {
 func logf(l Level, format string, args ...interface{}) {
 	if logger == nil {
 		// In case InitLogger wasn't called, fall back safely.
-		InitLogger()
+		InitLogger("", "")
 	}
 	if l < level {
 		return
@@ -116,7 +124,7 @@ func Error(format string, args ...interface{}) {
 // WithFields logs key=value style fields for richer observability.
 func WithFields(l Level, msg string, fields map[string]interface{}) {
 	if logger == nil {
-		InitLogger()
+		InitLogger("", "")
 	}
 	if l < level {
 		return
@@ -15,6 +15,8 @@ type Metrics struct {
 	LessonsListViews   uint64
 	ScaleDetailViews   uint64
 	LessonDetailViews  uint64
+	ChordsListViews    uint64
+	ChordDetailViews   uint64
 	DataLoadErrors     uint64
 	DataLoadSuccesses  uint64
 	LastAppStart       int64 // unix nano
@@ -69,6 +71,18 @@ func RecordLessonDetailView() {
 	IncViewsTotal("lesson_detail")
 }
 
+// RecordChordsListView increments the chords list view counter and Prometheus views_total.
+func RecordChordsListView() {
+	incr(&globalMetrics.ChordsListViews)
+	IncViewsTotal("chords_list")
+}
+
+// RecordChordDetailView increments the chord detail view counter and Prometheus views_total.
+func RecordChordDetailView() {
+	incr(&globalMetrics.ChordDetailViews)
+	IncViewsTotal("chord_detail")
+}
+
 // RecordDataLoadError increments the data load error counter and Prometheus data_load_total.
 func RecordDataLoadError() {
 	incr(&globalMetrics.DataLoadErrors)
@@ -91,6 +105,8 @@ func Snapshot() Metrics {
 		LessonsListViews:   atomic.LoadUint64(&globalMetrics.LessonsListViews),
 		ScaleDetailViews:   atomic.LoadUint64(&globalMetrics.ScaleDetailViews),
 		LessonDetailViews:  atomic.LoadUint64(&globalMetrics.LessonDetailViews),
+		ChordsListViews:    atomic.LoadUint64(&globalMetrics.ChordsListViews),
+		ChordDetailViews:   atomic.LoadUint64(&globalMetrics.ChordDetailViews),
 		DataLoadErrors:     atomic.LoadUint64(&globalMetrics.DataLoadErrors),
 		DataLoadSuccesses:  atomic.LoadUint64(&globalMetrics.DataLoadSuccesses),
 		LastAppStart:       atomic.LoadInt64(&globalMetrics.LastAppStart),
@@ -2,7 +2,6 @@ package obs
 
 import (
 	"net/http"
-	"os"
 	"strconv"
 	"sync"
 	"time"
@@ -19,7 +18,7 @@ const (
 var (
 	// Menu selection duration (e.g. time to open scales list or scale detail), by action label.
 	menuSelectionDuration *prometheus.HistogramVec
-	// View counts by action (scales_list, scale_detail, lessons_list, lesson_detail).
+	// View counts by action (scales_list, scale_detail, lessons_list, lesson_detail, chords_list, chord_detail).
 	viewsTotal prometheus.CounterVec
 	// Key presses total.
 	keyPressesTotal prometheus.Counter
@@ -29,6 +28,8 @@ var (
 	reg     *prometheus.Registry
 	initProm sync.Once
 	server  *http.Server
+	// Port the metrics server was started on, 0 when disabled.
+	metricsPort int
 )
 
 func initPrometheusRegistry() {
@@ -53,7 +54,7 @@ func initPrometheusRegistry() {
 		viewsTotal = *prometheus.NewCounterVec(prometheus.CounterOpts{
 			Namespace: metricsNamespace,
 			Name:      "views_total",
-			Help:      "Total number of views by type (scales_list, scale_detail, lessons_list, lesson_detail).",
+			Help:      "Total number of views by type (scales_list, scale_detail, lessons_list, lesson_detail, chords_list, chord_detail).",
 		}, []string{"view"})
 		reg.MustRegister(viewsTotal)
 
@@ -73,17 +74,14 @@ func initPrometheusRegistry() {
 	})
 }
 
-// StartMetricsServer starts an HTTP server that serves /metrics for Prometheus (and Grafana Cloud).
-// Port is read from METRICS_PORT (default "9090"). Disabled if METRICS_PORT is empty or "0".
-func StartMetricsServer() {
+// StartMetricsServer starts an HTTP server that serves /metrics for Prometheus (and Grafana Cloud)
+// on port. Disabled if port is 0.
+func StartMetricsServer(port int) {
 	initPrometheusRegistry()
 
-	port := os.Getenv("METRICS_PORT")
-	if port == "" {
-		port = "9090"
-	}
-	if port == "0" {
-		Info("metrics server disabled (METRICS_PORT=0)")
+	metricsPort = port
+	if port == 0 {
+		Info("metrics server disabled (port 0)")
 		return
 	}
 
@@ -91,7 +89,7 @@ func StartMetricsServer() {
 	mux.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
 
 	server = &http.Server{
-		Addr:         ":" + port,
+		Addr:         ":" + strconv.Itoa(port),
 		Handler:      mux,
 		ReadTimeout:  10 * time.Second,
 		WriteTimeout: 10 * time.Second,
@@ -114,7 +112,7 @@ func StopMetricsServer() {
 }
 
 // RecordMenuSelectionDuration records the duration of a menu selection for performance monitoring.
-// action should be one of: scales_list, scale_detail, lessons_list, lesson_detail.
+// action should be one of: scales_list, scale_detail, lessons_list, lesson_detail, chords_list, chord_detail.
 func RecordMenuSelectionDuration(action string, duration time.Duration) {
 	initPrometheusRegistry()
 	menuSelectionDuration.WithLabelValues(action).Observe(duration.Seconds())
@@ -140,13 +138,5 @@ func IncDataLoadTotal(result string) {
 
 // MetricsPort returns the port number used for the metrics server, or 0 if disabled.
 func MetricsPort() int {
-	portStr := os.Getenv("METRICS_PORT")
-	if portStr == "" {
-		portStr = "9090"
-	}
-	if portStr == "0" {
-		return 0
-	}
-	p, _ := strconv.Atoi(portStr)
-	return p
+	return metricsPort
 }
@@ -0,0 +1,162 @@
+package pitch
+
+import (
+	"math"
+
+	"github.com/paulgreig/guitar-training/internal/theory"
+)
+
+// Detection defaults.
+const (
+	DefaultThreshold = 0.15 // YIN absolute threshold
+	DefaultMinFreq   = 28   // just below a 5-string bass's low B (30.9 Hz)
+	DefaultMaxFreq   = 1400 // above the 24th fret of a guitar's high E
+	silenceRMS       = 0.01 // frames quieter than this are treated as silence
+)
+
+// FrameSize returns the number of samples to detect over for audio at rate:
+// the smallest power of two, and no less than DefaultHop, that holds two
+// periods of DefaultMinFreq. That is 4096 samples at 44.1 and 48 kHz.
+func FrameSize(rate int) int {
+	need := int(math.Ceil(2 * float64(rate) / DefaultMinFreq))
+	n := DefaultHop
+	for n < need {
+		n *= 2
+	}
+	return n
+}
+
+// Estimate is the pitch found in a frame.
+type Estimate struct {
+	Freq    float64      // detected frequency in Hz
+	Pitch   theory.Pitch // nearest note
+	Cents   float64      // deviation from Pitch, -50 to +50
+	Clarity float64      // 0-1, how periodic the frame is
+}
+
+// Detector finds the fundamental frequency of PCM frames using the YIN
+// algorithm (de Cheveigné & Kawahara, 2002).
+type Detector struct {
+	Rate      int
+	A4        float64 // reference pitch for naming notes
+	Threshold float64
+	MinFreq   float64
+	MaxFreq   float64
+}
+
+// NewDetector returns a detector for audio at rate with default settings and A4 = a4 Hz.
+func NewDetector(rate int, a4 float64) Detector {
+	return Detector{
+		Rate:      rate,
+		A4:        a4,
+		Threshold: DefaultThreshold,
+		MinFreq:   DefaultMinFreq,
+		MaxFreq:   DefaultMaxFreq,
+	}
+}
+
+// Detect estimates the pitch of frame. ok is false for silence and frames
+// without a clear pitch. The frame must hold at least two periods of the
+// lowest frequency to be detected.
+func (d Detector) Detect(frame []float64) (est Estimate, ok bool) {
+	if rms(frame) < silenceRMS {
+		return Estimate{}, false
+	}
+	minTau := int(float64(d.Rate) / d.MaxFreq)
+	maxTau := int(float64(d.Rate) / d.MinFreq)
+	if minTau < 2 {
+		minTau = 2
+	}
+	if maxTau > len(frame)/2 {
+		maxTau = len(frame) / 2
+	}
+	if maxTau <= minTau {
+		return Estimate{}, false
+	}
+	window := len(frame) - maxTau
+
+	// Difference function and its cumulative mean normalised form.
+	diff := make([]float64, maxTau+1)
+	for tau := 1; tau <= maxTau; tau++ {
+		sum := 0.0
+		for j := 0; j < window; j++ {
+			delta := frame[j] - frame[j+tau]
+			sum += delta * delta
+		}
+		diff[tau] = sum
+	}
+	cmnd := make([]float64, maxTau+1)
+	cmnd[0] = 1
+	running := 0.0
+	for tau := 1; tau <= maxTau; tau++ {
+		running += diff[tau]
+		if running == 0 {
+			cmnd[tau] = 1
+			continue
+		}
+		cmnd[tau] = diff[tau] * float64(tau) / running
+	}
+
+	// First dip below the threshold, followed down to its minimum. Noisy
+	// frames such as pick attacks may not reach the threshold, so try again
+	// with a looser one rather than taking the global minimum, which tends to
+	// fall on a multiple of the period and name the note an octave low.
+	tau := -1
+	for _, threshold := range []float64{d.Threshold, 2 * d.Threshold} {
+		tau = firstDip(cmnd, minTau, maxTau, threshold)
+		if tau >= 0 {
+			break
+		}
+	}
+	if tau < 0 {
+		return Estimate{}, false
+	}
+
+	// Parabolic interpolation around the dip for sub-sample accuracy.
+	period := float64(tau)
+	if tau > 1 && tau < maxTau {
+		a, b, c := cmnd[tau-1], cmnd[tau], cmnd[tau+1]
+		if den := a - 2*b + c; den != 0 {
+			period += 0.5 * (a - c) / den
+		}
+	}
+	freq := float64(d.Rate) / period
+	p, cents := Nearest(freq, d.A4)
+	return Estimate{Freq: freq, Pitch: p, Cents: cents, Clarity: 1 - math.Min(1, cmnd[tau])}, true
+}
+
+// firstDip returns the first minimum of cmnd in [minTau, maxTau] below
+// threshold, or -1.
+func firstDip(cmnd []float64, minTau, maxTau int, threshold float64) int {
+	for t := minTau; t <= maxTau; t++ {
+		if cmnd[t] < threshold {
+			for t+1 <= maxTau && cmnd[t+1] < cmnd[t] {
+				t++
+			}
+			return t
+		}
+	}
+	return -1
+}
+
+// Nearest returns the note closest to freq with A4 at a4 Hz, and how many
+// cents freq is above (positive) or below it.
+func Nearest(freq, a4 float64) (theory.Pitch, float64) {
+	if a4 == 0 {
+		a4 = theory.StandardA4
+	}
+	semis := 12 * math.Log2(freq/a4)
+	n := math.Round(semis)
+	return theory.Pitch(69 + int(n)), (semis - n) * 100
+}
+
+func rms(frame []float64) float64 {
+	if len(frame) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, v := range frame {
+		sum += v * v
+	}
+	return math.Sqrt(sum / float64(len(frame)))
+}
@@ -0,0 +1,104 @@
+package pitch
+
+import (
+	"math"
+	"testing"
+
+	"github.com/paulgreig/guitar-training/internal/audio"
+	"github.com/paulgreig/guitar-training/internal/synth"
+	"github.com/paulgreig/guitar-training/internal/theory"
+)
+
+func TestFrameSize(t *testing.T) {
+	tests := []struct{ rate, want int }{
+		{8000, DefaultHop},
+		{22050, 2048},
+		{44100, 4096},
+		{48000, 4096},
+		{96000, 8192},
+	}
+	for _, tt := range tests {
+		got := FrameSize(tt.rate)
+		if got != tt.want {
+			t.Errorf("FrameSize(%d) = %d, want %d", tt.rate, got, tt.want)
+		}
+		if periods := float64(got) * DefaultMinFreq / float64(tt.rate); got > DefaultHop && periods < 2 {
+			t.Errorf("FrameSize(%d) = %d holds %.2f periods of %d Hz, want 2", tt.rate, got, periods, DefaultMinFreq)
+		}
+	}
+}
+
+// TestDetect checks YIN names notes across the range of the built-in
+// tunings, from a 5-string bass's low B to the top of a guitar's neck, for
+// sine tones and synthesised plucks.
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		note  string
+		pluck bool
+	}{
+		{"B0", false},
+		{"E1", false},
+		{"E2", false},
+		{"A2", false},
+		{"G3", false},
+		{"E4", false},
+		{"E6", false},
+		{"B0", true},
+		{"E1", true},
+		{"E2", true},
+		{"D3", true},
+		{"B3", true},
+		{"E4", true},
+		{"E5", true},
+	}
+	for _, rate := range []int{44100, 48000} {
+		for _, tt := range tests {
+			p := theory.MustParsePitch(tt.note)
+			freq := p.Frequency(theory.StandardA4)
+			var samples []float64
+			if tt.pluck {
+				samples = synth.Pluck(freq, 1, rate)
+			} else {
+				samples = audio.Tone(freq, 1, 0.5, rate)
+			}
+			// Skip the attack, as a tracker would by the time its window fills.
+			frame := samples[rate/10 : rate/10+FrameSize(rate)]
+			est, ok := NewDetector(rate, theory.StandardA4).Detect(frame)
+			if !ok {
+				t.Errorf("%d Hz, %s pluck=%v: no pitch detected", rate, tt.note, tt.pluck)
+				continue
+			}
+			if est.Pitch != p || math.Abs(est.Cents) > 10 {
+				t.Errorf("%d Hz, %s pluck=%v: got %s %+.1f cents (%.2f Hz), want %.2f Hz",
+					rate, tt.note, tt.pluck, est.Pitch, est.Cents, est.Freq, freq)
+			}
+		}
+	}
+}
+
+func TestDetectSilence(t *testing.T) {
+	rate := 44100
+	if _, ok := NewDetector(rate, theory.StandardA4).Detect(audio.Silence(0.1, rate)); ok {
+		t.Error("silence: detected a pitch")
+	}
+}
+
+func TestNearest(t *testing.T) {
+	tests := []struct {
+		freq, a4  float64
+		want      string
+		wantCents float64
+	}{
+		{440, 440, "A4", 0},
+		{442, 442, "A4", 0},
+		{261.63, 440, "C4", 0},
+		{30.87, 440, "B0", 0},
+		{446.4, 440, "A4", 25},
+	}
+	for _, tt := range tests {
+		p, cents := Nearest(tt.freq, tt.a4)
+		if p.String() != tt.want || math.Abs(cents-tt.wantCents) > 1 {
+			t.Errorf("Nearest(%v, %v) = %s %+.1f cents, want %s %+.1f", tt.freq, tt.a4, p, cents, tt.want, tt.wantCents)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package pitch
+
+import (
+	"io"
+
+	"github.com/paulgreig/guitar-training/internal/audio"
+)
+
+// DefaultHop is how many new samples the tracker reads between detections,
+// about 46 ms at 44.1 kHz.
+const DefaultHop = 2048
+
+// Tracker runs a detector over a sliding window of an audio source.
+type Tracker struct {
+	Source   audio.Source
+	Detector Detector
+	window   []float64
+	hop      []float64
+	filled   int
+}
+
+// NewTracker returns a tracker over src naming notes with A4 = a4 Hz.
+func NewTracker(src audio.Source, a4 float64) *Tracker {
+	return &Tracker{
+		Source:   src,
+		Detector: NewDetector(src.SampleRate(), a4),
+		window:   make([]float64, FrameSize(src.SampleRate())),
+		hop:      make([]float64, DefaultHop),
+	}
+}
+
+// Next reads the next hop of samples and returns the pitch of the latest
+// window. ok is false while the window is filling and for frames without a
+// clear pitch. It returns io.EOF once the source has ended.
+func (t *Tracker) Next() (est Estimate, ok bool, err error) {
+	n := 0
+	for n < len(t.hop) {
+		k, err := t.Source.Read(t.hop[n:])
+		n += k
+		if err != nil {
+			if n == 0 || err != io.EOF {
+				return Estimate{}, false, err
+			}
+			break
+		}
+	}
+	copy(t.window, t.window[n:])
+	copy(t.window[len(t.window)-n:], t.hop[:n])
+	t.filled += n
+	if t.filled < len(t.window) {
+		return Estimate{}, false, nil
+	}
+	est, ok = t.Detector.Detect(t.window)
+	return est, ok, nil
+}
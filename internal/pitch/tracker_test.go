@@ -0,0 +1,155 @@
+package pitch
+
+import (
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/paulgreig/guitar-training/internal/audio"
+	"github.com/paulgreig/guitar-training/internal/synth"
+	"github.com/paulgreig/guitar-training/internal/theory"
+)
+
+// writeWAV saves samples as a 16-bit WAV file in a temporary directory, as
+// the tuner's wav: input reads them.
+func writeWAV(t *testing.T, samples []float64, rate int) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "take.wav")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := audio.WriteWAV(f, samples, rate); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// track runs a tracker over a WAV file to the end and returns its readings.
+func track(t *testing.T, path string, a4 float64) []Estimate {
+	t.Helper()
+	src, err := audio.OpenWAVSource(path, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := NewTracker(src, a4)
+	var out []Estimate
+	for {
+		est, ok, err := tr.Next()
+		if err == io.EOF {
+			return out
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok {
+			out = append(out, est)
+		}
+	}
+}
+
+// TestTrackerWAV checks the tracker names recordings of plucked strings,
+// including detuned ones and ones measured against another reference pitch.
+func TestTrackerWAV(t *testing.T) {
+	tests := []struct {
+		name      string
+		freq, a4  float64
+		want      string
+		wantCents float64
+	}{
+		{"low E", 82.41, 440, "E2", 0},
+		{"open A", 110, 440, "A2", 0},
+		{"high e", 329.63, 440, "E4", 0},
+		{"bass low B", 30.87, 440, "B0", 0},
+		{"flat G", 196 * math.Pow(2, -20.0/1200), 440, "G3", -20},
+		{"sharp D", 146.83 * math.Pow(2, 30.0/1200), 440, "D3", 30},
+		{"A at 442", 442, 442, "A4", 0},
+		{"A440 against 442", 440, 442, "A4", -7.9},
+	}
+	for _, tt := range tests {
+		for _, rate := range []int{44100, 48000} {
+			path := writeWAV(t, synth.Pluck(tt.freq, 1.5, rate), rate)
+			readings := track(t, path, tt.a4)
+			if len(readings) == 0 {
+				t.Errorf("%s at %d Hz: no readings", tt.name, rate)
+				continue
+			}
+			// The attack and the decayed tail may read differently; most
+			// readings must be the note, and their median in tune.
+			var cents []float64
+			for _, est := range readings {
+				if est.Pitch.String() == tt.want {
+					cents = append(cents, est.Cents)
+				}
+			}
+			if len(cents)*4 < len(readings)*3 {
+				t.Errorf("%s at %d Hz: %d of %d readings are %s", tt.name, rate, len(cents), len(readings), tt.want)
+				continue
+			}
+			if m := median(cents); math.Abs(m-tt.wantCents) > 3 {
+				t.Errorf("%s at %d Hz: median %+.1f cents, want %+.1f", tt.name, rate, m, tt.wantCents)
+			}
+		}
+	}
+}
+
+// TestTrackerFollowsNotes checks a recording of several notes in a row
+// reads each in turn.
+func TestTrackerFollowsNotes(t *testing.T) {
+	rate := 44100
+	notes := []string{"E2", "A2", "D3", "G3", "B3", "E4"}
+	var samples []float64
+	for _, n := range notes {
+		samples = append(samples, synth.Pluck(theory.MustParsePitch(n).Frequency(theory.StandardA4), 0.6, rate)...)
+	}
+	var got []string
+	for _, est := range track(t, writeWAV(t, samples, rate), theory.StandardA4) {
+		if name := est.Pitch.String(); len(got) == 0 || got[len(got)-1] != name {
+			got = append(got, name)
+		}
+	}
+	i := 0
+	for _, name := range got {
+		if i < len(notes) && name == notes[i] {
+			i++
+		}
+	}
+	if i != len(notes) {
+		t.Errorf("read %v, want %v in order", got, notes)
+	}
+}
+
+func TestTrackerFilling(t *testing.T) {
+	rate := 44100
+	src := audio.NewSampleSource(audio.Tone(220, 0.5, 0.5, rate), rate, false)
+	tr := NewTracker(src, theory.StandardA4)
+	hops := FrameSize(rate) / DefaultHop
+	for i := 0; i < hops-1; i++ {
+		if _, ok, err := tr.Next(); ok || err != nil {
+			t.Fatalf("hop %d: ok=%v err=%v while the window fills", i, ok, err)
+		}
+	}
+	if est, ok, err := tr.Next(); !ok || err != nil || est.Pitch.String() != "A3" {
+		t.Errorf("full window: got %s ok=%v err=%v, want A3", est.Pitch, ok, err)
+	}
+	for {
+		if _, _, err := tr.Next(); err != nil {
+			if err != io.EOF {
+				t.Errorf("at the end: got %v, want io.EOF", err)
+			}
+			break
+		}
+	}
+}
+
+func median(xs []float64) float64 {
+	s := append([]float64(nil), xs...)
+	sort.Float64s(s)
+	return s[len(s)/2]
+}
@@ -0,0 +1,119 @@
+package practice
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// SM-2 constants.
+const (
+	initialEase = 2.5
+	minEase     = 1.3
+	passQuality = 3
+)
+
+// Item identifies something that can be scheduled for practice.
+type Item struct {
+	Kind  string
+	ID    string // scale name or lesson ID, matching Session.Item
+	Title string
+}
+
+// Card is the spaced-repetition state of one item, derived from its rated sessions.
+type Card struct {
+	Item
+	Reviews    int       // rated sessions so far
+	Repetition int       // consecutive passing reviews
+	Interval   int       // days until the next review
+	Ease       float64   // SM-2 easiness factor
+	LastReview time.Time // zero for items never rated
+	Due        time.Time // zero for new items
+}
+
+// New reports whether the item has never been rated.
+func (c Card) New() bool {
+	return c.Reviews == 0
+}
+
+// review applies one SM-2 review with quality q (1-5) on date at.
+func (c *Card) review(q int, at time.Time) {
+	if c.Ease == 0 {
+		c.Ease = initialEase
+	}
+	if q >= passQuality {
+		switch c.Repetition {
+		case 0:
+			c.Interval = 1
+		case 1:
+			c.Interval = 6
+		default:
+			c.Interval = int(math.Round(float64(c.Interval) * c.Ease))
+		}
+		c.Repetition++
+	} else {
+		c.Repetition = 0
+		c.Interval = 1
+	}
+	d := float64(5 - q)
+	c.Ease += 0.1 - d*(0.08+d*0.02)
+	if c.Ease < minEase {
+		c.Ease = minEase
+	}
+	c.Reviews++
+	c.LastReview = at
+	c.Due = day(at).AddDate(0, 0, c.Interval)
+}
+
+// Schedule replays the rated sessions for each item in chronological order
+// using SM-2 and returns one card per item, in the order given. Self-ratings
+// 1-5 are the review quality; unrated sessions do not affect the schedule.
+// Due dates are days in loc, which should match the time DueToday is given.
+func Schedule(items []Item, sessions []Session, loc *time.Location) []Card {
+	sorted := append([]Session(nil), sessions...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Start.Before(sorted[j].Start) })
+
+	type key struct{ kind, id string }
+	idx := make(map[key]int, len(items))
+	cards := make([]Card, len(items))
+	for i, it := range items {
+		idx[key{it.Kind, it.ID}] = i
+		cards[i] = Card{Item: it, Ease: initialEase}
+	}
+	for _, s := range sorted {
+		i, ok := idx[key{s.Kind, s.Item}]
+		if !ok || s.Rating < 1 || s.Rating > 5 {
+			continue
+		}
+		cards[i].review(s.Rating, s.Start.In(loc))
+	}
+	return cards
+}
+
+// DueToday returns the cards to practise on now's date: reviews that are due
+// (most overdue first), followed by up to newLimit items never practised, in
+// their original order.
+func DueToday(cards []Card, now time.Time, newLimit int) []Card {
+	today := day(now)
+	var due, fresh []Card
+	for _, c := range cards {
+		switch {
+		case c.New():
+			if len(fresh) < newLimit {
+				fresh = append(fresh, c)
+			}
+		case !day(c.Due).After(today):
+			due = append(due, c)
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool { return due[i].Due.Before(due[j].Due) })
+	return append(due, fresh...)
+}
+
+// DaysOverdue is how many days past due the card is on now's date (0 when due today).
+func (c Card) DaysOverdue(now time.Time) int {
+	if c.New() {
+		return 0
+	}
+	return int(math.Round(day(now).Sub(day(c.Due)).Hours() / 24))
+}
@@ -0,0 +1,119 @@
+package practice
+
+import (
+	"math"
+	"slices"
+	"testing"
+	"time"
+)
+
+// rated returns a session on item rated q, on the given day of March 2026 at 18:00 UTC.
+func rated(item string, d, q int) Session {
+	return Session{Kind: KindScale, Item: item, Start: time.Date(2026, 3, d, 18, 0, 0, 0, time.UTC), Seconds: 60, Rating: q}
+}
+
+func TestSchedule(t *testing.T) {
+	tests := []struct {
+		name       string
+		sessions   []Session
+		reviews    int
+		repetition int
+		interval   int
+		ease       float64
+		due        int // day of March; 0 for none
+	}{
+		{"new", nil, 0, 0, 0, 2.5, 0},
+		{"first pass", []Session{rated("C", 1, 4)}, 1, 1, 1, 2.5, 2},
+		{"second pass", []Session{rated("C", 1, 4), rated("C", 2, 4)}, 2, 2, 6, 2.5, 8},
+		{"third pass grows by ease", []Session{rated("C", 1, 4), rated("C", 2, 4), rated("C", 8, 4)}, 3, 3, 15, 2.5, 23},
+		{"easy raises ease", []Session{rated("C", 1, 5), rated("C", 2, 5), rated("C", 8, 5)}, 3, 3, 16, 2.8, 24},
+		{"hard lowers ease", []Session{rated("C", 1, 3)}, 1, 1, 1, 2.36, 2},
+		{"lapse resets", []Session{rated("C", 1, 5), rated("C", 2, 5), rated("C", 8, 5), rated("C", 24, 2)}, 4, 0, 1, 2.48, 25},
+		{"ease floor", []Session{rated("C", 1, 1), rated("C", 2, 1), rated("C", 3, 1), rated("C", 4, 1)}, 4, 0, 1, 1.3, 5},
+		{"replayed in time order", []Session{rated("C", 8, 4), rated("C", 1, 4), rated("C", 2, 4)}, 3, 3, 15, 2.5, 23},
+		{"unrated and other items ignored", []Session{rated("C", 1, 0), rated("D", 1, 5), rated("C", 2, 6), rated("C", 3, 4)}, 1, 1, 1, 2.5, 4},
+	}
+	items := []Item{{Kind: KindScale, ID: "C", Title: "C Major"}}
+	for _, tt := range tests {
+		c := Schedule(items, tt.sessions, time.UTC)[0]
+		if c.Item != items[0] || c.Reviews != tt.reviews || c.Repetition != tt.repetition || c.Interval != tt.interval {
+			t.Errorf("%s: got %+v, want %d reviews, repetition %d, interval %d", tt.name, c, tt.reviews, tt.repetition, tt.interval)
+		}
+		if math.Abs(c.Ease-tt.ease) > 1e-9 {
+			t.Errorf("%s: ease %.2f, want %.2f", tt.name, c.Ease, tt.ease)
+		}
+		var due time.Time
+		if tt.due > 0 {
+			due = time.Date(2026, 3, tt.due, 0, 0, 0, 0, time.UTC)
+		}
+		if !c.Due.Equal(due) || c.New() != (tt.due == 0) {
+			t.Errorf("%s: due %v, want %v", tt.name, c.Due, due)
+		}
+	}
+}
+
+func TestDueToday(t *testing.T) {
+	items := []Item{
+		{Kind: KindScale, ID: "due-later"},
+		{Kind: KindScale, ID: "new-1"},
+		{Kind: KindLesson, ID: "overdue"},
+		{Kind: KindScale, ID: "new-2"},
+		{Kind: KindScale, ID: "due-today"},
+		{Kind: KindScale, ID: "new-3"},
+	}
+	sessions := []Session{
+		rated("due-later", 9, 4), // due on the 10th
+		{Kind: KindLesson, Item: "overdue", Start: time.Date(2026, 3, 5, 9, 0, 0, 0, time.UTC), Rating: 2}, // due on the 6th
+		rated("due-today", 8, 4), // due on the 9th
+	}
+	cards := Schedule(items, sessions, time.UTC)
+	now := time.Date(2026, 3, 9, 23, 59, 0, 0, time.UTC)
+	tests := []struct {
+		newLimit int
+		want     []string
+	}{
+		{2, []string{"overdue", "due-today", "new-1", "new-2"}},
+		{0, []string{"overdue", "due-today"}},
+		{5, []string{"overdue", "due-today", "new-1", "new-2", "new-3"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, c := range DueToday(cards, now, tt.newLimit) {
+			got = append(got, c.ID)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("limit %d: got %v, want %v", tt.newLimit, got, tt.want)
+		}
+	}
+	for id, want := range map[string]int{"overdue": 3, "due-today": 0, "new-1": 0} {
+		for _, c := range cards {
+			if c.ID == id && c.DaysOverdue(now) != want {
+				t.Errorf("%s: %d days overdue, want %d", id, c.DaysOverdue(now), want)
+			}
+		}
+	}
+}
+
+// TestDueTodayLocalDays checks due dates fall on days in the given zone: a
+// session late in the evening UTC is the next morning in New Zealand.
+func TestDueTodayLocalDays(t *testing.T) {
+	items := []Item{{Kind: KindScale, ID: "C"}}
+	cards := Schedule(items, []Session{rated("C", 1, 4)}, nz) // 07:00 on the 2nd in NZ
+	if want := time.Date(2026, 3, 3, 0, 0, 0, 0, nz); !cards[0].Due.Equal(want) {
+		t.Fatalf("due %v, want %v", cards[0].Due, want)
+	}
+	tests := []struct {
+		now time.Time
+		due bool
+	}{
+		{time.Date(2026, 3, 2, 23, 59, 0, 0, nz), false},
+		{time.Date(2026, 3, 3, 0, 0, 0, 0, nz), true},
+		{time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC), true}, // 01:00 on the 3rd in NZ
+	}
+	for _, tt := range tests {
+		now := tt.now.In(nz)
+		if got := len(DueToday(cards, now, 0)) == 1; got != tt.due {
+			t.Errorf("at %v: due %v, want %v", now, got, tt.due)
+		}
+	}
+}
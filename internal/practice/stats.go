@@ -0,0 +1,148 @@
+package practice
+
+import (
+	"sort"
+	"time"
+)
+
+// day truncates t to a calendar day in its own location.
+func day(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// Streaks returns the number of consecutive days up to today with at least
+// one session (yesterday counts, so a streak survives until today ends) and
+// the longest such run ever.
+func Streaks(sessions []Session, now time.Time) (current, longest int) {
+	days := make(map[time.Time]bool)
+	for _, s := range sessions {
+		days[day(s.Start.In(now.Location()))] = true
+	}
+	if len(days) == 0 {
+		return 0, 0
+	}
+	sorted := make([]time.Time, 0, len(days))
+	for d := range days {
+		sorted = append(sorted, d)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Before(sorted[j]) })
+
+	run := 0
+	var prev time.Time
+	for i, d := range sorted {
+		if i > 0 && prev.AddDate(0, 0, 1).Equal(d) {
+			run++
+		} else {
+			run = 1
+		}
+		if run > longest {
+			longest = run
+		}
+		prev = d
+	}
+
+	today := day(now)
+	d := today
+	if !days[d] {
+		d = today.AddDate(0, 0, -1)
+	}
+	for days[d] {
+		current++
+		d = d.AddDate(0, 0, -1)
+	}
+	return current, longest
+}
+
+// Total is the time spent on one item.
+type Total struct {
+	Kind     string
+	Item     string
+	Label    string
+	Duration time.Duration
+	Sessions int
+}
+
+// TotalsByItem sums practice time per item, longest first.
+func TotalsByItem(sessions []Session) []Total {
+	type key struct{ kind, item string }
+	idx := make(map[key]int)
+	var out []Total
+	for _, s := range sessions {
+		k := key{s.Kind, s.Item}
+		i, ok := idx[k]
+		if !ok {
+			i = len(out)
+			idx[k] = i
+			out = append(out, Total{Kind: s.Kind, Item: s.Item, Label: s.Label()})
+		}
+		out[i].Duration += s.Duration()
+		out[i].Sessions++
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Duration > out[j].Duration })
+	return out
+}
+
+// TempoPoint is the best tempo reached on one day.
+type TempoPoint struct {
+	Day time.Time
+	BPM int
+}
+
+// TempoSeries is the tempo history of one exercise on one item.
+type TempoSeries struct {
+	Item     string
+	Label    string
+	Exercise string
+	Points   []TempoPoint // one per day, oldest first
+}
+
+// TempoHistory groups sessions with a tempo by item and exercise and keeps
+// the highest tempo per day, counting days in loc as Streaks does. Series are
+// ordered by most recent activity.
+func TempoHistory(sessions []Session, loc *time.Location) []TempoSeries {
+	type key struct{ item, exercise string }
+	series := make(map[key]*TempoSeries)
+	best := make(map[key]map[time.Time]int)
+	last := make(map[key]time.Time)
+	for _, s := range sessions {
+		if s.TempoBPM == 0 {
+			continue
+		}
+		k := key{s.Item, s.Exercise}
+		if series[k] == nil {
+			series[k] = &TempoSeries{Item: s.Item, Label: s.Label(), Exercise: s.Exercise}
+			best[k] = make(map[time.Time]int)
+		}
+		d := day(s.Start.In(loc))
+		if s.TempoBPM > best[k][d] {
+			best[k][d] = s.TempoBPM
+		}
+		if s.Start.After(last[k]) {
+			last[k] = s.Start
+		}
+	}
+	out := make([]TempoSeries, 0, len(series))
+	for k, ts := range series {
+		for d, bpm := range best[k] {
+			ts.Points = append(ts.Points, TempoPoint{Day: d, BPM: bpm})
+		}
+		sort.Slice(ts.Points, func(i, j int) bool { return ts.Points[i].Day.Before(ts.Points[j].Day) })
+		out = append(out, *ts)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		ki := key{out[i].Item, out[i].Exercise}
+		kj := key{out[j].Item, out[j].Exercise}
+		return last[ki].After(last[kj])
+	})
+	return out
+}
+
+// TotalDuration sums the length of all sessions.
+func TotalDuration(sessions []Session) time.Duration {
+	var d time.Duration
+	for _, s := range sessions {
+		d += s.Duration()
+	}
+	return d
+}
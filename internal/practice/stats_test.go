@@ -0,0 +1,115 @@
+package practice
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// nz is far enough from UTC that evening UTC sessions fall on the next day.
+var nz = time.FixedZone("NZDT", 13*60*60)
+
+// on returns a session starting at the given UTC date and hour.
+func on(month time.Month, d, hour int) Session {
+	return Session{Kind: KindScale, Item: "C Major", Start: time.Date(2026, month, d, hour, 0, 0, 0, time.UTC), Seconds: 60}
+}
+
+func TestStreaks(t *testing.T) {
+	tests := []struct {
+		name     string
+		sessions []Session
+		now      time.Time
+		current  int
+		longest  int
+	}{
+		{"none", nil, time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC), 0, 0},
+		{"today", []Session{on(3, 10, 9)}, time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC), 1, 1},
+		{"yesterday keeps the streak", []Session{on(3, 8, 9), on(3, 9, 9)}, time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC), 2, 2},
+		{"broken streak", []Session{on(3, 7, 9), on(3, 8, 9)}, time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC), 0, 2},
+		{"several a day", []Session{on(3, 9, 9), on(3, 9, 20), on(3, 10, 8)}, time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC), 2, 2},
+		{"longest in the past", []Session{on(2, 1, 9), on(2, 2, 9), on(2, 3, 9), on(3, 10, 9)}, time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC), 1, 3},
+		{"across a month", []Session{on(2, 28, 9), on(3, 1, 9)}, time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC), 2, 2},
+		// 11:00 and 20:00 UTC are 00:00 and 09:00 the next day in NZ.
+		{"days in now's zone", []Session{on(3, 8, 11), on(3, 8, 20)}, time.Date(2026, 3, 10, 9, 0, 0, 0, nz), 1, 1},
+		{"late sessions move", []Session{on(3, 8, 10), on(3, 8, 11)}, time.Date(2026, 3, 10, 9, 0, 0, 0, nz), 2, 2},
+	}
+	for _, tt := range tests {
+		current, longest := Streaks(tt.sessions, tt.now)
+		if current != tt.current || longest != tt.longest {
+			t.Errorf("%s: streaks %d, %d, want %d, %d", tt.name, current, longest, tt.current, tt.longest)
+		}
+	}
+}
+
+func TestTotalsByItem(t *testing.T) {
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	sessions := []Session{
+		{Kind: KindScale, Item: "C Major", Start: start, Seconds: 60},
+		{Kind: KindLesson, Item: "bends", Title: "Bending", Start: start, Seconds: 300},
+		{Kind: KindScale, Item: "C Major", Start: start, Seconds: 120},
+		{Kind: KindQuiz, Item: "C Major", Start: start, Seconds: 180}, // same item, other kind
+	}
+	want := []Total{
+		{Kind: KindLesson, Item: "bends", Label: "Bending", Duration: 5 * time.Minute, Sessions: 1},
+		{Kind: KindScale, Item: "C Major", Label: "C Major", Duration: 3 * time.Minute, Sessions: 2},
+		{Kind: KindQuiz, Item: "C Major", Label: "C Major", Duration: 3 * time.Minute, Sessions: 1},
+	}
+	if got := TotalsByItem(sessions); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	if got := TotalDuration(sessions); got != 11*time.Minute {
+		t.Errorf("TotalDuration = %v, want 11m", got)
+	}
+}
+
+func TestTempoHistory(t *testing.T) {
+	run := func(exercise string, month time.Month, d, hour, bpm int) Session {
+		s := on(month, d, hour)
+		s.Exercise, s.TempoBPM = exercise, bpm
+		return s
+	}
+	sessions := []Session{
+		run("Ascending", 3, 1, 9, 80),
+		run("Ascending", 3, 1, 10, 90), // best of the day
+		run("Ascending", 3, 1, 12, 85), // the next day in NZ
+		run("Thirds", 3, 2, 9, 70),
+		on(3, 3, 9), // no tempo
+		{Kind: KindScale, Item: "A Minor", Exercise: "Ascending", Start: time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC), TempoBPM: 60},
+	}
+	midnight := func(loc *time.Location, d int) time.Time { return time.Date(2026, 3, d, 0, 0, 0, 0, loc) }
+	tests := []struct {
+		loc  *time.Location
+		want []TempoSeries
+	}{
+		{time.UTC, []TempoSeries{
+			{Item: "C Major", Label: "C Major", Exercise: "Thirds", Points: []TempoPoint{{midnight(time.UTC, 2), 70}}},
+			{Item: "C Major", Label: "C Major", Exercise: "Ascending", Points: []TempoPoint{{midnight(time.UTC, 1), 90}}},
+			{Item: "A Minor", Label: "A Minor", Exercise: "Ascending", Points: []TempoPoint{{time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), 60}}},
+		}},
+		{nz, []TempoSeries{
+			{Item: "C Major", Label: "C Major", Exercise: "Thirds", Points: []TempoPoint{{midnight(nz, 2), 70}}},
+			{Item: "C Major", Label: "C Major", Exercise: "Ascending", Points: []TempoPoint{{midnight(nz, 1), 90}, {midnight(nz, 2), 85}}},
+			{Item: "A Minor", Label: "A Minor", Exercise: "Ascending", Points: []TempoPoint{{time.Date(2026, 2, 1, 0, 0, 0, 0, nz), 60}}},
+		}},
+	}
+	for _, tt := range tests {
+		if got := TempoHistory(sessions, tt.loc); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %+v, want %+v", tt.loc, got, tt.want)
+		}
+	}
+}
+
+// TestTempoHistoryMatchesStreaks checks a session falls on the same day in
+// both views.
+func TestTempoHistoryMatchesStreaks(t *testing.T) {
+	s := on(3, 9, 20) // 09:00 on the 10th in NZ
+	s.TempoBPM = 100
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, nz)
+	if current, _ := Streaks([]Session{s}, now); current != 1 {
+		t.Fatalf("streak %d, want 1", current)
+	}
+	got := TempoHistory([]Session{s}, now.Location())[0].Points[0].Day
+	if want := day(now); !got.Equal(want) {
+		t.Errorf("tempo recorded on %v, streak counts %v", got, want)
+	}
+}
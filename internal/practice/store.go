@@ -0,0 +1,133 @@
+package practice
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Kinds of practice item.
+const (
+	KindScale  = "scale"
+	KindLesson = "lesson"
+	KindQuiz   = "quiz"
+)
+
+// Session is one recorded practice session.
+type Session struct {
+	Kind     string    `json:"kind"`               // KindScale, KindLesson or KindQuiz
+	Item     string    `json:"item"`               // scale name, lesson ID or quiz name
+	Title    string    `json:"title,omitempty"`    // display name when Item is an ID
+	Exercise string    `json:"exercise,omitempty"` // e.g. run pattern name
+	Start    time.Time `json:"start"`
+	Seconds  float64   `json:"seconds"`
+	TempoBPM int       `json:"tempo_bpm,omitempty"` // highest tempo reached
+	Rating   int       `json:"rating,omitempty"`    // self-rating 1-5, 0 if skipped
+	Score    int       `json:"score,omitempty"`     // percent played right, from MIDI input
+}
+
+// Duration returns the session length.
+func (s Session) Duration() time.Duration {
+	return time.Duration(s.Seconds * float64(time.Second))
+}
+
+// Label is the display name of the practised item.
+func (s Session) Label() string {
+	if s.Title != "" {
+		return s.Title
+	}
+	return s.Item
+}
+
+// Store is a practice log persisted as a JSON file. It is safe for concurrent use.
+type Store struct {
+	mu       sync.Mutex
+	path     string
+	sessions []Session
+}
+
+// DefaultPath returns the practice log location under the XDG data
+// directory: $XDG_DATA_HOME/guitar-training/practice.json, falling back to
+// ~/.local/share when XDG_DATA_HOME is unset.
+func DefaultPath() (string, error) {
+	dir := os.Getenv("XDG_DATA_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("could not find home directory: %w", err)
+		}
+		dir = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dir, "guitar-training", "practice.json"), nil
+}
+
+// Open loads the practice log at path. A missing file is an empty log.
+func Open(path string) (*Store, error) {
+	s := &Store{path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read practice log: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.sessions); err != nil {
+		return nil, fmt.Errorf("could not parse practice log %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// Path returns the file the store is persisted to.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Sessions returns a copy of all recorded sessions, oldest first.
+func (s *Store) Sessions() []Session {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]Session, len(s.sessions))
+	copy(out, s.sessions)
+	return out
+}
+
+// Add records a session and saves the log.
+func (s *Store) Add(sess Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions = append(s.sessions, sess)
+	return s.save()
+}
+
+// save writes the log atomically via a temporary file. Callers hold s.mu.
+func (s *Store) save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("could not create practice log directory: %w", err)
+	}
+	data, err := json.MarshalIndent(s.sessions, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".practice-*.json")
+	if err != nil {
+		return fmt.Errorf("could not write practice log: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("could not write practice log: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("could not write practice log: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("could not write practice log: %w", err)
+	}
+	return nil
+}
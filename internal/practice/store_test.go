@@ -0,0 +1,86 @@
+package practice
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "practice.json")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatalf("missing log: %v", err)
+	}
+	if got := s.Sessions(); len(got) != 0 {
+		t.Fatalf("missing log has %d sessions", len(got))
+	}
+	start := time.Date(2026, 3, 1, 18, 30, 0, 0, time.UTC)
+	want := []Session{
+		{Kind: KindScale, Item: "A Minor Pentatonic", Exercise: "Ascending", Start: start, Seconds: 90, TempoBPM: 100, Rating: 4},
+		{Kind: KindLesson, Item: "bends", Title: "Bending", Start: start.Add(time.Hour), Seconds: 300},
+	}
+	for _, sess := range want {
+		if err := s.Add(sess); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s.Sessions()[0].Item = "changed" // a copy
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reopened.Sessions(); !reflect.DeepEqual(got, want) {
+		t.Errorf("reopened log has %+v, want %+v", got, want)
+	}
+	if reopened.Path() != path {
+		t.Errorf("Path() = %q, want %q", reopened.Path(), path)
+	}
+	if leftovers, _ := filepath.Glob(filepath.Join(filepath.Dir(path), ".practice-*")); len(leftovers) > 0 {
+		t.Errorf("temporary files left behind: %v", leftovers)
+	}
+}
+
+func TestOpenErrors(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{"corrupt", `[{"kind": "scale",`, "could not parse practice log"},
+		{"wrong shape", `{"kind": "scale"}`, "could not parse practice log"},
+		{"bad time", `[{"kind": "scale", "start": "yesterday"}]`, "could not parse practice log"},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(dir, tt.name+".json")
+		if err := os.WriteFile(path, []byte(tt.data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		s, err := Open(path)
+		if err == nil || s != nil || !strings.Contains(err.Error(), tt.wantErr) || !strings.Contains(err.Error(), path) {
+			t.Errorf("%s: got %v, %v, want an error containing %q and the path", tt.name, s, err, tt.wantErr)
+		}
+	}
+	if _, err := Open(dir); err == nil || !strings.Contains(err.Error(), "could not read practice log") {
+		t.Errorf("directory: got %v, want a read error", err)
+	}
+}
+
+// TestAddUnwritable checks a failed save is reported.
+func TestAddUnwritable(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "log")
+	s, err := Open(filepath.Join(dir, "practice.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(dir, nil, 0o644); err != nil { // a file where the directory should go
+		t.Fatal(err)
+	}
+	if err := s.Add(Session{Kind: KindQuiz, Item: "fretboard"}); err == nil {
+		t.Error("Add with no directory to write to: no error")
+	}
+}
@@ -0,0 +1,251 @@
+package quiz
+
+import (
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/paulgreig/guitar-training/internal/fretboard"
+	"github.com/paulgreig/guitar-training/internal/theory"
+)
+
+// Kind is the type of quiz question.
+type Kind int
+
+// Kinds of question.
+const (
+	NameNote Kind = iota // name the note at a highlighted location
+	FindAll              // select every location of a note
+)
+
+// String returns a short description of the kind.
+func (k Kind) String() string {
+	if k == FindAll {
+		return "Find the notes"
+	}
+	return "Name the note"
+}
+
+// RegionWidth is how many frets a region spans along one string.
+const RegionWidth = 3
+
+// slowAnswer is the response time at which an area counts as fully slow.
+const slowAnswer = 6 * time.Second
+
+// Region is a block of RegionWidth frets on one string; accuracy and
+// response time are tracked per region.
+type Region struct {
+	String int
+	Block  int
+}
+
+// RegionOf returns the region containing loc.
+func RegionOf(loc fretboard.Location) Region {
+	return Region{String: loc.String, Block: loc.Fret / RegionWidth}
+}
+
+// Frets returns the first and last fret of the region.
+func (r Region) Frets() (lo, hi int) {
+	lo = r.Block * RegionWidth
+	return lo, lo + RegionWidth - 1
+}
+
+// Score is the answer history of one region.
+type Score struct {
+	Asked   int
+	Correct int
+	Time    time.Duration // total response time
+}
+
+// Accuracy returns the fraction of correct answers, or 0 when nothing has been asked.
+func (s Score) Accuracy() float64 {
+	if s.Asked == 0 {
+		return 0
+	}
+	return float64(s.Correct) / float64(s.Asked)
+}
+
+// MeanTime returns the average response time.
+func (s Score) MeanTime() time.Duration {
+	if s.Asked == 0 {
+		return 0
+	}
+	return s.Time / time.Duration(s.Asked)
+}
+
+// weight is the relative chance of asking about a region: unseen regions
+// and those answered wrongly or slowly come up more often.
+func (s Score) weight() float64 {
+	if s.Asked == 0 {
+		return 3
+	}
+	slow := float64(s.MeanTime()) / float64(slowAnswer)
+	if slow > 1 {
+		slow = 1
+	}
+	return 1 + 4*(1-s.Accuracy()) + 2*slow
+}
+
+// Question is one quiz question.
+type Question struct {
+	Kind     Kind
+	Location fretboard.Location   // highlighted location (NameNote) or the one the target was drawn from
+	Target   theory.PitchClass    // the note to name or find
+	Answers  []fretboard.Location // every location of Target (FindAll)
+	Asked    time.Time
+}
+
+// Quiz generates questions on one tuning, biased toward weak regions.
+type Quiz struct {
+	Tuning fretboard.Tuning
+	Frets  int
+	Stats  map[Region]Score
+	rng    *rand.Rand
+}
+
+// New returns a quiz over frets 0..frets of t.
+func New(t fretboard.Tuning, frets int, seed int64) *Quiz {
+	return &Quiz{
+		Tuning: t,
+		Frets:  frets,
+		Stats:  make(map[Region]Score),
+		rng:    rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Regions lists every region on the neck, low string and low frets first.
+func (q *Quiz) Regions() []Region {
+	var out []Region
+	for s := range q.Tuning.Strings {
+		for b := 0; b*RegionWidth <= q.Frets; b++ {
+			out = append(out, Region{String: s, Block: b})
+		}
+	}
+	return out
+}
+
+// Next returns a new question of the given kind asked at time at.
+func (q *Quiz) Next(kind Kind, at time.Time) Question {
+	r := q.pickRegion()
+	lo, hi := r.Frets()
+	if hi > q.Frets {
+		hi = q.Frets
+	}
+	loc := fretboard.Location{String: r.String, Fret: lo + q.rng.Intn(hi-lo+1)}
+	pc := q.Tuning.PitchAt(loc.String, loc.Fret).PitchClass()
+	qn := Question{Kind: kind, Location: loc, Target: pc, Asked: at}
+	if kind == FindAll {
+		qn.Answers = q.Tuning.Find([]theory.PitchClass{pc}, q.Frets)
+	}
+	return qn
+}
+
+func (q *Quiz) pickRegion() Region {
+	regions := q.Regions()
+	total := 0.0
+	weights := make([]float64, len(regions))
+	for i, r := range regions {
+		weights[i] = q.Stats[r].weight()
+		total += weights[i]
+	}
+	x := q.rng.Float64() * total
+	for i, w := range weights {
+		if x < w {
+			return regions[i]
+		}
+		x -= w
+	}
+	return regions[len(regions)-1]
+}
+
+func (q *Quiz) record(loc fretboard.Location, correct bool, elapsed time.Duration) {
+	r := RegionOf(loc)
+	s := q.Stats[r]
+	s.Asked++
+	if correct {
+		s.Correct++
+	}
+	s.Time += elapsed
+	q.Stats[r] = s
+}
+
+// AnswerName checks a NameNote answer given at time at and records it.
+// Enharmonic spellings are accepted.
+func (q *Quiz) AnswerName(qn Question, answer theory.PitchClass, at time.Time) bool {
+	correct := answer == qn.Target
+	q.record(qn.Location, correct, at.Sub(qn.Asked))
+	return correct
+}
+
+// FindResult is the outcome of a FindAll answer.
+type FindResult struct {
+	Found  int                  // correct locations selected
+	Missed []fretboard.Location // correct locations not selected
+	Wrong  []fretboard.Location // selected locations holding another note
+}
+
+// Correct reports whether every location was found and nothing else selected.
+func (r FindResult) Correct() bool {
+	return len(r.Missed) == 0 && len(r.Wrong) == 0
+}
+
+// AnswerFind checks a FindAll answer given at time at and records each
+// location's region: found locations as correct, missed and wrongly
+// selected ones as mistakes.
+func (q *Quiz) AnswerFind(qn Question, selected []fretboard.Location, at time.Time) FindResult {
+	elapsed := at.Sub(qn.Asked)
+	picked := make(map[fretboard.Location]bool, len(selected))
+	for _, loc := range selected {
+		picked[loc] = true
+	}
+	var res FindResult
+	for _, loc := range qn.Answers {
+		if picked[loc] {
+			res.Found++
+			delete(picked, loc)
+			q.record(loc, true, elapsed)
+		} else {
+			res.Missed = append(res.Missed, loc)
+			q.record(loc, false, elapsed)
+		}
+	}
+	for loc := range picked {
+		res.Wrong = append(res.Wrong, loc)
+		q.record(loc, false, elapsed)
+	}
+	sort.Slice(res.Wrong, func(i, j int) bool {
+		a, b := res.Wrong[i], res.Wrong[j]
+		if a.String != b.String {
+			return a.String < b.String
+		}
+		return a.Fret < b.Fret
+	})
+	return res
+}
+
+// Weakest returns up to n asked regions with the lowest accuracy, slowest first on ties.
+func (q *Quiz) Weakest(n int) []Region {
+	var out []Region
+	for r, s := range q.Stats {
+		if s.Asked > 0 && s.Correct < s.Asked {
+			out = append(out, r)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := q.Stats[out[i]], q.Stats[out[j]]
+		if a.Accuracy() != b.Accuracy() {
+			return a.Accuracy() < b.Accuracy()
+		}
+		if a.MeanTime() != b.MeanTime() {
+			return a.MeanTime() > b.MeanTime()
+		}
+		if out[i].String != out[j].String {
+			return out[i].String < out[j].String
+		}
+		return out[i].Block < out[j].Block
+	})
+	if len(out) > n {
+		out = out[:n]
+	}
+	return out
+}
@@ -0,0 +1,199 @@
+package quiz
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/paulgreig/guitar-training/internal/fretboard"
+	"github.com/paulgreig/guitar-training/internal/theory"
+)
+
+var start = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func TestRegion(t *testing.T) {
+	tests := []struct {
+		loc    fretboard.Location
+		want   Region
+		lo, hi int
+	}{
+		{fretboard.Location{String: 0, Fret: 0}, Region{0, 0}, 0, 2},
+		{fretboard.Location{String: 2, Fret: 2}, Region{2, 0}, 0, 2},
+		{fretboard.Location{String: 5, Fret: 3}, Region{5, 1}, 3, 5},
+		{fretboard.Location{String: 1, Fret: 12}, Region{1, 4}, 12, 14},
+	}
+	for _, tt := range tests {
+		r := RegionOf(tt.loc)
+		lo, hi := r.Frets()
+		if r != tt.want || lo != tt.lo || hi != tt.hi {
+			t.Errorf("RegionOf(%+v) = %+v frets %d-%d, want %+v frets %d-%d", tt.loc, r, lo, hi, tt.want, tt.lo, tt.hi)
+		}
+	}
+}
+
+func TestScoreWeight(t *testing.T) {
+	tests := []struct {
+		name  string
+		score Score
+		want  float64
+	}{
+		{"unseen", Score{}, 3},
+		{"right and quick", Score{Asked: 4, Correct: 4}, 1},
+		{"half right", Score{Asked: 4, Correct: 2}, 3},
+		{"wrong and slow", Score{Asked: 2, Time: 4 * slowAnswer}, 7},
+		{"right but slow", Score{Asked: 2, Correct: 2, Time: slowAnswer}, 2},
+	}
+	for _, tt := range tests {
+		if got := tt.score.weight(); got != tt.want {
+			t.Errorf("%s: weight = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestNext checks questions are on the neck, name the note they point at
+// and, for the same seed, come in the same order.
+func TestNext(t *testing.T) {
+	a := New(fretboard.Standard, 12, 7)
+	b := New(fretboard.Standard, 12, 7)
+	for i := 0; i < 200; i++ {
+		kind := Kind(i % 2)
+		qa, qb := a.Next(kind, start), b.Next(kind, start)
+		if !reflect.DeepEqual(qa, qb) {
+			t.Fatalf("question %d differs for the same seed: %+v and %+v", i, qa, qb)
+		}
+		loc := qa.Location
+		if loc.String < 0 || loc.String >= 6 || loc.Fret < 0 || loc.Fret > 12 {
+			t.Fatalf("question %d is off the neck at %+v", i, loc)
+		}
+		if pc := fretboard.Standard.PitchAt(loc.String, loc.Fret).PitchClass(); qa.Target != pc {
+			t.Errorf("question %d asks for %s at %+v, which is %s", i, qa.Target, loc, pc)
+		}
+		if kind == FindAll {
+			want := fretboard.Standard.Find([]theory.PitchClass{qa.Target}, 12)
+			if !reflect.DeepEqual(qa.Answers, want) {
+				t.Errorf("question %d answers = %v, want %v", i, qa.Answers, want)
+			}
+		} else if qa.Answers != nil {
+			t.Errorf("question %d names a note but has answers %v", i, qa.Answers)
+		}
+	}
+}
+
+// TestNextWeighting checks a region answered wrongly and slowly comes up
+// far more often than regions answered well.
+func TestNextWeighting(t *testing.T) {
+	q := New(fretboard.Standard, 12, 1)
+	weak := Region{String: 3, Block: 2}
+	for _, r := range q.Regions() {
+		q.Stats[r] = Score{Asked: 5, Correct: 5, Time: 5 * time.Second}
+	}
+	q.Stats[weak] = Score{Asked: 5, Time: 5 * slowAnswer}
+
+	counts := map[Region]int{}
+	const n = 5000
+	for i := 0; i < n; i++ {
+		counts[RegionOf(q.Next(NameNote, start).Location)]++
+	}
+	// The weak region weighs 7 to the others' 1, so it is asked about 7
+	// times as often as each of them.
+	others := float64(n-counts[weak]) / float64(len(q.Regions())-1)
+	if ratio := float64(counts[weak]) / others; ratio < 5 || ratio > 9 {
+		t.Errorf("weak region asked %d times, %.1f times the others", counts[weak], ratio)
+	}
+	for _, r := range q.Regions() {
+		if counts[r] == 0 {
+			t.Errorf("region %+v never asked", r)
+		}
+	}
+}
+
+func TestAnswerName(t *testing.T) {
+	q := New(fretboard.Standard, 12, 1)
+	loc := fretboard.Location{String: 0, Fret: 4} // G#2
+	qn := Question{Kind: NameNote, Location: loc, Target: fretboard.Standard.PitchAt(0, 4).PitchClass(), Asked: start}
+
+	if !q.AnswerName(qn, theory.MustParseNote("Ab").PitchClass(), start.Add(2*time.Second)) {
+		t.Errorf("Ab wasn't accepted for G#")
+	}
+	if q.AnswerName(qn, theory.MustParseNote("A").PitchClass(), start.Add(4*time.Second)) {
+		t.Errorf("A was accepted for G#")
+	}
+	want := Score{Asked: 2, Correct: 1, Time: 6 * time.Second}
+	if got := q.Stats[RegionOf(loc)]; got != want {
+		t.Errorf("stats = %+v, want %+v", got, want)
+	}
+	if got := q.Stats[RegionOf(loc)].MeanTime(); got != 3*time.Second {
+		t.Errorf("mean time = %v, want 3s", got)
+	}
+}
+
+func TestAnswerFind(t *testing.T) {
+	q := New(fretboard.Standard, 5, 1)
+	target := theory.MustParseNote("A").PitchClass()
+	qn := Question{
+		Kind:    FindAll,
+		Target:  target,
+		Answers: fretboard.Standard.Find([]theory.PitchClass{target}, 5),
+		Asked:   start,
+	}
+	// A is at string 0 fret 5, string 1 fret 0, string 3 fret 2 and string 5
+	// fret 5.
+	selected := []fretboard.Location{
+		{String: 1, Fret: 0},
+		{String: 4, Fret: 1}, // C
+		{String: 3, Fret: 2},
+		{String: 2, Fret: 1}, // D#
+	}
+	res := q.AnswerFind(qn, selected, start.Add(3*time.Second))
+	if res.Found != 2 {
+		t.Errorf("found %d, want 2", res.Found)
+	}
+	if want := []fretboard.Location{{String: 0, Fret: 5}, {String: 5, Fret: 5}}; !reflect.DeepEqual(res.Missed, want) {
+		t.Errorf("missed %v, want %v", res.Missed, want)
+	}
+	if want := []fretboard.Location{{String: 2, Fret: 1}, {String: 4, Fret: 1}}; !reflect.DeepEqual(res.Wrong, want) {
+		t.Errorf("wrong %v, want %v", res.Wrong, want)
+	}
+	if res.Correct() {
+		t.Errorf("an answer with misses and mistakes is correct")
+	}
+
+	wantStats := map[Region]Score{
+		{String: 0, Block: 1}: {Asked: 1, Time: 3 * time.Second},
+		{String: 1, Block: 0}: {Asked: 1, Correct: 1, Time: 3 * time.Second},
+		{String: 3, Block: 0}: {Asked: 1, Correct: 1, Time: 3 * time.Second},
+		{String: 2, Block: 0}: {Asked: 1, Time: 3 * time.Second},
+		{String: 4, Block: 0}: {Asked: 1, Time: 3 * time.Second},
+		{String: 5, Block: 1}: {Asked: 1, Time: 3 * time.Second},
+	}
+	if !reflect.DeepEqual(q.Stats, wantStats) {
+		t.Errorf("stats = %v, want %v", q.Stats, wantStats)
+	}
+
+	res = q.AnswerFind(qn, qn.Answers, start.Add(time.Second))
+	if !res.Correct() || res.Found != 4 {
+		t.Errorf("selecting every answer gave %+v", res)
+	}
+}
+
+func TestWeakest(t *testing.T) {
+	q := New(fretboard.Standard, 12, 1)
+	q.Stats = map[Region]Score{
+		{String: 0, Block: 0}: {Asked: 4, Correct: 4, Time: 4 * time.Second}, // never wrong
+		{String: 1, Block: 0}: {Asked: 4, Correct: 2, Time: 4 * time.Second},
+		{String: 2, Block: 0}: {Asked: 4, Correct: 1, Time: 4 * time.Second},
+		{String: 3, Block: 0}: {Asked: 4, Correct: 2, Time: 20 * time.Second}, // as accurate as 1, slower
+		{String: 4, Block: 0}: {Asked: 4, Correct: 2, Time: 4 * time.Second},  // ties with 1
+		{String: 5, Block: 0}: {},                                             // never asked
+	}
+	want := []Region{{2, 0}, {3, 0}, {1, 0}, {4, 0}}
+	if got := q.Weakest(10); !reflect.DeepEqual(got, want) {
+		t.Errorf("Weakest(10) = %v, want %v", got, want)
+	}
+	if got := q.Weakest(2); !reflect.DeepEqual(got, want[:2]) {
+		t.Errorf("Weakest(2) = %v, want %v", got, want[:2])
+	}
+	if got := New(fretboard.Standard, 12, 1).Weakest(3); len(got) != 0 {
+		t.Errorf("Weakest of a new quiz = %v", got)
+	}
+}
@@ -0,0 +1,649 @@
+package score
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+
+	"github.com/paulgreig/guitar-training/internal/tab"
+	"github.com/paulgreig/guitar-training/internal/theory"
+)
+
+// Limits on counts read from Guitar Pro files, so a corrupt file fails
+// quickly instead of reading billions of empty records.
+const (
+	gpMaxMeasures = 10000
+	gpMaxTracks   = 128
+	gpMaxBeats    = 1024
+	gpMaxString   = 1 << 16
+	gpMaxPoints   = 64
+)
+
+// gpVersion matches the version string at the start of a Guitar Pro file,
+// such as "FICHIER GUITAR PRO v5.10".
+var gpVersion = regexp.MustCompile(`^FICHIER GUITAR PRO v([345])\.(\d\d)`)
+
+// gpBendSemitone is the bend value of a semitone (a full bend is 100).
+const gpBendSemitone = 50
+
+// gpReader reads the little-endian primitives of a Guitar Pro file. The
+// first error is kept and later reads return zero values, so records can be
+// read field by field and the error checked once.
+type gpReader struct {
+	r       *bufio.Reader
+	version int // 300, 400, 406, 500 or 510
+	err     error
+}
+
+func (g *gpReader) read(n int) []byte {
+	if g.err != nil {
+		return make([]byte, n)
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(g.r, buf); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		g.err = err
+	}
+	return buf
+}
+
+func (g *gpReader) skip(n int) { g.read(n) }
+func (g *gpReader) u8() int    { return int(g.read(1)[0]) }
+func (g *gpReader) i8() int    { return int(int8(g.read(1)[0])) }
+func (g *gpReader) bool() bool { return g.u8() != 0 }
+func (g *gpReader) i16() int   { return int(int16(binary.LittleEndian.Uint16(g.read(2)))) }
+func (g *gpReader) i32() int   { return int(int32(binary.LittleEndian.Uint32(g.read(4)))) }
+func (g *gpReader) fail(format string, args ...interface{}) {
+	if g.err == nil {
+		g.err = fmt.Errorf(format, args...)
+	}
+}
+
+// count reads a record count and checks it against limit.
+func (g *gpReader) count(what string, limit int) int {
+	n := g.i32()
+	if n < 0 || n > limit {
+		g.fail("%s count %d out of range", what, n)
+		return 0
+	}
+	return n
+}
+
+// byteSizeString reads a string stored as a length byte followed by a
+// fixed-size field.
+func (g *gpReader) byteSizeString(size int) string {
+	n := g.u8()
+	buf := g.read(size)
+	return latin1(buf[:min(n, size)])
+}
+
+// intByteSizeString reads a string stored as an int32 field size, then a
+// length byte and the text.
+func (g *gpReader) intByteSizeString() string {
+	size := g.i32()
+	if size < 0 || size > gpMaxString {
+		g.fail("string size %d out of range", size)
+		return ""
+	}
+	if size == 0 {
+		return ""
+	}
+	n := g.u8()
+	buf := g.read(size - 1)
+	return latin1(buf[:min(n, size-1)])
+}
+
+// intSizeString reads a string stored as an int32 length and the text.
+func (g *gpReader) intSizeString() string {
+	n := g.i32()
+	if n < 0 || n > gpMaxString {
+		g.fail("string size %d out of range", n)
+		return ""
+	}
+	return latin1(g.read(n))
+}
+
+// latin1 decodes text stored in the files' single-byte encoding.
+func latin1(b []byte) string {
+	r := make([]rune, len(b))
+	for i, c := range b {
+		r[i] = rune(c)
+	}
+	return string(r)
+}
+
+// gpTrack is a track being read, with the state carried from beat to beat.
+type gpTrack struct {
+	Track
+	drums   bool
+	pending map[int]gpLink // technique started on each string, by string index
+}
+
+// gpLink is a hammer-on, pull-off or slide started on a note, which joins it
+// to the next note on the string.
+type gpLink struct {
+	legato bool
+	fret   int
+}
+
+// ReadGP reads a Guitar Pro 3, 4 or 5 file (.gp3, .gp4, .gp5). Every track
+// except percussion becomes a track of the score; only the first voice of a
+// Guitar Pro 5 track is read.
+func ReadGP(r io.Reader) (*Score, error) {
+	g := &gpReader{r: bufio.NewReader(r)}
+	v := g.byteSizeString(30)
+	if g.err != nil {
+		return nil, fmt.Errorf("could not read Guitar Pro header: %w", g.err)
+	}
+	m := gpVersion.FindStringSubmatch(v)
+	if m == nil {
+		return nil, fmt.Errorf("not a Guitar Pro 3-5 file (version %q)", v)
+	}
+	major, _ := strconv.Atoi(m[1])
+	minor, _ := strconv.Atoi(m[2])
+	g.version = major*100 + minor
+
+	s := &Score{}
+	tracks := g.readSong(s)
+	if g.err != nil {
+		return nil, fmt.Errorf("could not read Guitar Pro file: %w", g.err)
+	}
+	for _, t := range tracks {
+		if !t.drums && len(t.Strings) > 0 {
+			s.Tracks = append(s.Tracks, t.Track)
+		}
+	}
+	if len(s.Tracks) == 0 {
+		return nil, fmt.Errorf("no guitar tracks found")
+	}
+	return s, nil
+}
+
+func (g *gpReader) readSong(s *Score) []*gpTrack {
+	// Song information.
+	s.Title = g.intByteSizeString()
+	g.intByteSizeString() // subtitle
+	s.Artist = g.intByteSizeString()
+	g.intByteSizeString() // album
+	g.intByteSizeString() // words
+	if g.version >= 500 {
+		g.intByteSizeString() // music
+	}
+	g.intByteSizeString() // copyright
+	g.intByteSizeString() // tab author
+	g.intByteSizeString() // instructions
+	for i, n := 0, g.count("notice", gpMaxMeasures); i < n; i++ {
+		g.intByteSizeString()
+	}
+
+	if g.version < 500 {
+		g.bool() // triplet feel
+	}
+	if g.version >= 400 {
+		g.i32() // lyrics track
+		for i := 0; i < 5; i++ {
+			g.i32() // starting measure
+			g.intSizeString()
+		}
+	}
+	if g.version >= 510 {
+		g.skip(19) // RSE master effect
+	}
+	if g.version >= 500 {
+		g.skip(30) // page size, margins, proportion and header flags
+		for i := 0; i < 10; i++ {
+			g.intByteSizeString() // header and footer templates
+		}
+		g.intByteSizeString() // tempo name
+	}
+	g.i32() // tempo
+	if g.version >= 510 {
+		g.bool() // hide tempo
+	}
+	if g.version >= 400 {
+		g.skip(5) // key and octave
+	} else {
+		g.skip(4) // key
+	}
+	g.skip(64 * 12) // MIDI channels
+	if g.version >= 500 {
+		g.skip(42) // directions (coda, segno, ...) and master reverb
+	}
+	measures := g.count("measure", gpMaxMeasures)
+	trackCount := g.count("track", gpMaxTracks)
+
+	for i := 0; i < measures; i++ {
+		g.readMeasureHeader(i)
+	}
+	tracks := make([]*gpTrack, trackCount)
+	for i := range tracks {
+		tracks[i] = g.readTrack(i)
+	}
+	if g.version >= 500 {
+		if g.version == 500 {
+			g.skip(2)
+		} else {
+			g.skip(1)
+		}
+	}
+	for i := 0; i < measures && g.err == nil; i++ {
+		for _, t := range tracks {
+			t.Bars = append(t.Bars, g.readMeasure(t))
+		}
+	}
+	return tracks
+}
+
+// readMeasureHeader skips a measure header: time signature, repeats,
+// markers and key changes are not needed for tab.
+func (g *gpReader) readMeasureHeader(i int) {
+	if g.version >= 500 && i > 0 {
+		g.skip(1)
+	}
+	flags := g.u8()
+	if flags&0x01 != 0 {
+		g.i8() // numerator
+	}
+	if flags&0x02 != 0 {
+		g.i8() // denominator
+	}
+	if flags&0x08 != 0 {
+		g.i8() // repeat close
+	}
+	if g.version < 500 {
+		if flags&0x10 != 0 {
+			g.u8() // alternative ending
+		}
+		if flags&0x20 != 0 {
+			g.intByteSizeString() // marker
+			g.skip(4)             // marker colour
+		}
+		if flags&0x40 != 0 {
+			g.skip(2) // key signature
+		}
+		return
+	}
+	if flags&0x20 != 0 {
+		g.intByteSizeString()
+		g.skip(4)
+	}
+	if flags&0x40 != 0 {
+		g.skip(2)
+	}
+	if flags&0x10 != 0 {
+		g.u8()
+	}
+	if flags&0x03 != 0 {
+		g.skip(4) // beam groups
+	}
+	if flags&0x10 == 0 {
+		g.skip(1)
+	}
+	g.u8() // triplet feel
+}
+
+func (g *gpReader) readTrack(i int) *gpTrack {
+	if g.version >= 500 && (i == 0 || g.version == 500) {
+		g.skip(1)
+	}
+	t := &gpTrack{pending: map[int]gpLink{}}
+	flags := g.u8()
+	t.Name = g.byteSizeString(40)
+	strs := g.i32()
+	if strs < 0 || strs > 7 {
+		g.fail("track %d: %d strings", i+1, strs)
+		strs = 0
+	}
+	tuning := make([]int, 7)
+	for s := range tuning {
+		tuning[s] = g.i32()
+	}
+	// Strings are stored highest first.
+	for s := strs - 1; s >= 0; s-- {
+		t.Strings = append(t.Strings, theory.Pitch(tuning[s]))
+	}
+	g.i32() // MIDI port
+	channel := g.i32()
+	g.i32()   // effect channel
+	g.i32()   // frets
+	g.i32()   // capo
+	g.skip(4) // colour
+	t.drums = flags&0x01 != 0 || (channel-1)%16 == 9
+
+	if g.version >= 500 {
+		if g.version == 500 {
+			g.skip(44) // display settings and RSE instrument
+		} else {
+			g.skip(49)
+			g.intByteSizeString() // RSE effect
+			g.intByteSizeString() // RSE effect category
+		}
+	}
+	return t
+}
+
+func (g *gpReader) readMeasure(t *gpTrack) Bar {
+	var bar Bar
+	voices := 1
+	if g.version >= 500 {
+		voices = 2
+	}
+	for v := 0; v < voices; v++ {
+		beats := g.count("beat", gpMaxBeats)
+		for i := 0; i < beats && g.err == nil; i++ {
+			b := g.readBeat(t)
+			if v == 0 {
+				bar.Beats = append(bar.Beats, b)
+			}
+		}
+	}
+	if g.version >= 500 {
+		g.u8() // line break
+	}
+	return bar
+}
+
+func (g *gpReader) readBeat(t *gpTrack) Beat {
+	var b Beat
+	flags := g.u8()
+	rest := false
+	if flags&0x40 != 0 {
+		rest = g.u8() != 1 // 0 is an empty beat, 2 a rest
+	}
+	b.Duration = 4 / float64(int(1)<<max(g.i8()+2, 0))
+	if flags&0x01 != 0 {
+		b.Duration *= 1.5
+	}
+	if flags&0x20 != 0 {
+		if n := g.i32(); n > 1 {
+			played := 1
+			for played*2 <= n {
+				played *= 2
+			}
+			b.Duration = b.Duration * float64(played) / float64(n)
+		}
+	}
+	if flags&0x02 != 0 {
+		g.readChord()
+	}
+	if flags&0x04 != 0 {
+		g.intByteSizeString() // text
+	}
+	vibrato := false
+	if flags&0x08 != 0 {
+		vibrato = g.readBeatEffects()
+	}
+	if flags&0x10 != 0 {
+		g.readMixTable()
+	}
+
+	stringFlags := g.u8()
+	for n := 1; n <= len(t.Strings); n++ {
+		if stringFlags&(1<<(7-n)) == 0 {
+			continue
+		}
+		note, keep, palmMute := g.readNote(t, len(t.Strings)-n)
+		if palmMute {
+			b.PalmMute = true
+		}
+		if keep && !rest {
+			note.Vibrato = note.Vibrato || vibrato
+			b.Notes = append(b.Notes, note)
+		}
+	}
+	if g.version >= 500 {
+		if g.i16()&0x0800 != 0 {
+			g.u8() // break secondary beams
+		}
+	}
+	return b
+}
+
+// readChord skips a chord diagram.
+func (g *gpReader) readChord() {
+	if g.version >= 500 {
+		g.skip(17)
+		g.skip(22) // name
+		g.skip(4)
+		g.i32()
+		g.skip(7 * 4)
+		g.skip(32)
+		return
+	}
+	if !g.bool() {
+		// Old format: name, first fret and six frets.
+		g.intByteSizeString()
+		if g.i32() != 0 {
+			g.skip(6 * 4)
+		}
+		return
+	}
+	if g.version >= 400 {
+		g.skip(16)
+		g.skip(22) // name
+		g.skip(4)
+		g.i32()
+		g.skip(7 * 4)
+		g.skip(32)
+		return
+	}
+	g.skip(25)
+	g.skip(35) // name and extensions
+	g.i32()
+	g.skip(6 * 4)
+	g.skip(36)
+}
+
+// readBeatEffects reads the effects applied to a whole beat and reports
+// whether its notes have vibrato.
+func (g *gpReader) readBeatEffects() bool {
+	if g.version < 400 {
+		flags := g.u8()
+		if flags&0x20 != 0 {
+			g.u8()    // tapping, slapping or popping
+			g.skip(4) // tremolo bar value
+		}
+		if flags&0x40 != 0 {
+			g.skip(2) // stroke
+		}
+		return flags&0x03 != 0
+	}
+	flags1, flags2 := g.u8(), g.u8()
+	if flags1&0x20 != 0 {
+		g.u8()
+	}
+	if flags2&0x04 != 0 {
+		g.readBend() // tremolo bar
+	}
+	if flags1&0x40 != 0 {
+		g.skip(2)
+	}
+	if flags2&0x02 != 0 {
+		g.u8() // pick stroke
+	}
+	return flags1&0x03 != 0
+}
+
+// readMixTable skips a change of instrument, volume, effects or tempo.
+func (g *gpReader) readMixTable() {
+	g.i8() // instrument
+	if g.version >= 500 {
+		g.skip(16) // RSE instrument
+	}
+	values := make([]int, 6) // volume, balance, chorus, reverb, phaser, tremolo
+	for i := range values {
+		values[i] = g.i8()
+	}
+	if g.version >= 500 {
+		g.intByteSizeString() // tempo name
+	}
+	tempo := g.i32()
+	for _, v := range values {
+		if v >= 0 {
+			g.u8() // transition
+		}
+	}
+	if tempo >= 0 {
+		g.u8()
+		if g.version >= 510 {
+			g.bool() // hide tempo
+		}
+	}
+	if g.version >= 400 {
+		g.u8() // apply to all tracks
+	}
+	if g.version >= 500 {
+		g.i8() // wah
+	}
+	if g.version >= 510 {
+		g.intByteSizeString()
+		g.intByteSizeString()
+	}
+}
+
+// readNote reads the note on string str. keep is false for tied notes,
+// which continue the previous note and are shown as a gap.
+func (g *gpReader) readNote(t *gpTrack, str int) (note tab.Note, keep, palmMute bool) {
+	note.String = str
+	flags := g.u8()
+	kind := 1
+	if flags&0x20 != 0 {
+		kind = g.u8()
+	}
+	if flags&0x01 != 0 && g.version < 500 {
+		g.skip(2) // duration and tuplet
+	}
+	if flags&0x10 != 0 {
+		g.i8() // dynamic
+	}
+	if flags&0x20 != 0 {
+		note.Fret = max(g.i8(), 0)
+	}
+	if flags&0x80 != 0 {
+		g.skip(2) // fingering
+	}
+	if g.version >= 500 {
+		if flags&0x01 != 0 {
+			g.skip(8) // duration percent
+		}
+		g.u8()
+	}
+	var link *gpLink
+	if flags&0x08 != 0 {
+		link, palmMute = g.readNoteEffects(&note)
+	}
+	if kind == 2 {
+		return note, false, palmMute
+	}
+	note.Muted = kind == 3
+
+	if from, ok := t.pending[str]; ok {
+		note.Approach = techniqueBetween(from.legato, from.fret, note.Fret)
+		delete(t.pending, str)
+	}
+	if link != nil {
+		link.fret = note.Fret
+		t.pending[str] = *link
+	}
+	return note, true, palmMute
+}
+
+// readNoteEffects reads bends, vibrato and palm muting into note, and
+// returns the hammer-on, pull-off or slide that joins it to the next note.
+func (g *gpReader) readNoteEffects(note *tab.Note) (link *gpLink, palmMute bool) {
+	if g.version < 400 {
+		flags := g.u8()
+		if flags&0x01 != 0 {
+			g.readNoteBend(note)
+		}
+		if flags&0x10 != 0 {
+			g.skip(4) // grace note
+		}
+		switch {
+		case flags&0x02 != 0:
+			link = &gpLink{legato: true}
+		case flags&0x04 != 0:
+			link = &gpLink{}
+		}
+		return link, false
+	}
+
+	flags1, flags2 := g.u8(), g.u8()
+	if flags1&0x01 != 0 {
+		g.readNoteBend(note)
+	}
+	if flags1&0x10 != 0 {
+		if g.version >= 500 {
+			g.skip(5)
+		} else {
+			g.skip(4)
+		}
+	}
+	if flags2&0x04 != 0 {
+		g.u8() // tremolo picking
+	}
+	if flags2&0x08 != 0 {
+		slide := g.i8()
+		// Shift and legato slides join two notes; the rest slide in or out.
+		if (g.version >= 500 && slide&0x03 != 0) || (g.version < 500 && (slide == 1 || slide == 2)) {
+			link = &gpLink{}
+		}
+	}
+	if flags2&0x10 != 0 {
+		harmonic := g.i8()
+		if g.version >= 500 {
+			switch harmonic {
+			case 2:
+				g.skip(3) // artificial harmonic pitch
+			case 3:
+				g.skip(1) // tapped fret
+			}
+		}
+	}
+	if flags2&0x20 != 0 {
+		g.skip(2) // trill
+	}
+	if flags1&0x02 != 0 && link == nil {
+		link = &gpLink{legato: true}
+	}
+	note.Vibrato = flags2&0x40 != 0
+	return link, flags2&0x02 != 0
+}
+
+// readNoteBend reads a bend and marks the note with the highest fret it
+// reaches and, when it comes back down, the fret it is released to.
+func (g *gpReader) readNoteBend(note *tab.Note) {
+	points := g.readBend()
+	if len(points) == 0 {
+		return
+	}
+	peak := 0
+	for _, p := range points {
+		peak = max(peak, p)
+	}
+	note.Bend = true
+	note.BendTo = note.Fret + (peak+gpBendSemitone/2)/gpBendSemitone
+	if last := points[len(points)-1]; last < peak {
+		note.Release = note.Fret + (last+gpBendSemitone/2)/gpBendSemitone
+	}
+}
+
+// readBend reads a bend or tremolo bar curve and returns its point values.
+func (g *gpReader) readBend() []int {
+	g.i8()  // type
+	g.i32() // value
+	n := g.count("bend point", gpMaxPoints)
+	points := make([]int, n)
+	for i := range points {
+		g.i32() // position
+		points[i] = g.i32()
+		g.u8() // vibrato
+	}
+	return points
+}
@@ -0,0 +1,568 @@
+package score
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/paulgreig/guitar-training/internal/fretboard"
+	"github.com/paulgreig/guitar-training/internal/tab"
+	"github.com/paulgreig/guitar-training/internal/theory"
+)
+
+var update = flag.Bool("update", false, "rewrite the fixtures in testdata")
+
+// gpWriter writes the parts of a Guitar Pro file read by ReadGP, and
+// records where named fields are so tests can corrupt them.
+type gpWriter struct {
+	bytes.Buffer
+	version int
+	at      map[string]int
+}
+
+func (w *gpWriter) mark(name string) { w.at[name] = w.Len() }
+func (w *gpWriter) u8(v int)         { w.WriteByte(byte(v)) }
+func (w *gpWriter) skip(n int)       { w.Write(make([]byte, n)) }
+func (w *gpWriter) i16(v int)        { binary.Write(w, binary.LittleEndian, int16(v)) }
+func (w *gpWriter) i32(v int)        { binary.Write(w, binary.LittleEndian, int32(v)) }
+
+func (w *gpWriter) byteSizeString(s string, size int) {
+	w.u8(len(s))
+	w.WriteString(s)
+	w.skip(size - len(s))
+}
+
+func (w *gpWriter) intByteSizeString(s string) {
+	w.i32(len(s) + 1)
+	w.u8(len(s))
+	w.WriteString(s)
+}
+
+func (w *gpWriter) intSizeString(s string) {
+	w.i32(len(s))
+	w.WriteString(s)
+}
+
+// gpFixture builds the test song in the given version: a lead guitar in
+// standard tuning over two bars, and a drum track that ReadGP skips.
+func gpFixture(version int) *gpWriter {
+	w := &gpWriter{version: version, at: map[string]int{}}
+	w.byteSizeString(map[int]string{
+		300: "FICHIER GUITAR PRO v3.00",
+		400: "FICHIER GUITAR PRO v4.00",
+		406: "FICHIER GUITAR PRO v4.06",
+		500: "FICHIER GUITAR PRO v5.00",
+		510: "FICHIER GUITAR PRO v5.10",
+	}[version], 30)
+
+	w.mark("title")
+	w.intByteSizeString("Fixture Riff")
+	w.intByteSizeString("") // subtitle
+	w.intByteSizeString("The Testers")
+	w.intByteSizeString("") // album
+	w.intByteSizeString("") // words
+	if version >= 500 {
+		w.intByteSizeString("") // music
+	}
+	w.intByteSizeString("") // copyright
+	w.intByteSizeString("") // tab author
+	w.intByteSizeString("") // instructions
+	w.mark("notices")
+	w.i32(1)
+	w.intByteSizeString("a notice")
+	if version < 500 {
+		w.u8(0) // triplet feel
+	}
+	if version >= 400 {
+		w.i32(1)
+		for i := 0; i < 5; i++ {
+			w.i32(1)
+			w.intSizeString("")
+		}
+	}
+	if version >= 510 {
+		w.skip(19)
+	}
+	if version >= 500 {
+		w.skip(30)
+		for i := 0; i < 10; i++ {
+			w.intByteSizeString("%TITLE%")
+		}
+		w.intByteSizeString("Moderate")
+	}
+	w.i32(120)
+	if version >= 510 {
+		w.u8(0)
+	}
+	if version >= 400 {
+		w.skip(5)
+	} else {
+		w.skip(4)
+	}
+	w.skip(64 * 12)
+	if version >= 500 {
+		w.skip(42)
+	}
+	w.mark("measures")
+	w.i32(2)
+	w.mark("tracks")
+	w.i32(2)
+
+	for i := 0; i < 2; i++ {
+		if version >= 500 && i > 0 {
+			w.u8(0)
+		}
+		if i == 0 {
+			w.u8(0x03)
+			w.u8(4)
+			w.u8(4)
+		} else {
+			w.u8(0)
+		}
+		if version >= 500 {
+			if i == 0 {
+				w.skip(4)
+			}
+			w.u8(0)
+			w.u8(0)
+		}
+	}
+
+	w.mark("track 1")
+	w.writeTrack(0, "Lead", 0, 1)
+	w.writeTrack(1, "Drums", 0x01, 10)
+	if version == 500 {
+		w.skip(2)
+	} else if version > 500 {
+		w.skip(1)
+	}
+
+	// Bar 1.
+	w.mark("beats")
+	w.writeVoices([]func(){
+		func() {
+			// Quarter with a tempo change and text; low E, fret 5,
+			// hammered on to...
+			w.u8(0x04 | 0x10)
+			w.i8(0)
+			w.intByteSizeString("intro")
+			w.writeMixTable()
+			w.u8(1 << 1)
+			w.writeNote(1, 5, func() { w.writeNoteEffects(true, false, nil) })
+			w.beatEnd()
+		},
+		func() {
+			// ...fret 7, palm muted (GP4 and up).
+			w.u8(0)
+			w.i8(0)
+			w.u8(1 << 1)
+			w.writeNote(1, 7, func() { w.writeNoteEffects(false, true, nil) })
+			w.beatEnd()
+		},
+		func() {
+			// Half: open high E and the B string bent up a tone and back.
+			w.u8(0)
+			w.i8(-1)
+			w.u8(1<<6 | 1<<5)
+			w.writeNote(1, 0, nil)
+			w.writeNote(1, 1, func() { w.writeNoteEffects(false, false, []int{0, 100, 0}) })
+			w.beatEnd()
+		},
+	})
+
+	// Bar 2.
+	w.writeVoices([]func(){
+		w.restBeat,
+		func() {
+			// Dotted eighth with a chord diagram: a dead note on the D string.
+			w.u8(0x01 | 0x02)
+			w.i8(1)
+			w.writeChord()
+			w.u8(1 << 3)
+			w.writeNote(3, 0, nil)
+			w.beatEnd()
+		},
+		func() {
+			// Quarter with vibrato: G string, fret 2...
+			w.u8(0x08)
+			w.i8(0)
+			w.u8(0x01)
+			if version >= 400 {
+				w.u8(0)
+			}
+			w.u8(1 << 4)
+			w.writeNote(1, 2, nil)
+			w.beatEnd()
+		},
+		func() {
+			// ...held for another quarter.
+			w.u8(0)
+			w.i8(0)
+			w.u8(1 << 4)
+			w.writeNote(2, 2, nil)
+			w.beatEnd()
+		},
+	})
+	return w
+}
+
+func (w *gpWriter) i8(v int) { w.u8(int(uint8(int8(v)))) }
+
+func (w *gpWriter) writeTrack(i int, name string, flags, channel int) {
+	if w.version >= 500 && (i == 0 || w.version == 500) {
+		w.u8(0)
+	}
+	w.u8(flags)
+	w.byteSizeString(name, 40)
+	w.i32(6)
+	for _, p := range []string{"E4", "B3", "G3", "D3", "A2", "E2", "C0"} {
+		w.i32(int(theory.MustParsePitch(p)))
+	}
+	w.i32(1)
+	w.i32(channel)
+	w.i32(channel)
+	w.i32(24)
+	w.i32(0)
+	w.skip(4)
+	if w.version == 500 {
+		w.skip(44)
+	} else if w.version > 500 {
+		w.skip(49)
+		w.intByteSizeString("")
+		w.intByteSizeString("")
+	}
+}
+
+// writeVoices writes one bar of the lead track with the given beats, and a
+// rest for the drums. In GP5 the lead's second voice holds a rest too.
+func (w *gpWriter) writeVoices(lead []func()) {
+	for track, beats := range [][]func(){lead, {w.restBeat}} {
+		w.i32(len(beats))
+		for _, b := range beats {
+			b()
+		}
+		if w.version >= 500 {
+			if track == 0 {
+				// A second voice, which ReadGP ignores.
+				w.i32(1)
+				w.restBeat()
+			} else {
+				w.i32(0)
+			}
+			w.u8(0)
+		}
+	}
+}
+
+func (w *gpWriter) restBeat() {
+	w.u8(0x40)
+	w.u8(2)
+	w.i8(0)
+	w.u8(0)
+	w.beatEnd()
+}
+
+func (w *gpWriter) beatEnd() {
+	if w.version >= 500 {
+		w.i16(0)
+	}
+}
+
+func (w *gpWriter) writeMixTable() {
+	w.i8(-1)
+	if w.version >= 500 {
+		w.skip(16)
+	}
+	for i := 0; i < 6; i++ {
+		w.i8(-1)
+	}
+	if w.version >= 500 {
+		w.intByteSizeString("")
+	}
+	w.i32(96)
+	w.u8(0)
+	if w.version >= 510 {
+		w.u8(0)
+	}
+	if w.version >= 400 {
+		w.u8(0)
+	}
+	if w.version >= 500 {
+		w.i8(-1)
+	}
+	if w.version >= 510 {
+		w.intByteSizeString("")
+		w.intByteSizeString("")
+	}
+}
+
+func (w *gpWriter) writeChord() {
+	switch {
+	case w.version >= 500:
+		w.skip(17 + 22 + 4)
+		w.i32(0)
+		w.skip(7*4 + 32)
+	case w.version >= 400:
+		w.u8(1)
+		w.skip(16 + 22 + 4)
+		w.i32(0)
+		w.skip(7*4 + 32)
+	default:
+		w.u8(1)
+		w.skip(25 + 35)
+		w.i32(0)
+		w.skip(6*4 + 36)
+	}
+}
+
+// writeNote writes a note of the given kind (1 normal, 2 tied, 3 dead) and
+// its effects, if any.
+func (w *gpWriter) writeNote(kind, fret int, effects func()) {
+	flags := 0x20 | 0x10
+	if effects != nil {
+		flags |= 0x08
+	}
+	w.u8(flags)
+	w.u8(kind)
+	w.i8(8) // dynamic
+	w.i8(fret)
+	if w.version >= 500 {
+		w.u8(0)
+	}
+	if effects != nil {
+		effects()
+	}
+}
+
+func (w *gpWriter) writeNoteEffects(legato, palmMute bool, bend []int) {
+	if w.version < 400 {
+		flags := 0
+		if bend != nil {
+			flags |= 0x01
+		}
+		if legato {
+			flags |= 0x02
+		}
+		w.u8(flags)
+		if bend != nil {
+			w.writeBend(bend)
+		}
+		return
+	}
+	flags1, flags2 := 0, 0
+	if bend != nil {
+		flags1 |= 0x01
+	}
+	if legato {
+		flags1 |= 0x02
+	}
+	if palmMute {
+		flags2 |= 0x02
+	}
+	w.u8(flags1)
+	w.u8(flags2)
+	if bend != nil {
+		w.writeBend(bend)
+	}
+}
+
+func (w *gpWriter) writeBend(points []int) {
+	w.i8(1)
+	w.i32(100)
+	w.i32(len(points))
+	for i, p := range points {
+		w.i32(i * 6)
+		w.i32(p)
+		w.u8(0)
+	}
+}
+
+// gpFixtures are the checked-in Guitar Pro files and their versions.
+var gpFixtures = []struct {
+	file    string
+	version int
+}{
+	{"riff.gp3", 300},
+	{"riff-v400.gp4", 400},
+	{"riff.gp4", 406},
+	{"riff-v500.gp5", 500},
+	{"riff.gp5", 510},
+}
+
+// wantRiff is the lead track of the fixtures. GP3 has no palm muting.
+func wantRiff(palmMute bool) *Score {
+	return &Score{
+		Title:  "Fixture Riff",
+		Artist: "The Testers",
+		Tracks: []Track{{
+			Name:    "Lead",
+			Strings: fretboard.Standard.Strings,
+			Bars: []Bar{
+				{Beats: []Beat{
+					{Duration: 1, Notes: []tab.Note{{String: 0, Fret: 5}}},
+					{Duration: 1, Notes: []tab.Note{{String: 0, Fret: 7, Approach: tab.HammerOn}}, PalmMute: palmMute},
+					{Duration: 2, Notes: []tab.Note{
+						{String: 5, Fret: 0},
+						{String: 4, Fret: 1, Bend: true, BendTo: 3, Release: 1},
+					}},
+				}},
+				{Beats: []Beat{
+					{Duration: 1},
+					{Duration: 0.75, Notes: []tab.Note{{String: 2, Muted: true}}},
+					{Duration: 1, Notes: []tab.Note{{String: 3, Fret: 2, Vibrato: true}}},
+					{Duration: 1},
+				}},
+			},
+		}},
+	}
+}
+
+func TestReadGP(t *testing.T) {
+	for _, tt := range gpFixtures {
+		path := filepath.Join("testdata", tt.file)
+		fixture := gpFixture(tt.version).Bytes()
+		if *update {
+			if err := os.WriteFile(path, fixture, 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(data, fixture) {
+			t.Errorf("%s: out of date; run go test -update", tt.file)
+		}
+		s, err := Import(path)
+		if err != nil {
+			t.Errorf("%s: %v", tt.file, err)
+			continue
+		}
+		if want := wantRiff(tt.version >= 400); !reflect.DeepEqual(s, want) {
+			t.Errorf("%s: read\n%+v\nwant\n%+v", tt.file, s, want)
+		}
+	}
+}
+
+// TestReadGPTruncated checks every cut-short copy of each fixture fails to
+// read, rather than panicking or reading a partial song.
+func TestReadGPTruncated(t *testing.T) {
+	for _, tt := range gpFixtures {
+		data := gpFixture(tt.version).Bytes()
+		for n := 0; n < len(data); n++ {
+			if _, err := ReadGP(bytes.NewReader(data[:n])); err == nil {
+				t.Errorf("%s: read the first %d of %d bytes without error", tt.file, n, len(data))
+				break
+			}
+		}
+	}
+}
+
+// TestReadGPOutOfRange checks corrupt counts and sizes are rejected before
+// anything is read for them.
+func TestReadGPOutOfRange(t *testing.T) {
+	tests := []struct {
+		name  string
+		field string
+		value int
+		want  string
+	}{
+		{"huge title", "title", gpMaxString + 1, "string size 65537 out of range"},
+		{"negative title", "title", -1, "string size -1 out of range"},
+		{"too many notices", "notices", 1 << 30, "notice count 1073741824 out of range"},
+		{"too many measures", "measures", gpMaxMeasures + 1, "measure count 10001 out of range"},
+		{"negative measures", "measures", -2, "measure count -2 out of range"},
+		{"too many tracks", "tracks", gpMaxTracks + 1, "track count 129 out of range"},
+		{"too many beats", "beats", gpMaxBeats + 1, "beat count 1025 out of range"},
+	}
+	for _, v := range []int{300, 510} {
+		for _, tt := range tests {
+			w := gpFixture(v)
+			data := w.Bytes()
+			binary.LittleEndian.PutUint32(data[w.at[tt.field]:], uint32(int32(tt.value)))
+			_, err := ReadGP(bytes.NewReader(data))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("%s (v%d): error %v, want %q", tt.name, v, err, tt.want)
+			}
+		}
+	}
+
+	w := gpFixture(406)
+	data := w.Bytes()
+	// The string count follows the flags and 41-byte name.
+	binary.LittleEndian.PutUint32(data[w.at["track 1"]+1+41:], 9)
+	if _, err := ReadGP(bytes.NewReader(data)); err == nil || !strings.Contains(err.Error(), "track 1: 9 strings") {
+		t.Errorf("nine strings: error %v", err)
+	}
+}
+
+func TestReadGPHeader(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"empty", nil, "could not read Guitar Pro header"},
+		{"GP6", append([]byte{24}, []byte("FICHIER GUITAR PRO v6.00      ")...), `not a Guitar Pro 3-5 file (version "FICHIER GUITAR PRO v6.00")`},
+		{"not Guitar Pro", append([]byte{5}, []byte("hello                         ")...), `not a Guitar Pro 3-5 file (version "hello")`},
+	}
+	for _, tt := range tests {
+		_, err := ReadGP(bytes.NewReader(tt.data))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error %v, want %q", tt.name, err, tt.want)
+		}
+	}
+
+	// A song with only the drum track has nothing to import.
+	w := gpFixture(406)
+	data := w.Bytes()
+	binary.LittleEndian.PutUint32(data[w.at["track 1"]+1+41+4+7*4+4:], 10) // MIDI channel
+	if _, err := ReadGP(bytes.NewReader(data)); err == nil || err.Error() != "no guitar tracks found" {
+		t.Errorf("drums only: error %v", err)
+	}
+}
+
+func TestGPReaderLimits(t *testing.T) {
+	le := func(v int32) []byte { return binary.LittleEndian.AppendUint32(nil, uint32(v)) }
+	tests := []struct {
+		name    string
+		data    []byte
+		read    func(g *gpReader) interface{}
+		want    interface{}
+		wantErr string
+	}{
+		{"count", le(3), func(g *gpReader) interface{} { return g.count("beat", 3) }, 3, ""},
+		{"count over limit", le(4), func(g *gpReader) interface{} { return g.count("beat", 3) }, 0, "beat count 4 out of range"},
+		{"negative count", le(-1), func(g *gpReader) interface{} { return g.count("beat", 3) }, 0, "beat count -1 out of range"},
+		{"short count", []byte{1, 0}, func(g *gpReader) interface{} { return g.count("beat", 3) }, 0, "unexpected EOF"},
+		{"string", append(le(4), 3, 'a', 'b', 'c'), func(g *gpReader) interface{} { return g.intByteSizeString() }, "abc", ""},
+		{"padded string", append(le(5), 2, 'a', 'b', 0, 0), func(g *gpReader) interface{} { return g.intByteSizeString() }, "ab", ""},
+		{"length past size", append(le(2), 9, 'a'), func(g *gpReader) interface{} { return g.intByteSizeString() }, "a", ""},
+		{"empty string", le(0), func(g *gpReader) interface{} { return g.intByteSizeString() }, "", ""},
+		{"latin-1 string", append(le(3), 2, 0xe9, 't'), func(g *gpReader) interface{} { return g.intByteSizeString() }, "ét", ""},
+		{"oversized string", le(gpMaxString + 1), func(g *gpReader) interface{} { return g.intByteSizeString() }, "", "string size 65537 out of range"},
+		{"negative string", le(-5), func(g *gpReader) interface{} { return g.intByteSizeString() }, "", "string size -5 out of range"},
+		{"short string", append(le(10), 9, 'a'), func(g *gpReader) interface{} { return g.intByteSizeString() }, "", "unexpected EOF"},
+		{"int string", append(le(2), 'h', 'i'), func(g *gpReader) interface{} { return g.intSizeString() }, "hi", ""},
+		{"oversized int string", le(gpMaxString + 1), func(g *gpReader) interface{} { return g.intSizeString() }, "", "string size 65537 out of range"},
+	}
+	for _, tt := range tests {
+		g := &gpReader{r: bufio.NewReader(bytes.NewReader(tt.data))}
+		got := tt.read(g)
+		if tt.wantErr == "" {
+			if g.err != nil || got != tt.want {
+				t.Errorf("%s: got %q, %v; want %q", tt.name, got, g.err, tt.want)
+			}
+			continue
+		}
+		if g.err == nil || g.err.Error() != tt.wantErr {
+			t.Errorf("%s: error %v, want %q", tt.name, g.err, tt.wantErr)
+		}
+	}
+}
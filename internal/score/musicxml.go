@@ -0,0 +1,449 @@
+package score
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"strings"
+
+	"github.com/paulgreig/guitar-training/internal/fretboard"
+	"github.com/paulgreig/guitar-training/internal/tab"
+	"github.com/paulgreig/guitar-training/internal/theory"
+)
+
+// The subset of MusicXML (score-partwise) read by the importer.
+type (
+	xmlScore struct {
+		WorkTitle     string       `xml:"work>work-title"`
+		MovementTitle string       `xml:"movement-title"`
+		Creators      []xmlCreator `xml:"identification>creator"`
+		PartList      []xmlPartRef `xml:"part-list>score-part"`
+		Parts         []xmlPart    `xml:"part"`
+	}
+	xmlCreator struct {
+		Type string `xml:"type,attr"`
+		Name string `xml:",chardata"`
+	}
+	xmlPartRef struct {
+		ID   string `xml:"id,attr"`
+		Name string `xml:"part-name"`
+	}
+	xmlPart struct {
+		ID       string       `xml:"id,attr"`
+		Measures []xmlMeasure `xml:"measure"`
+	}
+	// xmlMeasure keeps the notes, attributes and directions of a measure in
+	// document order.
+	xmlMeasure struct {
+		Items []interface{}
+	}
+	xmlAttributes struct {
+		Divisions    int               `xml:"divisions"`
+		StaffDetails []xmlStaffDetails `xml:"staff-details"`
+	}
+	xmlStaffDetails struct {
+		Number  int              `xml:"number,attr"`
+		Lines   int              `xml:"staff-lines"`
+		Tunings []xmlStaffTuning `xml:"staff-tuning"`
+	}
+	xmlStaffTuning struct {
+		Line   int     `xml:"line,attr"` // 1 is the lowest string
+		Step   string  `xml:"tuning-step"`
+		Alter  float64 `xml:"tuning-alter"`
+		Octave int     `xml:"tuning-octave"`
+	}
+	xmlDirection struct {
+		Words  []string  `xml:"direction-type>words"`
+		Dashes []xmlLine `xml:"direction-type>dashes"`
+	}
+	xmlNote struct {
+		Grace     *struct{}      `xml:"grace"`
+		Chord     *struct{}      `xml:"chord"`
+		Rest      *struct{}      `xml:"rest"`
+		Pitch     *xmlPitch      `xml:"pitch"`
+		Duration  int            `xml:"duration"`
+		Voice     string         `xml:"voice"`
+		Staff     int            `xml:"staff"`
+		Ties      []xmlLine      `xml:"tie"`
+		Notehead  string         `xml:"notehead"`
+		Mute      string         `xml:"play>mute"`
+		Notations []xmlNotations `xml:"notations"`
+	}
+	xmlPitch struct {
+		Step   string  `xml:"step"`
+		Alter  float64 `xml:"alter"`
+		Octave int     `xml:"octave"`
+	}
+	xmlNotations struct {
+		Technical  []xmlTechnical `xml:"technical"`
+		Tied       []xmlLine      `xml:"tied"`
+		Slides     []xmlLine      `xml:"slide"`
+		Glissandos []xmlLine      `xml:"glissando"`
+		WavyLines  []xmlLine      `xml:"ornaments>wavy-line"`
+	}
+	xmlTechnical struct {
+		String   int       `xml:"string"` // 1 is the highest string
+		Fret     *int      `xml:"fret"`
+		HammerOn []xmlLine `xml:"hammer-on"`
+		PullOff  []xmlLine `xml:"pull-off"`
+		Bends    []xmlBend `xml:"bend"`
+	}
+	xmlBend struct {
+		Alter   float64   `xml:"bend-alter"`
+		Release *struct{} `xml:"release"`
+	}
+	xmlLine struct {
+		Type string `xml:"type,attr"`
+	}
+)
+
+func (m *xmlMeasure) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			var item interface{}
+			switch t.Name.Local {
+			case "note":
+				item = &xmlNote{}
+			case "attributes":
+				item = &xmlAttributes{}
+			case "direction":
+				item = &xmlDirection{}
+			default:
+				if err := d.Skip(); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := d.DecodeElement(item, &t); err != nil {
+				return err
+			}
+			m.Items = append(m.Items, item)
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// ReadMXL reads a compressed MusicXML file: a zip archive whose
+// META-INF/container.xml names the score inside it.
+func ReadMXL(name string) (*Score, error) {
+	zr, err := zip.OpenReader(name)
+	if err != nil {
+		return nil, fmt.Errorf("could not open compressed MusicXML: %w", err)
+	}
+	defer zr.Close()
+
+	var container struct {
+		Rootfiles []struct {
+			Path string `xml:"full-path,attr"`
+		} `xml:"rootfiles>rootfile"`
+	}
+	rootPath := ""
+	if f, err := zr.Open("META-INF/container.xml"); err == nil {
+		err = xml.NewDecoder(f).Decode(&container)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("could not parse META-INF/container.xml: %w", err)
+		}
+		if len(container.Rootfiles) > 0 {
+			rootPath = container.Rootfiles[0].Path
+		}
+	}
+	if rootPath == "" {
+		// No container: use the first score at the top of the archive.
+		for _, f := range zr.File {
+			if ext := path.Ext(f.Name); !strings.Contains(f.Name, "/") && (ext == ".xml" || ext == ".musicxml") {
+				rootPath = f.Name
+				break
+			}
+		}
+	}
+	if rootPath == "" {
+		return nil, fmt.Errorf("no score found in compressed MusicXML")
+	}
+	f, err := zr.Open(rootPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not open %s in compressed MusicXML: %w", rootPath, err)
+	}
+	defer f.Close()
+	return ReadMusicXML(f)
+}
+
+// ReadMusicXML reads an uncompressed score-partwise MusicXML document. Each
+// part with notes becomes a track. Notes are placed on the string and fret
+// given in their technical notation, or else on the lowest fret that plays
+// them in the part's tuning (from its TAB staff, or standard tuning).
+// Only the first voice of the TAB staff (or the first staff) is read.
+func ReadMusicXML(r io.Reader) (*Score, error) {
+	var doc xmlScore
+	d := xml.NewDecoder(r)
+	d.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
+		// Declared encodings other than UTF-8 are nearly always plain ASCII in practice.
+		return input, nil
+	}
+	if err := d.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("could not parse MusicXML: %w", err)
+	}
+	if len(doc.Parts) == 0 {
+		return nil, fmt.Errorf("could not parse MusicXML: no parts (only score-partwise files are supported)")
+	}
+
+	s := &Score{Title: doc.WorkTitle}
+	if s.Title == "" {
+		s.Title = doc.MovementTitle
+	}
+	for _, c := range doc.Creators {
+		if c.Type == "composer" || (c.Type == "artist" && s.Artist == "") {
+			s.Artist = strings.TrimSpace(c.Name)
+		}
+	}
+	names := map[string]string{}
+	for _, p := range doc.PartList {
+		names[p.ID] = strings.TrimSpace(p.Name)
+	}
+	for _, p := range doc.Parts {
+		t, err := readXMLPart(p)
+		if err != nil {
+			return nil, fmt.Errorf("part %s: %w", p.ID, err)
+		}
+		if t.Notes() == 0 {
+			continue
+		}
+		t.Name = names[p.ID]
+		if t.Name == "" {
+			t.Name = p.ID
+		}
+		s.Tracks = append(s.Tracks, t)
+	}
+	if len(s.Tracks) == 0 {
+		return nil, fmt.Errorf("no pitched parts found")
+	}
+	return s, nil
+}
+
+// xmlPartReader holds the state carried from note to note within a part.
+type xmlPartReader struct {
+	track     Track
+	divisions int
+	tabStaff  int    // staff number of the TAB staff; 0 when not numbered
+	voice     string // the voice being read
+	palmMute  bool   // in a P.M. span
+	pmOnce    bool   // P.M. marked for the next beat only
+	last      map[int]int
+	bar       *Bar
+}
+
+func readXMLPart(p xmlPart) (Track, error) {
+	r := &xmlPartReader{divisions: 1, last: map[int]int{}}
+	r.track.Strings = fretboard.Standard.Strings
+	for i, m := range p.Measures {
+		r.track.Bars = append(r.track.Bars, Bar{})
+		r.bar = &r.track.Bars[len(r.track.Bars)-1]
+		for _, item := range m.Items {
+			var err error
+			switch it := item.(type) {
+			case *xmlAttributes:
+				err = r.attributes(it)
+			case *xmlDirection:
+				r.direction(it)
+			case *xmlNote:
+				err = r.note(it)
+			}
+			if err != nil {
+				return Track{}, fmt.Errorf("measure %d: %w", i+1, err)
+			}
+		}
+	}
+	return r.track, nil
+}
+
+func (r *xmlPartReader) attributes(a *xmlAttributes) error {
+	if a.Divisions > 0 {
+		r.divisions = a.Divisions
+	}
+	for _, sd := range a.StaffDetails {
+		if len(sd.Tunings) == 0 {
+			continue
+		}
+		lines := sd.Lines
+		if lines == 0 {
+			lines = len(sd.Tunings)
+		}
+		strs := make([]theory.Pitch, lines)
+		copy(strs, fretboard.Standard.Strings)
+		for _, st := range sd.Tunings {
+			if st.Line < 1 || st.Line > lines {
+				return fmt.Errorf("staff-tuning line %d out of range", st.Line)
+			}
+			n, err := theory.ParseNote(st.Step)
+			if err != nil {
+				return fmt.Errorf("staff-tuning: %w", err)
+			}
+			strs[st.Line-1] = theory.PitchOf(n, st.Octave).Transpose(int(math.Round(st.Alter)))
+		}
+		r.track.Strings, r.tabStaff = strs, sd.Number
+	}
+	return nil
+}
+
+func (r *xmlPartReader) direction(d *xmlDirection) {
+	for _, w := range d.Words {
+		if w := strings.ToUpper(strings.ReplaceAll(w, " ", "")); w == "P.M." || w == "PM" || strings.HasPrefix(w, "P.M.") {
+			r.pmOnce = true
+		}
+	}
+	for _, dash := range d.Dashes {
+		switch dash.Type {
+		case "start":
+			if r.pmOnce {
+				r.palmMute = true
+			}
+		case "stop":
+			r.palmMute = false
+		}
+	}
+}
+
+func (r *xmlPartReader) note(n *xmlNote) error {
+	if n.Grace != nil {
+		return nil
+	}
+	if n.Staff == 0 {
+		n.Staff = 1
+	}
+	if want := max(r.tabStaff, 1); n.Staff != want {
+		return nil
+	}
+	if r.voice == "" {
+		r.voice = n.Voice
+	}
+	if n.Voice != r.voice {
+		return nil
+	}
+
+	if n.Chord == nil || len(r.bar.Beats) == 0 {
+		b := Beat{Duration: float64(n.Duration) / float64(r.divisions), PalmMute: r.palmMute || r.pmOnce}
+		r.pmOnce = false
+		r.bar.Beats = append(r.bar.Beats, b)
+	}
+	beat := &r.bar.Beats[len(r.bar.Beats)-1]
+	if n.Rest != nil || tieStop(n) {
+		// A held note is shown as a gap, like a rest.
+		return nil
+	}
+
+	var note tab.Note
+	var tech xmlTechnical
+	for _, nt := range n.Notations {
+		for _, t := range nt.Technical {
+			if t.String > 0 {
+				tech.String = t.String
+			}
+			if t.Fret != nil {
+				tech.Fret = t.Fret
+			}
+			tech.HammerOn = append(tech.HammerOn, t.HammerOn...)
+			tech.PullOff = append(tech.PullOff, t.PullOff...)
+			tech.Bends = append(tech.Bends, t.Bends...)
+		}
+	}
+	strs := len(r.track.Strings)
+	switch {
+	case tech.Fret != nil && tech.String >= 1 && tech.String <= strs:
+		note.String, note.Fret = strs-tech.String, *tech.Fret
+	case n.Pitch != nil:
+		str, fret, ok := r.place(n.Pitch, beat)
+		if !ok {
+			return fmt.Errorf("%s%d is out of range of the tuning", n.Pitch.Step, n.Pitch.Octave)
+		}
+		note.String, note.Fret = str, fret
+	default:
+		// Unpitched (percussion) note.
+		return nil
+	}
+	note.Muted = n.Notehead == "x"
+	if n.Mute == "palm" {
+		beat.PalmMute = true
+	}
+
+	for _, h := range tech.HammerOn {
+		if h.Type == "stop" {
+			note.Approach = tab.HammerOn
+		}
+	}
+	for _, p := range tech.PullOff {
+		if p.Type == "stop" {
+			note.Approach = tab.PullOff
+		}
+	}
+	for _, nt := range n.Notations {
+		for _, l := range append(nt.Slides, nt.Glissandos...) {
+			if from, ok := r.last[note.String]; ok && l.Type == "stop" {
+				note.Approach = techniqueBetween(false, from, note.Fret)
+			}
+		}
+		for _, w := range nt.WavyLines {
+			if w.Type == "start" {
+				note.Vibrato = true
+			}
+		}
+	}
+	for _, b := range tech.Bends {
+		note.Bend = true
+		note.BendTo = note.Fret + int(math.Round(b.Alter))
+		if b.Release != nil {
+			note.Release = note.Fret
+		}
+	}
+
+	r.last[note.String] = note.Fret
+	beat.Notes = append(beat.Notes, note)
+	return nil
+}
+
+// tieStop reports whether a note only continues a tied note.
+func tieStop(n *xmlNote) bool {
+	start, stop := false, false
+	ties := append([]xmlLine(nil), n.Ties...)
+	for _, nt := range n.Notations {
+		ties = append(ties, nt.Tied...)
+	}
+	for _, t := range ties {
+		switch t.Type {
+		case "start":
+			start = true
+		case "stop":
+			stop = true
+		}
+	}
+	return stop && !start
+}
+
+// place finds the lowest fret that plays p on a string not already used in
+// the beat.
+func (r *xmlPartReader) place(p *xmlPitch, beat *Beat) (str, fret int, ok bool) {
+	n, err := theory.ParseNote(p.Step)
+	if err != nil {
+		return 0, 0, false
+	}
+	target := theory.PitchOf(n, p.Octave).Transpose(int(math.Round(p.Alter)))
+	used := map[int]bool{}
+	for _, other := range beat.Notes {
+		used[other.String] = true
+	}
+	fret = math.MaxInt
+	for s, open := range r.track.Strings {
+		if f := int(target - open); f >= 0 && f < fret && !used[s] {
+			str, fret, ok = s, f, true
+		}
+	}
+	return str, fret, ok
+}
@@ -0,0 +1,240 @@
+package score
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/paulgreig/guitar-training/internal/fretboard"
+	"github.com/paulgreig/guitar-training/internal/tab"
+)
+
+// wantXMLRiff is the guitar part of testdata/riff.musicxml, in drop D.
+func wantXMLRiff() *Score {
+	dropD, _ := fretboard.LookupTuning("drop-d")
+	return &Score{
+		Title:  "Fixture Riff",
+		Artist: "The Testers",
+		Tracks: []Track{{
+			Name:    "Guitar",
+			Strings: dropD.Strings,
+			Bars: []Bar{
+				{Beats: []Beat{
+					{Duration: 1, Notes: []tab.Note{{String: 0, Fret: 0}}, PalmMute: true},
+					{Duration: 1, Notes: []tab.Note{{String: 0, Fret: 2, Approach: tab.HammerOn}}, PalmMute: true},
+					{Duration: 2, Notes: []tab.Note{{String: 1, Fret: 0}, {String: 2, Fret: 0}}},
+				}},
+				{Beats: []Beat{
+					{Duration: 1},
+					{Duration: 0.5, Notes: []tab.Note{{String: 3, Fret: 2}}},
+					{Duration: 0.5, Notes: []tab.Note{{String: 3, Fret: 4, Approach: tab.SlideUp}}},
+					{Duration: 0.5, Notes: []tab.Note{{String: 2, Fret: 3, Muted: true}}},
+					{Duration: 1, Notes: []tab.Note{{String: 4, Fret: 5, Bend: true, BendTo: 7, Release: 5, Vibrato: true}}},
+					{Duration: 0.5},
+				}},
+			},
+		}},
+	}
+}
+
+// writeMXL zips files into an .mxl archive at path.
+func writeMXL(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	// mimetype comes first, as in files written by notation programs.
+	names := []string{"mimetype"}
+	for name := range files {
+		if name != "mimetype" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names[1:])
+	for _, name := range names {
+		body, ok := files[name]
+		if !ok {
+			continue
+		}
+		f, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write([]byte(body))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+const mxlContainer = `<?xml version="1.0" encoding="UTF-8"?>
+<container>
+  <rootfiles>
+    <rootfile full-path="score/riff.musicxml" media-type="application/vnd.recordare.musicxml+xml"/>
+  </rootfiles>
+</container>
+`
+
+func TestReadMusicXML(t *testing.T) {
+	doc, err := os.ReadFile(filepath.Join("testdata", "riff.musicxml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *update {
+		writeMXL(t, filepath.Join("testdata", "riff.mxl"), map[string]string{
+			"mimetype":               "application/vnd.recordare.musicxml",
+			"META-INF/container.xml": mxlContainer,
+			"score/riff.musicxml":    string(doc),
+		})
+	}
+	// Without a container the first score at the top of the archive is read.
+	bare := filepath.Join(t.TempDir(), "bare.mxl")
+	writeMXL(t, bare, map[string]string{"notes/readme.xml": "<readme/>", "riff.xml": string(doc)})
+
+	for _, path := range []string{filepath.Join("testdata", "riff.musicxml"), filepath.Join("testdata", "riff.mxl"), bare} {
+		s, err := Import(path)
+		if err != nil {
+			t.Errorf("%s: %v", path, err)
+			continue
+		}
+		if want := wantXMLRiff(); !reflect.DeepEqual(s, want) {
+			t.Errorf("%s: read\n%+v\nwant\n%+v", path, s, want)
+		}
+		if got := s.Tracks[0].Tuning().ID; got != "drop-d" {
+			t.Errorf("%s: tuning %q, want drop-d", path, got)
+		}
+	}
+}
+
+// TestReadMusicXMLTruncated checks every cut-short copy of the fixture fails
+// to read, rather than panicking or reading a partial score.
+func TestReadMusicXMLTruncated(t *testing.T) {
+	doc, err := os.ReadFile(filepath.Join("testdata", "riff.musicxml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	end := bytes.LastIndex(doc, []byte("</score-partwise>"))
+	for n := 0; n < end; n++ {
+		if _, err := ReadMusicXML(bytes.NewReader(doc[:n])); err == nil {
+			t.Errorf("read the first %d of %d bytes without error", n, len(doc))
+			break
+		}
+	}
+}
+
+func TestReadMusicXMLErrors(t *testing.T) {
+	part := func(attributes, notes string) string {
+		return `<score-partwise><part-list><score-part id="P1"/></part-list><part id="P1"><measure number="1">` +
+			`<attributes><divisions>1</divisions>` + attributes + `</attributes>` + notes + `</measure></part></score-partwise>`
+	}
+	note := func(step string, octave string) string {
+		return `<note><pitch><step>` + step + `</step><octave>` + octave + `</octave></pitch><duration>1</duration></note>`
+	}
+	tests := []struct {
+		name string
+		doc  string
+		want string
+	}{
+		{"empty", "", "could not parse MusicXML: EOF"},
+		{"not XML", "{}", "could not parse MusicXML"},
+		{"timewise", `<score-timewise><measure number="1"><part id="P1"/></measure></score-timewise>`, "no parts (only score-partwise files are supported)"},
+		{"below the tuning", part("", note("C", "1")), "part P1: measure 1: C1 is out of range of the tuning"},
+		{"bad tuning line", part(`<staff-details><staff-lines>6</staff-lines><staff-tuning line="7"><tuning-step>E</tuning-step><tuning-octave>4</tuning-octave></staff-tuning></staff-details>`, note("E", "2")),
+			"part P1: measure 1: staff-tuning line 7 out of range"},
+		{"bad tuning step", part(`<staff-details><staff-tuning line="1"><tuning-step>H</tuning-step><tuning-octave>2</tuning-octave></staff-tuning></staff-details>`, note("E", "2")),
+			"part P1: measure 1: staff-tuning"},
+		{"only percussion", part("", `<note><unpitched/><duration>1</duration></note>`), "no pitched parts found"},
+		{"only rests", part("", `<note><rest/><duration>4</duration></note>`), "no pitched parts found"},
+	}
+	for _, tt := range tests {
+		_, err := ReadMusicXML(strings.NewReader(tt.doc))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+// TestReadMusicXMLPlacement checks notes without a string and fret go on
+// the lowest fret free in their beat, in standard tuning by default.
+func TestReadMusicXMLPlacement(t *testing.T) {
+	doc := `<score-partwise><part-list><score-part id="P1"><part-name>Lead</part-name></score-part></part-list>
+<part id="P1"><measure number="1"><attributes><divisions>1</divisions></attributes>
+<note><pitch><step>E</step><octave>2</octave></pitch><duration>1</duration></note>
+<note><pitch><step>B</step><alter>-1</alter><octave>2</octave></pitch><duration>1</duration></note>
+<note><chord/><pitch><step>B</step><alter>-1</alter><octave>2</octave></pitch><duration>1</duration></note>
+<direction><direction-type><words>P.M.</words></direction-type></direction>
+<note><pitch><step>A</step><octave>2</octave></pitch><duration>2</duration></note>
+<note><pitch><step>A</step><octave>2</octave></pitch><duration>2</duration></note>
+</measure></part></score-partwise>`
+	s, err := ReadMusicXML(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Beat{
+		{Duration: 1, Notes: []tab.Note{{String: 0, Fret: 0}}},
+		{Duration: 1, Notes: []tab.Note{{String: 1, Fret: 1}, {String: 0, Fret: 6}}},
+		{Duration: 2, Notes: []tab.Note{{String: 1, Fret: 0}}, PalmMute: true},
+		{Duration: 2, Notes: []tab.Note{{String: 1, Fret: 0}}},
+	}
+	if got := s.Tracks[0].Bars[0].Beats; !reflect.DeepEqual(got, want) {
+		t.Errorf("beats = %+v, want %+v", got, want)
+	}
+	if s.Title != "" || s.Tracks[0].Name != "Lead" || s.Tracks[0].Tuning().ID != "standard" {
+		t.Errorf("read %q track %q in %s", s.Title, s.Tracks[0].Name, s.Tracks[0].Tuning().ID)
+	}
+}
+
+func TestReadMXLErrors(t *testing.T) {
+	dir := t.TempDir()
+	notZip := filepath.Join(dir, "not-zip.mxl")
+	os.WriteFile(notZip, []byte("<score-partwise/>"), 0o644)
+	empty := filepath.Join(dir, "empty.mxl")
+	writeMXL(t, empty, map[string]string{"notes/readme.txt": "nothing here"})
+	missing := filepath.Join(dir, "missing.mxl")
+	writeMXL(t, missing, map[string]string{"META-INF/container.xml": mxlContainer})
+	badContainer := filepath.Join(dir, "bad-container.mxl")
+	writeMXL(t, badContainer, map[string]string{"META-INF/container.xml": "<container><rootfiles>"})
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{notZip, "could not open compressed MusicXML"},
+		{empty, "no score found in compressed MusicXML"},
+		{missing, "could not open score/riff.musicxml in compressed MusicXML"},
+		{badContainer, "could not parse META-INF/container.xml"},
+		{filepath.Join(dir, "absent.mxl"), "could not open compressed MusicXML"},
+	}
+	for _, tt := range tests {
+		_, err := ReadMXL(tt.path)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error %v, want %q", filepath.Base(tt.path), err, tt.want)
+		}
+	}
+}
+
+func TestImportErrors(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "song.txt"), []byte("E|---0---|"), 0o644)
+	tests := []struct {
+		file string
+		want string
+	}{
+		{"song.txt", `unsupported score format ".txt"`},
+		{"absent.gp5", "could not open score"},
+		{"absent.musicxml", "could not open score"},
+	}
+	for _, tt := range tests {
+		_, err := Import(filepath.Join(dir, tt.file))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error %v, want %q", tt.file, err, tt.want)
+		}
+	}
+}
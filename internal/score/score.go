@@ -0,0 +1,155 @@
+// Package score imports notated music from MusicXML and Guitar Pro files as
+// guitar tracks that can be shown as tab or practised as exercises.
+package score
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/paulgreig/guitar-training/internal/exercise"
+	"github.com/paulgreig/guitar-training/internal/fretboard"
+	"github.com/paulgreig/guitar-training/internal/tab"
+	"github.com/paulgreig/guitar-training/internal/theory"
+)
+
+// Score is an imported piece with one or more tracks.
+type Score struct {
+	Title  string
+	Artist string
+	Tracks []Track
+}
+
+// Track is one fretted instrument part.
+type Track struct {
+	Name    string
+	Strings []theory.Pitch // open pitch of each string, lowest first
+	Bars    []Bar
+}
+
+// Bar is one measure of a track.
+type Bar struct {
+	Beats []Beat
+}
+
+// Beat is the notes struck together, or a rest when there are none.
+type Beat struct {
+	Duration float64 // in quarter notes
+	Notes    []tab.Note
+	PalmMute bool
+}
+
+// Import reads a score, choosing the format from the file extension:
+// .musicxml, .xml, .mxl, .gp3, .gp4 or .gp5.
+func Import(path string) (*Score, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == ".mxl" {
+		return ReadMXL(path)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open score: %w", err)
+	}
+	defer f.Close()
+	switch ext {
+	case ".musicxml", ".xml":
+		return ReadMusicXML(f)
+	case ".gp3", ".gp4", ".gp5":
+		return ReadGP(f)
+	default:
+		return nil, fmt.Errorf("unsupported score format %q (want .musicxml, .xml, .mxl, .gp3, .gp4 or .gp5)", ext)
+	}
+}
+
+// Tuning returns the track's strings as a tuning, named after a built-in
+// tuning when they match one.
+func (t Track) Tuning() fretboard.Tuning {
+	for _, known := range fretboard.Tunings() {
+		if samePitches(known.Strings, t.Strings) {
+			return known
+		}
+	}
+	return fretboard.Tuning{ID: "custom", Name: "Custom", Strings: t.Strings}
+}
+
+func samePitches(a, b []theory.Pitch) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Tab converts the track to tab. The gap before each beat grows with the
+// length of the beat before it, two dashes to the quarter note, so the
+// rhythm stays readable.
+func (t Track) Tab() *tab.Tab {
+	out := &tab.Tab{Strings: t.Tuning().StringLabels()}
+	for _, bar := range t.Bars {
+		var m tab.Measure
+		prev := 0.5
+		for _, b := range bar.Beats {
+			m.Beats = append(m.Beats, tab.Beat{Notes: b.Notes, PalmMute: b.PalmMute, Space: spaceFor(prev)})
+			prev = b.Duration
+		}
+		m.Tail = spaceFor(prev)
+		out.Measures = append(out.Measures, m)
+	}
+	return out
+}
+
+// spaceFor is the number of dashes standing for a duration in quarter notes.
+func spaceFor(quarters float64) int {
+	return max(1, int(math.Round(quarters*2)))
+}
+
+// Run returns the track's fretted notes in playing order, for judging
+// against what is played. Muted notes are left out.
+func (t Track) Run() []exercise.Note {
+	tuning := t.Tuning()
+	var out []exercise.Note
+	for _, bar := range t.Bars {
+		for _, b := range bar.Beats {
+			for _, n := range b.Notes {
+				if n.Muted || n.String < 0 || n.String >= len(t.Strings) {
+					continue
+				}
+				loc := fretboard.Location{String: n.String, Fret: n.Fret}
+				out = append(out, exercise.Note{Location: loc, Pitch: tuning.PitchAt(loc.String, loc.Fret)})
+			}
+		}
+	}
+	return out
+}
+
+// Notes counts the notes in the track.
+func (t Track) Notes() int {
+	n := 0
+	for _, bar := range t.Bars {
+		for _, b := range bar.Beats {
+			n += len(b.Notes)
+		}
+	}
+	return n
+}
+
+// techniqueBetween is the technique joining a note at fret from to one at
+// fret to: legato is a hammer-on going up and a pull-off going down.
+func techniqueBetween(legato bool, from, to int) tab.Technique {
+	switch {
+	case legato && to >= from:
+		return tab.HammerOn
+	case legato:
+		return tab.PullOff
+	case to >= from:
+		return tab.SlideUp
+	default:
+		return tab.SlideDown
+	}
+}
@@ -0,0 +1,131 @@
+package synth
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Voice is the timbre of a plucked string.
+type Voice struct {
+	// Decay is roughly how many seconds the fundamental takes to die away
+	// (fall by 60 dB).
+	Decay float64
+	// Brightness from 0 (dull, like a thumb or a wound string) to 1 (bright,
+	// like a pick on a plain string) filters the initial noise burst.
+	Brightness float64
+}
+
+// DefaultVoice is a mid-range string picked fairly brightly.
+var DefaultVoice = Voice{Decay: 4, Brightness: 0.8}
+
+// GuitarVoices returns one voice per string, low to high: low, wound
+// strings sustain longer and sound darker; high, plain strings are brighter
+// and die away sooner.
+func GuitarVoices(strings int) []Voice {
+	voices := make([]Voice, strings)
+	for i := range voices {
+		pos := 0.5
+		if strings > 1 {
+			pos = float64(i) / float64(strings-1) // 0 = lowest string
+		}
+		voices[i] = Voice{
+			Decay:      6 - 3*pos,
+			Brightness: 0.55 + 0.4*pos,
+		}
+	}
+	return voices
+}
+
+// Pluck synthesises DefaultVoice at freq Hz for the given number of seconds.
+func Pluck(freq, seconds float64, rate int) []float64 {
+	return DefaultVoice.Pluck(freq, seconds, rate)
+}
+
+// Pluck synthesises a plucked string at freq Hz for the given number of
+// seconds using the Karplus-Strong algorithm: a burst of noise circulates
+// through a delay line one period long and is low-pass filtered on every pass.
+// A first-order allpass filter supplies the fractional part of the delay so
+// high notes stay in tune. The noise is seeded from freq, so the same note
+// always sounds the same.
+func (v Voice) Pluck(freq, seconds float64, rate int) []float64 {
+	n := int(seconds * float64(rate))
+	if n <= 0 || freq <= 0 {
+		return nil
+	}
+	// Averaging a sample with the one written after it shortens the loop by
+	// half a sample, so the delay line has to be half a sample longer.
+	period := float64(rate)/freq + 0.5
+	length := int(period)
+	// Keep the allpass delay away from zero, where its coefficient nears 1
+	// and it rings.
+	if period-float64(length) < 0.1 {
+		length--
+	}
+	if length < 2 {
+		length = 2
+	}
+	frac := period - float64(length)
+	c := (1 - frac) / (1 + frac) // allpass coefficient
+
+	rng := rand.New(rand.NewSource(int64(freq * 1000)))
+	line := make([]float64, length)
+	brightness := math.Max(0.05, math.Min(1, v.Brightness))
+	prev := 0.0
+	for i := range line {
+		// One-pole low-pass on the excitation: duller voices start with less treble.
+		prev += brightness * (rng.Float64()*2 - 1 - prev)
+		line[i] = prev
+	}
+	removeDC(line)
+
+	gain := v.loopGain(freq)
+	out := make([]float64, n)
+	var apIn, apOut float64
+	pos := 0
+	for i := range out {
+		cur := line[pos]
+		next := line[(pos+1)%length]
+		avg := gain * 0.5 * (cur + next)
+		// y[n] = c*x[n] + x[n-1] - c*y[n-1]
+		ap := c*avg + apIn - c*apOut
+		apIn, apOut = avg, ap
+		line[pos] = ap
+		out[i] = cur
+		pos = (pos + 1) % length
+	}
+	fadeOut(out, rate/100)
+	return out
+}
+
+// loopGain is the per-period gain that makes the fundamental fall by 60 dB
+// over v.Decay seconds.
+func (v Voice) loopGain(freq float64) float64 {
+	decay := v.Decay
+	if decay <= 0 {
+		decay = DefaultVoice.Decay
+	}
+	return math.Min(1, math.Pow(10, -3/(freq*decay)))
+}
+
+// removeDC subtracts the mean so a dull, low-passed burst doesn't leave an offset ringing.
+func removeDC(samples []float64) {
+	mean := 0.0
+	for _, v := range samples {
+		mean += v
+	}
+	mean /= float64(len(samples))
+	for i := range samples {
+		samples[i] -= mean
+	}
+}
+
+// fadeOut ramps the last n samples to zero so notes cut off without a click.
+func fadeOut(samples []float64, n int) {
+	if n > len(samples) {
+		n = len(samples)
+	}
+	start := len(samples) - n
+	for i := start; i < len(samples); i++ {
+		samples[i] *= math.Max(0, float64(len(samples)-1-i)/float64(n))
+	}
+}
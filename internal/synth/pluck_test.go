@@ -0,0 +1,88 @@
+package synth
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/paulgreig/guitar-training/internal/pitch"
+	"github.com/paulgreig/guitar-training/internal/theory"
+)
+
+// TestPluckInTune checks the fractional delay keeps plucked notes within a
+// few cents across the fretboard, high notes included.
+func TestPluckInTune(t *testing.T) {
+	const rate = 44100
+	d := pitch.NewDetector(rate, theory.StandardA4)
+	for _, name := range []string{"E2", "A2", "D3", "G3", "B3", "E4", "C#5", "E6"} {
+		p := theory.MustParsePitch(name)
+		freq := p.Frequency(theory.StandardA4)
+		out := Pluck(freq, 0.5, rate)
+		est, ok := d.Detect(out[rate/20 : rate/20+pitch.FrameSize(rate)])
+		if !ok {
+			t.Errorf("%s: no pitch detected", name)
+			continue
+		}
+		if cents := 1200 * math.Log2(est.Freq/freq); math.Abs(cents) > 5 {
+			t.Errorf("%s: %.1f Hz, %.1f cents off %.1f Hz", name, est.Freq, cents, freq)
+		}
+	}
+}
+
+func TestPluck(t *testing.T) {
+	const rate = 8000
+	out := Pluck(220, 1, rate)
+	if len(out) != rate {
+		t.Fatalf("got %d samples, want %d", len(out), rate)
+	}
+	if !slices.Equal(out, Pluck(220, 1, rate)) {
+		t.Errorf("the same note sounds different twice")
+	}
+	if out[len(out)-1] != 0 {
+		t.Errorf("last sample %v, want a fade to 0", out[len(out)-1])
+	}
+	for _, tt := range []struct{ freq, seconds float64 }{{0, 1}, {-110, 1}, {220, 0}} {
+		if got := Pluck(tt.freq, tt.seconds, rate); got != nil {
+			t.Errorf("Pluck(%v, %v) = %d samples, want none", tt.freq, tt.seconds, len(got))
+		}
+	}
+}
+
+// TestDecay checks a longer decay sustains longer.
+func TestDecay(t *testing.T) {
+	const rate = 8000
+	energy := func(v Voice) float64 {
+		out := v.Pluck(110, 2, rate)
+		sum := 0.0
+		for _, s := range out[rate : rate+rate/2] {
+			sum += s * s
+		}
+		return sum
+	}
+	short, long := energy(Voice{Decay: 1, Brightness: 0.8}), energy(Voice{Decay: 8, Brightness: 0.8})
+	if short >= long {
+		t.Errorf("energy after 1s: decay 1 %.3g, decay 8 %.3g; want the longer decay louder", short, long)
+	}
+}
+
+func TestGuitarVoices(t *testing.T) {
+	tests := []struct {
+		strings int
+		want    []Voice
+	}{
+		{1, []Voice{{Decay: 4.5, Brightness: 0.75}}},
+		{3, []Voice{{Decay: 6, Brightness: 0.55}, {Decay: 4.5, Brightness: 0.75}, {Decay: 3, Brightness: 0.95}}},
+	}
+	for _, tt := range tests {
+		got := GuitarVoices(tt.strings)
+		if len(got) != len(tt.want) {
+			t.Errorf("%d strings: %d voices", tt.strings, len(got))
+			continue
+		}
+		for i := range got {
+			if math.Abs(got[i].Decay-tt.want[i].Decay) > 1e-9 || math.Abs(got[i].Brightness-tt.want[i].Brightness) > 1e-9 {
+				t.Errorf("%d strings: voice %d = %+v, want %+v", tt.strings, i, got[i], tt.want[i])
+			}
+		}
+	}
+}
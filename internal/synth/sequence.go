@@ -0,0 +1,68 @@
+package synth
+
+import (
+	"time"
+
+	"github.com/paulgreig/guitar-training/internal/audio"
+	"github.com/paulgreig/guitar-training/internal/theory"
+)
+
+// release is how long a note keeps ringing past its written length.
+const release = 0.15
+
+// Note is one note of a sequence.
+type Note struct {
+	Pitch  theory.Pitch
+	String int     // string index, low to high, choosing the voice; -1 for DefaultVoice
+	Beat   float64 // start, in beats from the beginning
+	Beats  float64 // written length, in beats
+}
+
+// Renderer turns note sequences into audio.
+type Renderer struct {
+	Rate   int
+	A4     float64 // reference pitch; theory.StandardA4 when zero
+	Voices []Voice // per string, low to high
+}
+
+// NewRenderer returns a renderer at rate with guitar voices for the given number of strings.
+func NewRenderer(rate, strings int) Renderer {
+	return Renderer{Rate: rate, A4: theory.StandardA4, Voices: GuitarVoices(strings)}
+}
+
+// voice returns the timbre for a string.
+func (r Renderer) voice(str int) Voice {
+	if str < 0 || str >= len(r.Voices) {
+		return DefaultVoice
+	}
+	return r.Voices[str]
+}
+
+// Render synthesises notes at bpm beats per minute and normalises the result.
+func (r Renderer) Render(notes []Note, bpm int) []float64 {
+	a4 := r.A4
+	if a4 == 0 {
+		a4 = theory.StandardA4
+	}
+	beat := 60 / float64(bpm)
+	var out []float64
+	for _, n := range notes {
+		seconds := n.Beats*beat + release
+		s := r.voice(n.String).Pluck(n.Pitch.Frequency(a4), seconds, r.Rate)
+		out = audio.Mix(out, s, int(n.Beat*beat*float64(r.Rate)))
+	}
+	audio.Normalize(out, 0.8)
+	return out
+}
+
+// Duration returns how long the rendered sequence lasts at bpm.
+func Duration(notes []Note, bpm int) time.Duration {
+	end := 0.0
+	for _, n := range notes {
+		if e := n.Beat + n.Beats; e > end {
+			end = e
+		}
+	}
+	seconds := end*60/float64(bpm) + release
+	return time.Duration(seconds * float64(time.Second))
+}
@@ -0,0 +1,60 @@
+package synth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/paulgreig/guitar-training/internal/theory"
+)
+
+func TestDuration(t *testing.T) {
+	a := theory.MustParsePitch("A2")
+	tests := []struct {
+		name  string
+		notes []Note
+		bpm   int
+		want  time.Duration
+	}{
+		{"empty", nil, 120, 150 * time.Millisecond},
+		{"one beat", []Note{{Pitch: a, Beats: 1}}, 60, 1150 * time.Millisecond},
+		{"longest wins", []Note{{Pitch: a, Beat: 0, Beats: 4}, {Pitch: a, Beat: 2, Beats: 1}}, 120, 2150 * time.Millisecond},
+		{"last wins", []Note{{Pitch: a, Beat: 0, Beats: 1}, {Pitch: a, Beat: 3, Beats: 0.5}}, 120, 1900 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		got := Duration(tt.notes, tt.bpm)
+		if d := got - tt.want; d < -time.Millisecond || d > time.Millisecond {
+			t.Errorf("%s: %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	const rate = 8000
+	r := NewRenderer(rate, 6)
+	a := theory.MustParsePitch("A2")
+	notes := []Note{
+		{Pitch: a, String: 1, Beat: 0, Beats: 1},
+		{Pitch: a.Transpose(7), String: -1, Beat: 2, Beats: 1},
+	}
+	out := r.Render(notes, 120)
+	if want := int(Duration(notes, 120).Seconds() * rate); len(out) != want {
+		t.Errorf("got %d samples, want %d", len(out), want)
+	}
+	peak := 0.0
+	for _, s := range out {
+		peak = max(peak, s, -s)
+	}
+	if peak < 0.79 || peak > 0.81 {
+		t.Errorf("peak %.3f, want 0.8", peak)
+	}
+	// The first note rings out before the second starts on beat 2.
+	gap := out[int((0.5+release)*rate):rate]
+	for i, s := range gap {
+		if s != 0 {
+			t.Fatalf("sample %d in the rest is %v, want silence", i, s)
+		}
+	}
+	if r.voice(1) != r.Voices[1] || r.voice(-1) != DefaultVoice || r.voice(6) != DefaultVoice {
+		t.Errorf("voices picked wrongly for strings 1, -1 and 6")
+	}
+}
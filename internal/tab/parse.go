@@ -0,0 +1,243 @@
+package tab
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// stringLine matches one string of a tab: an optional note label, then the
+// first bar line and the string's content.
+var stringLine = regexp.MustCompile(`^\s*([A-Ga-g][#b]?)?\s*\|(.*)$`)
+
+// palmMuteLine matches an annotation line such as "PM----|   PM--".
+var palmMuteLine = regexp.MustCompile(`^[\s.\-|]*PM[\sPM.\-|]*$`)
+
+// token is a note as written on one line, with its columns relative to the
+// start of the line's content.
+type token struct {
+	note       Note
+	start, end int // end is exclusive and includes bends and vibrato
+	digits     int // width of the fret number
+}
+
+// system is a group of string lines written one above another.
+type system struct {
+	labels []string
+	tokens [][]token // per line, top (highest string) first
+	bars   map[int]bool
+	width  int
+	pm     [][2]int // palm-muted column ranges, inclusive
+}
+
+// Parse reads ASCII tab. Systems (groups of string lines, highest string
+// first) are separated by blank lines and joined into one tab; each may have
+// palm mute lines ("PM---|") above it. Fret numbers may be followed by b
+// (bend, optionally with the target fret and r plus the release fret) and ~
+// (vibrato), and preceded by h, p, / or \ to join them to the previous note
+// on the string. x is a muted note.
+func Parse(text string) (*Tab, error) {
+	var systems []system
+	var cur *system
+	var pmLines []string
+	for i, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		lineNo := i + 1
+		line = strings.TrimRight(line, " \t")
+		if strings.TrimSpace(line) == "" {
+			if cur != nil {
+				systems = append(systems, *cur)
+				cur = nil
+			}
+			pmLines = nil
+			continue
+		}
+		if palmMuteLine.MatchString(line) {
+			if cur != nil {
+				return nil, fmt.Errorf("line %d: palm mute line below a string", lineNo)
+			}
+			pmLines = append(pmLines, line)
+			continue
+		}
+		match := stringLine.FindStringSubmatchIndex(line)
+		if match == nil {
+			return nil, fmt.Errorf("line %d: not a tab line: %q", lineNo, line)
+		}
+		label := ""
+		if match[2] >= 0 {
+			label = line[match[2]:match[3]]
+		}
+		offset := match[4]
+		tokens, bars, err := parseString(line[offset:])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		if cur == nil {
+			cur = &system{bars: map[int]bool{}}
+			for _, pm := range pmLines {
+				cur.pm = append(cur.pm, palmMuteRanges(pm, offset)...)
+			}
+		}
+		cur.labels = append(cur.labels, label)
+		cur.tokens = append(cur.tokens, tokens)
+		for _, b := range bars {
+			cur.bars[b] = true
+		}
+		if n := len(line) - offset; n > cur.width {
+			cur.width = n
+		}
+	}
+	if cur != nil {
+		systems = append(systems, *cur)
+	}
+	if len(systems) == 0 {
+		return nil, fmt.Errorf("no tab found")
+	}
+
+	t := &Tab{}
+	strs := len(systems[0].labels)
+	if strs < 2 {
+		return nil, fmt.Errorf("a tab needs at least two strings, found %d", strs)
+	}
+	for i := strs - 1; i >= 0; i-- {
+		t.Strings = append(t.Strings, systems[0].labels[i])
+	}
+	for i, s := range systems {
+		if len(s.labels) != strs {
+			return nil, fmt.Errorf("system %d has %d strings, want %d", i+1, len(s.labels), strs)
+		}
+		t.Measures = append(t.Measures, s.measures()...)
+	}
+	return t, nil
+}
+
+// parseString reads the content of one string line after its first bar line.
+func parseString(s string) ([]token, []int, error) {
+	var tokens []token
+	var bars []int
+	pending := Picked
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '-' || c == ' ':
+			i++
+		case c == '|':
+			bars = append(bars, i)
+			pending = Picked
+			i++
+		case c == 'h' || c == 'p' || c == '/' || c == '\\':
+			pending = map[byte]Technique{'h': HammerOn, 'p': PullOff, '/': SlideUp, '\\': SlideDown}[c]
+			i++
+		case c == 'x' || c == 'X':
+			tokens = append(tokens, token{note: Note{Muted: true, Approach: pending}, start: i, end: i + 1, digits: 1})
+			pending = Picked
+			i++
+		case isDigit(c):
+			tok := token{start: i}
+			tok.note.Approach = pending
+			pending = Picked
+			tok.note.Fret, i = readNumber(s, i)
+			tok.digits = i - tok.start
+			if i < len(s) && s[i] == 'b' {
+				tok.note.Bend = true
+				tok.note.BendTo, i = readNumber(s, i+1)
+				if i < len(s) && s[i] == 'r' {
+					if i+1 >= len(s) || !isDigit(s[i+1]) {
+						return nil, nil, fmt.Errorf("column %d: release without a fret", i+1)
+					}
+					tok.note.Release, i = readNumber(s, i+1)
+				}
+			}
+			for i < len(s) && s[i] == '~' {
+				tok.note.Vibrato = true
+				i++
+			}
+			tok.end = i
+			tokens = append(tokens, tok)
+		default:
+			return nil, nil, fmt.Errorf("column %d: unexpected %q", i+1, c)
+		}
+	}
+	return tokens, bars, nil
+}
+
+// palmMuteRanges returns the column ranges covered by each PM marking,
+// relative to the string content starting at offset.
+func palmMuteRanges(line string, offset int) [][2]int {
+	var out [][2]int
+	for i := 0; i < len(line); i++ {
+		if !strings.HasPrefix(line[i:], "PM") {
+			continue
+		}
+		end := i + 1
+		for end+1 < len(line) && strings.IndexByte("-.|", line[end+1]) >= 0 {
+			end++
+		}
+		out = append(out, [2]int{i - offset, end - offset})
+		i = end
+	}
+	return out
+}
+
+// measures groups the system's notes into beats (notes starting in the same
+// column) and the beats into measures at the bar lines.
+func (s system) measures() []Measure {
+	var all []token
+	for line, toks := range s.tokens {
+		for _, tok := range toks {
+			tok.note.String = len(s.tokens) - 1 - line
+			all = append(all, tok)
+		}
+	}
+	sort.SliceStable(all, func(i, j int) bool { return all[i].start < all[j].start })
+
+	bars := make([]int, 0, len(s.bars))
+	for b := range s.bars {
+		bars = append(bars, b)
+	}
+	sort.Ints(bars)
+	bars = append(bars, s.width) // close the last measure
+
+	var out []Measure
+	measureStart, next := 0, 0
+	for _, bar := range bars {
+		var m Measure
+		prevEnd := measureStart
+		for next < len(all) && all[next].start < bar {
+			start, digits, end := all[next].start, all[next].digits, all[next].end
+			beat := Beat{Space: max(start-prevEnd, 1)}
+			for next < len(all) && all[next].start < start+digits && all[next].start < bar {
+				beat.Notes = append(beat.Notes, all[next].note)
+				digits = max(digits, all[next].start-start+all[next].digits)
+				end = max(end, all[next].end)
+				next++
+			}
+			sort.Slice(beat.Notes, func(i, j int) bool { return beat.Notes[i].String < beat.Notes[j].String })
+			for _, r := range s.pm {
+				if start >= r[0] && start <= r[1] {
+					beat.PalmMute = true
+				}
+			}
+			m.Beats = append(m.Beats, beat)
+			prevEnd = end
+		}
+		m.Tail = max(bar-prevEnd, 1)
+		if len(m.Beats) > 0 || bar-measureStart > 1 {
+			out = append(out, m)
+		}
+		measureStart = bar + 1
+	}
+	return out
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+// readNumber reads the digits at s[i:], returning 0 when there are none.
+func readNumber(s string, i int) (int, int) {
+	n := 0
+	for i < len(s) && isDigit(s[i]) {
+		n = n*10 + int(s[i]-'0')
+		i++
+	}
+	return n, i
+}
@@ -0,0 +1,97 @@
+package tab
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseNotes(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want []Note
+	}{
+		{"open and fretted", "e|-0-3-|\nB|-----|", []Note{{String: 1}, {String: 1, Fret: 3}}},
+		{"two-digit fret", "e|--12--|\nB|-------|", []Note{{String: 1, Fret: 12}}},
+		{"muted", "e|-x-|\nB|-X-|", []Note{{String: 0, Muted: true}, {String: 1, Muted: true}}},
+		{"hammer-on and pull-off", "G|-5h7p5-|\nD|--------|", []Note{
+			{String: 1, Fret: 5}, {String: 1, Fret: 7, Approach: HammerOn}, {String: 1, Fret: 5, Approach: PullOff}}},
+		{"slides", `G|-7/9\7-|` + "\nD|--------|", []Note{
+			{String: 1, Fret: 7}, {String: 1, Fret: 9, Approach: SlideUp}, {String: 1, Fret: 7, Approach: SlideDown}}},
+		{"bend, release and vibrato", "G|-7b9r7~-|\nD|---------|", []Note{
+			{String: 1, Fret: 7, Bend: true, BendTo: 9, Release: 7, Vibrato: true}}},
+		{"bend without target", "G|-7b--|\nD|-----|", []Note{{String: 1, Fret: 7, Bend: true}}},
+		{"technique doesn't cross a bar", "G|-5h|7-|\nD|-----|", []Note{{String: 1, Fret: 5}, {String: 1, Fret: 7}}},
+		{"chord sorted low to high", "e|-0-|\nB|-1-|\nG|-0-|", []Note{{String: 0}, {String: 1, Fret: 1}, {String: 2}}},
+		{"labels optional", "|-1-|\n|-2-|", []Note{{String: 0, Fret: 2}, {String: 1, Fret: 1}}},
+	}
+	for _, tt := range tests {
+		tb, err := Parse(tt.src)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if got := tb.Notes(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseLayout(t *testing.T) {
+	src := strings.Join([]string{
+		"   PM-------|",
+		"e|-----------|---|",
+		"B|-----------|-1-|",
+		"G|-0---2-----|---|",
+		"D|-2--12-----|---|",
+		"",
+		"e|---|",
+		"B|-3-|",
+		"G|---|",
+		"D|---|",
+	}, "\n")
+	tb, err := Parse(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"D", "G", "B", "e"}; !reflect.DeepEqual(tb.Strings, want) {
+		t.Errorf("strings = %v, want %v", tb.Strings, want)
+	}
+	if len(tb.Measures) != 3 {
+		t.Fatalf("got %d measures, want 3", len(tb.Measures))
+	}
+	first := tb.Measures[0]
+	if len(first.Beats) != 2 {
+		t.Fatalf("first measure has %d beats, want 2", len(first.Beats))
+	}
+	if n := len(first.Beats[1].Notes); n != 2 {
+		t.Errorf("second beat has %d notes, want 2 (12 and 2 start in the same column)", n)
+	}
+	if !first.Beats[0].PalmMute || !first.Beats[1].PalmMute {
+		t.Errorf("first measure beats palm muted = %v, %v, want both", first.Beats[0].PalmMute, first.Beats[1].PalmMute)
+	}
+	if tb.Measures[1].Beats[0].PalmMute {
+		t.Error("second measure is palm muted")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name, src, wantErr string
+	}{
+		{"empty", "", "no tab found"},
+		{"one string", "e|-0-|", "at least two strings"},
+		{"prose", "e|-0-|\nplay this slowly", `line 2: not a tab line`},
+		{"bad character", "e|-0-|\nB|-q-|", "line 2: column 2: unexpected 'q'"},
+		{"release without fret", "e|-7b9r-|\nB|------|", "line 1: column 5: release without a fret"},
+		{"palm mute below a string", "e|-0-|\nPM--|", "line 2: palm mute line below a string"},
+		{"uneven systems", "e|-0-|\nB|-0-|\n\ne|-0-|\nB|-0-|\nG|-0-|", "system 2 has 3 strings, want 2"},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.src)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: got error %v, want one containing %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package tab
+
+import (
+	"strconv"
+	"strings"
+)
+
+// cell is how a note is written, without the technique joining it to the
+// previous note.
+func (n Note) cell() string {
+	if n.Muted {
+		return "x"
+	}
+	s := strconv.Itoa(n.Fret)
+	if n.Bend {
+		s += "b"
+		if n.BendTo > 0 {
+			s += strconv.Itoa(n.BendTo)
+		}
+		if n.Release > 0 {
+			s += "r" + strconv.Itoa(n.Release)
+		}
+	}
+	if n.Vibrato {
+		s += "~"
+	}
+	return s
+}
+
+// piece is a run of columns drawn across every string: a beat with the gap
+// before it, followed by the measure's tail and bar line for its last beat.
+type piece struct {
+	lines    []string // one per string, lowest first
+	space    int      // columns before the beat's notes
+	palmMute bool
+	bar      bool // ends a measure
+}
+
+func (p piece) width() int { return len(p.lines[0]) }
+
+// pieces lays out every beat and bar line, padding the cells of each beat to
+// a common width so the strings stay aligned.
+func (t *Tab) pieces() []piece {
+	var out []piece
+	for _, m := range t.Measures {
+		for _, b := range m.Beats {
+			width := 1
+			cells := make([]string, len(t.Strings))
+			techniques := make([]string, len(t.Strings))
+			for _, n := range b.Notes {
+				if n.String < 0 || n.String >= len(t.Strings) {
+					continue
+				}
+				cells[n.String] = n.cell()
+				techniques[n.String] = n.Approach.Symbol()
+				width = max(width, len(cells[n.String]))
+			}
+			space := max(b.Space, 1)
+			p := piece{lines: make([]string, len(t.Strings)), space: space, palmMute: b.PalmMute}
+			for s := range t.Strings {
+				gap := strings.Repeat("-", space-len(techniques[s])) + techniques[s]
+				p.lines[s] = gap + cells[s] + strings.Repeat("-", width-len(cells[s]))
+			}
+			out = append(out, p)
+		}
+		tail := strings.Repeat("-", max(m.Tail, 1)) + "|"
+		if len(m.Beats) == 0 {
+			out = append(out, piece{lines: make([]string, len(t.Strings))})
+		}
+		last := &out[len(out)-1]
+		for s := range last.lines {
+			last.lines[s] += tail
+		}
+		last.bar = true
+	}
+	return out
+}
+
+// Render lays the tab out as text, highest string first, with each line at
+// most width characters wide. Lines are wrapped at bar lines where possible
+// and between beats when a measure is too wide on its own. A width of zero
+// or less means no wrapping.
+func (t *Tab) Render(width int) string {
+	labels := make([]string, len(t.Strings))
+	labelWidth := 0
+	for _, l := range t.Strings {
+		labelWidth = max(labelWidth, len(l))
+	}
+	for i, l := range t.Strings {
+		labels[i] = l + strings.Repeat(" ", labelWidth-len(l)) + "|"
+	}
+	avail := width - labelWidth - 1
+	if width <= 0 {
+		avail = int(^uint(0) >> 1)
+	}
+
+	// Group pieces into measures, then measures into systems.
+	var measures [][]piece
+	var cur []piece
+	for _, p := range t.pieces() {
+		cur = append(cur, p)
+		if p.bar {
+			measures = append(measures, cur)
+			cur = nil
+		}
+	}
+	var systems [][]piece
+	var line []piece
+	used := 0
+	for _, m := range measures {
+		w := 0
+		for _, p := range m {
+			w += p.width()
+		}
+		if used+w <= avail {
+			line, used = append(line, m...), used+w
+			continue
+		}
+		if len(line) > 0 {
+			systems, line, used = append(systems, line), nil, 0
+		}
+		for _, p := range m {
+			if used+p.width() > avail && len(line) > 0 {
+				systems, line, used = append(systems, line), nil, 0
+			}
+			line, used = append(line, p), used+p.width()
+		}
+	}
+	if len(line) > 0 {
+		systems = append(systems, line)
+	}
+
+	var out []string
+	for i, sys := range systems {
+		if i > 0 {
+			out = append(out, "")
+		}
+		if pm := palmMuteRow(sys); pm != "" {
+			out = append(out, strings.Repeat(" ", labelWidth+1)+pm)
+		}
+		for s := len(t.Strings) - 1; s >= 0; s-- {
+			var b strings.Builder
+			b.WriteString(labels[s])
+			for _, p := range sys {
+				b.WriteString(p.lines[s])
+			}
+			out = append(out, b.String())
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+// String renders the tab without wrapping.
+func (t *Tab) String() string {
+	return t.Render(0)
+}
+
+// palmMuteRow draws "PM--|" over each run of palm-muted beats, or returns ""
+// when the system has none.
+func palmMuteRow(sys []piece) string {
+	offsets := make([]int, len(sys)+1)
+	for i, p := range sys {
+		offsets[i+1] = offsets[i] + p.width()
+	}
+	row := []byte(strings.Repeat(" ", offsets[len(sys)]))
+	found := false
+	for i := 0; i < len(sys); i++ {
+		if !sys[i].palmMute {
+			continue
+		}
+		found = true
+		j := i
+		for j+1 < len(sys) && sys[j+1].palmMute {
+			j++
+		}
+		start, end := offsets[i]+sys[i].space, offsets[j+1]-1
+		for c := start; c <= end; c++ {
+			row[c] = '-'
+		}
+		copy(row[start:], "PM")
+		if end-start >= 3 {
+			row[end] = '|'
+		}
+		i = j
+	}
+	if !found {
+		return ""
+	}
+	return strings.TrimRight(string(row), " ")
+}
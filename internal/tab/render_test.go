@@ -0,0 +1,83 @@
+package tab
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const riff = `PM----------------|
+e|-----------------|-----------------|
+B|-----------------|-----------------|
+G|-----------------|-----5h7p5-------|
+D|-----------------|-7/9-------7b9r7~|
+A|-----5-----5-----|-----------------|
+E|-0-0---0-0---3-0-|-----------------|`
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name, src, want string
+	}{
+		{"keeps spacing", "e|-0---3-|\nB|-1-----|", "e|-0---3-|\nB|-1-----|"},
+		{"aligns wide cells", "e|-12-|\nB|-x--|", "e|-12-|\nB|-x--|"},
+		{"techniques", "G|-5h7p5-|\nD|-------|", "G|-5h7p5-|\nD|-------|"},
+		{"pads labels", "Eb|-0-|\nBb|-0-|\nD|-0-|", "Eb|-0-|\nBb|-0-|\nD |-0-|"},
+	}
+	for _, tt := range tests {
+		tb, err := Parse(tt.src)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if got := tb.String(); got != tt.want {
+			t.Errorf("%s: got\n%s\nwant\n%s", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestRenderRoundTrip checks rendered tab parses back to the same notes.
+// While every measure fits on a line it also renders the same again; a
+// measure split across lines comes back as two.
+func TestRenderRoundTrip(t *testing.T) {
+	tb, err := Parse(riff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, width := range []int{0, 80, 30, 12} {
+		out := tb.Render(width)
+		again, err := Parse(out)
+		if err != nil {
+			t.Errorf("width %d: rendered tab doesn't parse: %v\n%s", width, err, out)
+			continue
+		}
+		if !reflect.DeepEqual(again.Notes(), tb.Notes()) {
+			t.Errorf("width %d: notes changed\n%s", width, out)
+		}
+		if got := again.Render(width); width != 12 && got != out {
+			t.Errorf("width %d: second render differs\n%s\nwant\n%s", width, got, out)
+		}
+	}
+}
+
+func TestRenderWraps(t *testing.T) {
+	tb, err := Parse(riff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, width := range []int{30, 20, 12} {
+		out := tb.Render(width)
+		for _, line := range strings.Split(out, "\n") {
+			if len(line) > width {
+				t.Errorf("width %d: line %q is %d wide", width, line, len(line))
+			}
+		}
+	}
+	// Wrapping at bar lines: each measure fits in 30 columns on its own.
+	out := tb.Render(30)
+	if systems := strings.Count(out, "\n\n") + 1; systems != 2 {
+		t.Errorf("width 30: got %d systems, want 2\n%s", systems, out)
+	}
+	if !strings.HasPrefix(out, "   PM") {
+		t.Errorf("width 30: palm mute line missing from the first system\n%s", out)
+	}
+}
@@ -0,0 +1,71 @@
+package tab
+
+// Technique is how a note is reached from the previous note on its string.
+type Technique int
+
+// Techniques written between two notes on a string.
+const (
+	Picked Technique = iota
+	HammerOn
+	PullOff
+	SlideUp
+	SlideDown
+)
+
+// Symbol returns the tab notation for t, or "" for a picked note.
+func (t Technique) Symbol() string {
+	switch t {
+	case HammerOn:
+		return "h"
+	case PullOff:
+		return "p"
+	case SlideUp:
+		return "/"
+	case SlideDown:
+		return `\`
+	default:
+		return ""
+	}
+}
+
+// Note is one fretted, open or muted note.
+type Note struct {
+	String   int  // 0 = lowest string
+	Fret     int  // ignored for muted notes
+	Muted    bool // dead note, written x
+	Approach Technique
+	Bend     bool
+	BendTo   int // fret the bend reaches; 0 when not written
+	Release  int // fret the bend is released to; 0 when not released
+	Vibrato  bool
+}
+
+// Beat is the notes struck together in one column of the tab.
+type Beat struct {
+	Notes    []Note
+	PalmMute bool
+	Space    int // dashes before the beat, kept from the source for rhythm
+}
+
+// Measure is the beats between two bar lines.
+type Measure struct {
+	Beats []Beat
+	Tail  int // dashes after the last beat
+}
+
+// Tab is a parsed guitar tab.
+type Tab struct {
+	Strings  []string // string labels, lowest first (E A D G B e)
+	Measures []Measure
+}
+
+// Notes returns every note in order, beat by beat.
+func (t *Tab) Notes() []Note {
+	var out []Note
+	for _, m := range t.Measures {
+		for _, b := range m.Beats {
+			out = append(out, b.Notes...)
+		}
+	}
+	return out
+}
@@ -0,0 +1,114 @@
+package theory
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ChordQuality is an interval formula for a chord, e.g. minor seventh.
+type ChordQuality struct {
+	Name      string
+	Symbol    string // suffix after the root, e.g. "m7"
+	Intervals []Interval
+}
+
+var chordQualities = []ChordQuality{
+	{"major", "", []Interval{Unison, MajorThird, PerfectFifth}},
+	{"minor", "m", []Interval{Unison, MinorThird, PerfectFifth}},
+	{"diminished", "dim", []Interval{Unison, MinorThird, DiminishedFifth}},
+	{"augmented", "aug", []Interval{Unison, MajorThird, AugmentedFifth}},
+	{"sus2", "sus2", []Interval{Unison, MajorSecond, PerfectFifth}},
+	{"sus4", "sus4", []Interval{Unison, PerfectFourth, PerfectFifth}},
+	{"power", "5", []Interval{Unison, PerfectFifth}},
+	{"major sixth", "6", []Interval{Unison, MajorThird, PerfectFifth, MajorSixth}},
+	{"minor sixth", "m6", []Interval{Unison, MinorThird, PerfectFifth, MajorSixth}},
+	{"dominant seventh", "7", []Interval{Unison, MajorThird, PerfectFifth, MinorSeventh}},
+	{"major seventh", "maj7", []Interval{Unison, MajorThird, PerfectFifth, MajorSeventh}},
+	{"minor seventh", "m7", []Interval{Unison, MinorThird, PerfectFifth, MinorSeventh}},
+	{"half-diminished", "m7b5", []Interval{Unison, MinorThird, DiminishedFifth, MinorSeventh}},
+	{"diminished seventh", "dim7", []Interval{Unison, MinorThird, DiminishedFifth, DiminishedSeventh}},
+	{"seventh sus4", "7sus4", []Interval{Unison, PerfectFourth, PerfectFifth, MinorSeventh}},
+	{"add9", "add9", []Interval{Unison, MajorThird, PerfectFifth, MajorNinth}},
+	{"dominant ninth", "9", []Interval{Unison, MajorThird, PerfectFifth, MinorSeventh, MajorNinth}},
+}
+
+// chordQualityAliases maps alternative names and symbols to a canonical symbol.
+var chordQualityAliases = map[string]string{
+	"maj":  "",
+	"M":    "",
+	"min":  "m",
+	"-":    "m",
+	"°":    "dim",
+	"+":    "aug",
+	"sus":  "sus4",
+	"dom7": "7",
+	"M7":   "maj7",
+	"Δ7":   "maj7",
+	"min7": "m7",
+	"-7":   "m7",
+	"ø":    "m7b5",
+	"ø7":   "m7b5",
+	"°7":   "dim7",
+}
+
+// ChordQualities returns all built-in chord qualities.
+func ChordQualities() []ChordQuality {
+	out := make([]ChordQuality, len(chordQualities))
+	copy(out, chordQualities)
+	return out
+}
+
+// LookupChordQuality finds a quality by symbol ("m7"), alias ("min7") or
+// name ("minor seventh"). Symbols are case-sensitive so "M7" and "m7" differ;
+// names are not.
+func LookupChordQuality(s string) (ChordQuality, bool) {
+	s = strings.TrimSpace(s)
+	if alias, ok := chordQualityAliases[s]; ok {
+		s = alias
+	}
+	for _, q := range chordQualities {
+		if q.Symbol == s {
+			return q, true
+		}
+	}
+	name := normaliseTypeName(s)
+	for _, q := range chordQualities {
+		if normaliseTypeName(q.Name) == name {
+			return q, true
+		}
+	}
+	return ChordQuality{}, false
+}
+
+// Notes spells the chord from root.
+func (q ChordQuality) Notes(root Note) []Note {
+	notes := make([]Note, len(q.Intervals))
+	for i, iv := range q.Intervals {
+		notes[i] = root.Transpose(iv)
+	}
+	return notes
+}
+
+// ParseChordSymbol splits a symbol such as "F#m7" or "Bbmaj7" into its root
+// and quality.
+func ParseChordSymbol(sym string) (Note, ChordQuality, error) {
+	sym = strings.TrimSpace(sym)
+	if sym == "" {
+		return Note{}, ChordQuality{}, fmt.Errorf("empty chord symbol")
+	}
+	// The root is the letter plus any accidentals; "b" directly after the
+	// letter is a flat, never part of the quality.
+	i := 1
+	for i < len(sym) && (sym[i] == '#' || sym[i] == 'b') {
+		i++
+	}
+	root, err := ParseNote(sym[:i])
+	if err != nil {
+		return Note{}, ChordQuality{}, err
+	}
+	q, ok := LookupChordQuality(sym[i:])
+	if !ok {
+		return Note{}, ChordQuality{}, fmt.Errorf("unknown chord quality %q in %q", sym[i:], sym)
+	}
+	return root, q, nil
+}
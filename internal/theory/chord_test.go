@@ -0,0 +1,47 @@
+package theory
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseChordSymbol(t *testing.T) {
+	tests := []struct {
+		sym  string
+		want []string
+	}{
+		{"C", []string{"C", "E", "G"}},
+		{"Am", []string{"A", "C", "E"}},
+		{"F#m7", []string{"F#", "A", "C#", "E"}},
+		{"Bbmaj7", []string{"Bb", "D", "F", "A"}},
+		{"Bb", []string{"Bb", "D", "F"}},
+		{"Ebm7b5", []string{"Eb", "Gb", "Bbb", "Db"}},
+		{"Bdim7", []string{"B", "D", "F", "Ab"}},
+		{"Caug", []string{"C", "E", "G#"}},
+		{"C+", []string{"C", "E", "G#"}},
+		{"Dsus", []string{"D", "G", "A"}},
+		{"G7", []string{"G", "B", "D", "F"}},
+		{"E5", []string{"E", "B"}},
+		{"Cadd9", []string{"C", "E", "G", "D"}},
+		{"A-7", []string{"A", "C", "E", "G"}},
+		{"CΔ7", []string{"C", "E", "G", "B"}},
+	}
+	for _, tt := range tests {
+		root, q, err := ParseChordSymbol(tt.sym)
+		if err != nil {
+			t.Errorf("ParseChordSymbol(%q): %v", tt.sym, err)
+			continue
+		}
+		if got := NoteNames(q.Notes(root)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s = %v, want %v", tt.sym, got, tt.want)
+		}
+	}
+}
+
+func TestParseChordSymbolErrors(t *testing.T) {
+	for _, sym := range []string{"", "H7", "Cmaj13#11", "C/G"} {
+		if _, _, err := ParseChordSymbol(sym); err == nil {
+			t.Errorf("ParseChordSymbol(%q): no error", sym)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package theory
+
+import "fmt"
+
+// Interval is a distance between two notes. Degree is the diatonic size
+// (1 = unison, 3 = third, 8 = octave) and decides spelling; Semitones is the
+// chromatic size and decides pitch.
+type Interval struct {
+	Degree    int
+	Semitones int
+}
+
+// Common intervals.
+var (
+	Unison            = Interval{1, 0}
+	MinorSecond       = Interval{2, 1}
+	MajorSecond       = Interval{2, 2}
+	AugmentedSecond   = Interval{2, 3}
+	MinorThird        = Interval{3, 3}
+	MajorThird        = Interval{3, 4}
+	PerfectFourth     = Interval{4, 5}
+	AugmentedFourth   = Interval{4, 6}
+	DiminishedFifth   = Interval{5, 6}
+	PerfectFifth      = Interval{5, 7}
+	AugmentedFifth    = Interval{5, 8}
+	MinorSixth        = Interval{6, 8}
+	MajorSixth        = Interval{6, 9}
+	DiminishedSeventh = Interval{7, 9}
+	MinorSeventh      = Interval{7, 10}
+	MajorSeventh      = Interval{7, 11}
+	Octave            = Interval{8, 12}
+	MinorNinth        = Interval{9, 13}
+	MajorNinth        = Interval{9, 14}
+	AugmentedNinth    = Interval{9, 15}
+	PerfectEleventh   = Interval{11, 17}
+	AugmentedEleventh = Interval{11, 18}
+	MinorThirteenth   = Interval{13, 20}
+	MajorThirteenth   = Interval{13, 21}
+)
+
+// perfectDegrees marks the simple degrees that take perfect rather than major/minor quality.
+var perfectDegrees = map[int]bool{1: true, 4: true, 5: true}
+
+// referenceSemitones is the size of the perfect or major interval for each simple degree.
+var referenceSemitones = map[int]int{1: 0, 2: 2, 3: 4, 4: 5, 5: 7, 6: 9, 7: 11}
+
+// Name returns the short interval name, e.g. "P5", "m3", "A4", "M9".
+func (iv Interval) Name() string {
+	if iv.Degree < 1 {
+		return fmt.Sprintf("?%d", iv.Semitones)
+	}
+	simple := (iv.Degree-1)%7 + 1
+	ref := referenceSemitones[simple] + 12*((iv.Degree-1)/7)
+	diff := iv.Semitones - ref
+	var quality string
+	if perfectDegrees[simple] {
+		switch diff {
+		case 0:
+			quality = "P"
+		case -1:
+			quality = "d"
+		case 1:
+			quality = "A"
+		}
+	} else {
+		switch diff {
+		case 0:
+			quality = "M"
+		case -1:
+			quality = "m"
+		case -2:
+			quality = "d"
+		case 1:
+			quality = "A"
+		}
+	}
+	if quality == "" {
+		return fmt.Sprintf("?%d", iv.Semitones)
+	}
+	return fmt.Sprintf("%s%d", quality, iv.Degree)
+}
+
+// String implements fmt.Stringer.
+func (iv Interval) String() string {
+	return iv.Name()
+}
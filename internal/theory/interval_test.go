@@ -0,0 +1,43 @@
+package theory
+
+import "testing"
+
+func TestIntervalName(t *testing.T) {
+	tests := []struct {
+		iv   Interval
+		want string
+	}{
+		{Unison, "P1"},
+		{MinorSecond, "m2"},
+		{MajorSecond, "M2"},
+		{AugmentedSecond, "A2"},
+		{MinorThird, "m3"},
+		{MajorThird, "M3"},
+		{Interval{4, 4}, "d4"},
+		{PerfectFourth, "P4"},
+		{AugmentedFourth, "A4"},
+		{DiminishedFifth, "d5"},
+		{PerfectFifth, "P5"},
+		{AugmentedFifth, "A5"},
+		{MinorSixth, "m6"},
+		{MajorSixth, "M6"},
+		{DiminishedSeventh, "d7"},
+		{MinorSeventh, "m7"},
+		{MajorSeventh, "M7"},
+		{Octave, "P8"},
+		{MinorNinth, "m9"},
+		{MajorNinth, "M9"},
+		{AugmentedNinth, "A9"},
+		{PerfectEleventh, "P11"},
+		{AugmentedEleventh, "A11"},
+		{MinorThirteenth, "m13"},
+		{MajorThirteenth, "M13"},
+		{Interval{3, 7}, "?7"},
+		{Interval{0, 2}, "?2"},
+	}
+	for _, tt := range tests {
+		if got := tt.iv.Name(); got != tt.want {
+			t.Errorf("%+v.Name() = %q, want %q", tt.iv, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package theory
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PitchClass is a note name with octave and spelling removed (C=0 ... B=11).
+type PitchClass int
+
+var sharpNames = []string{"C", "C#", "D", "D#", "E", "F", "F#", "G", "G#", "A", "A#", "B"}
+
+// NewPitchClass wraps any integer into the 0..11 range.
+func NewPitchClass(n int) PitchClass {
+	return PitchClass(((n % 12) + 12) % 12)
+}
+
+// Add returns the pitch class the given number of semitones above p.
+func (p PitchClass) Add(semitones int) PitchClass {
+	return NewPitchClass(int(p) + semitones)
+}
+
+// String returns the sharp spelling of the pitch class (e.g. "F#").
+func (p PitchClass) String() string {
+	return sharpNames[NewPitchClass(int(p))]
+}
+
+// letters lists the natural note letters in scale order starting on C.
+const letters = "CDEFGAB"
+
+// naturalPitch is the pitch class of each natural letter, indexed like letters.
+var naturalPitch = []int{0, 2, 4, 5, 7, 9, 11}
+
+// Note is a spelled note name: a letter plus an accidental offset in semitones
+// (-1 flat, +1 sharp, ±2 double). F# and Gb share a pitch class but are different Notes.
+type Note struct {
+	Letter     byte
+	Accidental int
+}
+
+// ParseNote parses names like "C", "F#", "Bb", "Ebb", "Gx", "C♯" or "D♭".
+func ParseNote(s string) (Note, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Note{}, fmt.Errorf("empty note name")
+	}
+	letter := s[0]
+	if letter >= 'a' && letter <= 'g' {
+		letter -= 'a' - 'A'
+	}
+	if strings.IndexByte(letters, letter) < 0 {
+		return Note{}, fmt.Errorf("invalid note letter in %q", s)
+	}
+	acc := 0
+	for _, r := range s[1:] {
+		switch r {
+		case '#', '♯':
+			acc++
+		case 'b', '♭':
+			acc--
+		case 'x', '𝄪':
+			acc += 2
+		default:
+			return Note{}, fmt.Errorf("invalid accidental %q in %q", r, s)
+		}
+	}
+	if acc < -2 || acc > 2 {
+		return Note{}, fmt.Errorf("too many accidentals in %q", s)
+	}
+	return Note{Letter: letter, Accidental: acc}, nil
+}
+
+// MustParseNote is like ParseNote but panics on error. Intended for static tables.
+func MustParseNote(s string) Note {
+	n, err := ParseNote(s)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+// letterIndex returns the position of the note letter in letters (C=0 ... B=6).
+func (n Note) letterIndex() int {
+	return strings.IndexByte(letters, n.Letter)
+}
+
+// PitchClass returns the sounding pitch class of the note.
+func (n Note) PitchClass() PitchClass {
+	return NewPitchClass(naturalPitch[n.letterIndex()] + n.Accidental)
+}
+
+// String returns the note name using ASCII accidentals (e.g. "F#", "Bb", "Cbb").
+func (n Note) String() string {
+	var b strings.Builder
+	b.WriteByte(n.Letter)
+	switch {
+	case n.Accidental > 0:
+		b.WriteString(strings.Repeat("#", n.Accidental))
+	case n.Accidental < 0:
+		b.WriteString(strings.Repeat("b", -n.Accidental))
+	}
+	return b.String()
+}
+
+// Transpose returns the note a given interval above n, spelled so that the
+// letter moves by the interval's degree (a major third above Eb is G, a
+// diminished fourth above E is Ab).
+func (n Note) Transpose(iv Interval) Note {
+	idx := (n.letterIndex() + iv.Degree - 1) % 7
+	if idx < 0 {
+		idx += 7
+	}
+	target := int(n.PitchClass().Add(iv.Semitones))
+	acc := target - naturalPitch[idx]
+	// Pick the smallest accidental that reaches the target pitch class.
+	for acc > 6 {
+		acc -= 12
+	}
+	for acc < -6 {
+		acc += 12
+	}
+	return Note{Letter: letters[idx], Accidental: acc}
+}
+
+// SpellPitchClass names a pitch class, preferring flats when preferFlats is set.
+func SpellPitchClass(p PitchClass, preferFlats bool) Note {
+	p = NewPitchClass(int(p))
+	for i, np := range naturalPitch {
+		if PitchClass(np) == p {
+			return Note{Letter: letters[i]}
+		}
+	}
+	if preferFlats {
+		n := SpellPitchClass(p.Add(1), false)
+		n.Accidental--
+		return n
+	}
+	n := SpellPitchClass(p.Add(-1), false)
+	n.Accidental++
+	return n
+}
@@ -0,0 +1,100 @@
+package theory
+
+import "testing"
+
+func TestParseNote(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Note
+		wantErr bool
+	}{
+		{"C", Note{'C', 0}, false},
+		{"f#", Note{'F', 1}, false},
+		{"Bb", Note{'B', -1}, false},
+		{"Ebb", Note{'E', -2}, false},
+		{"Gx", Note{'G', 2}, false},
+		{"C♯", Note{'C', 1}, false},
+		{"D♭", Note{'D', -1}, false},
+		{" A ", Note{'A', 0}, false},
+		{"", Note{}, true},
+		{"H", Note{}, true},
+		{"C#b#x", Note{}, true},
+		{"Bbbb", Note{}, true},
+		{"C?", Note{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseNote(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseNote(%q) error = %v, want error %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseNote(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNotePitchClass(t *testing.T) {
+	tests := []struct {
+		note string
+		want PitchClass
+	}{
+		{"C", 0}, {"B#", 0}, {"Dbb", 0}, {"Cb", 11}, {"E#", 5}, {"Fb", 4}, {"Gx", 9},
+	}
+	for _, tt := range tests {
+		if got := MustParseNote(tt.note).PitchClass(); got != tt.want {
+			t.Errorf("%s.PitchClass() = %d, want %d", tt.note, got, tt.want)
+		}
+	}
+}
+
+// TestTranspose checks notes are spelled by the interval's degree, not the
+// nearest name for the pitch.
+func TestTranspose(t *testing.T) {
+	tests := []struct {
+		root string
+		iv   Interval
+		want string
+	}{
+		{"C", MajorThird, "E"},
+		{"Eb", MajorThird, "G"},
+		{"E", MajorThird, "G#"},
+		{"F#", MajorThird, "A#"},
+		{"Db", MinorThird, "Fb"},
+		{"E", Interval{4, 4}, "Ab"}, // diminished fourth
+		{"C", AugmentedFourth, "F#"},
+		{"C", DiminishedFifth, "Gb"},
+		{"B", PerfectFifth, "F#"},
+		{"Bb", DiminishedSeventh, "Abb"},
+		{"C#", DiminishedSeventh, "Bb"},
+		{"G#", AugmentedSecond, "A##"},
+		{"A", MajorSeventh, "G#"},
+		{"G", Octave, "G"},
+		{"D", MajorNinth, "E"},
+		{"C", AugmentedNinth, "D#"},
+		{"G#", MajorSeventh, "F##"},
+		{"Fb", MinorThird, "Abb"},
+	}
+	for _, tt := range tests {
+		if got := MustParseNote(tt.root).Transpose(tt.iv).String(); got != tt.want {
+			t.Errorf("%s + %s = %s, want %s", tt.root, tt.iv, got, tt.want)
+		}
+	}
+}
+
+func TestSpellPitchClass(t *testing.T) {
+	for pc := 0; pc < 12; pc++ {
+		for _, flats := range []bool{false, true} {
+			n := SpellPitchClass(PitchClass(pc), flats)
+			if n.PitchClass() != PitchClass(pc) {
+				t.Errorf("SpellPitchClass(%d, %v) = %s, which is pitch class %d", pc, flats, n, n.PitchClass())
+			}
+			if flats && n.Accidental > 0 || !flats && n.Accidental < 0 {
+				t.Errorf("SpellPitchClass(%d, %v) = %s", pc, flats, n)
+			}
+		}
+	}
+	if got := SpellPitchClass(-1, true).String(); got != "B" {
+		t.Errorf("SpellPitchClass(-1, true) = %s, want B", got)
+	}
+}
@@ -0,0 +1,78 @@
+package theory
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Pitch is an absolute pitch as a MIDI note number (middle C = C4 = 60).
+type Pitch int
+
+// StandardA4 is the default concert pitch reference in Hz.
+const StandardA4 = 440.0
+
+// ParsePitch parses scientific pitch notation such as "E2", "F#3" or "Bb-1".
+func ParsePitch(s string) (Pitch, error) {
+	s = strings.TrimSpace(s)
+	i := len(s)
+	for i > 0 && (s[i-1] >= '0' && s[i-1] <= '9') {
+		i--
+	}
+	if i > 0 && s[i-1] == '-' && i < len(s) {
+		i--
+	}
+	if i == 0 || i == len(s) {
+		return 0, fmt.Errorf("pitch %q needs a note name and octave", s)
+	}
+	n, err := ParseNote(s[:i])
+	if err != nil {
+		return 0, err
+	}
+	octave, err := strconv.Atoi(s[i:])
+	if err != nil {
+		return 0, fmt.Errorf("invalid octave in %q", s)
+	}
+	return PitchOf(n, octave), nil
+}
+
+// MustParsePitch is like ParsePitch but panics on error. Intended for static tables.
+func MustParsePitch(s string) Pitch {
+	p, err := ParsePitch(s)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// PitchOf returns the pitch of a spelled note in the given octave. The octave
+// follows the letter, so B#3 sounds the same as C4.
+func PitchOf(n Note, octave int) Pitch {
+	return Pitch((octave+1)*12 + naturalPitch[n.letterIndex()] + n.Accidental)
+}
+
+// PitchClass returns the pitch class of p.
+func (p Pitch) PitchClass() PitchClass {
+	return NewPitchClass(int(p))
+}
+
+// Octave returns the scientific-pitch octave number of p.
+func (p Pitch) Octave() int {
+	return int(math.Floor(float64(p)/12)) - 1
+}
+
+// Transpose returns the pitch the given number of semitones above p.
+func (p Pitch) Transpose(semitones int) Pitch {
+	return p + Pitch(semitones)
+}
+
+// Frequency returns the equal-tempered frequency of p in Hz for the given A4 reference.
+func (p Pitch) Frequency(a4 float64) float64 {
+	return a4 * math.Pow(2, float64(int(p)-69)/12)
+}
+
+// String returns the sharp spelling with octave, e.g. "F#3".
+func (p Pitch) String() string {
+	return fmt.Sprintf("%s%d", p.PitchClass(), p.Octave())
+}
@@ -0,0 +1,71 @@
+package theory
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParsePitch(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Pitch
+		wantErr bool
+	}{
+		{"C4", 60, false},
+		{"A4", 69, false},
+		{"E2", 40, false},
+		{"F#3", 54, false},
+		{"Bb-1", 10, false},
+		{"C-1", 0, false},
+		{"B#3", 60, false},
+		{"Cb4", 59, false},
+		{"B0", 23, false},
+		{"C", 0, true},
+		{"4", 0, true},
+		{"", 0, true},
+		{"H2", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParsePitch(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParsePitch(%q) error = %v, want error %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParsePitch(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestPitchString(t *testing.T) {
+	tests := []struct {
+		p    Pitch
+		want string
+	}{
+		{60, "C4"}, {40, "E2"}, {54, "F#3"}, {0, "C-1"}, {11, "B-1"}, {127, "G9"},
+	}
+	for _, tt := range tests {
+		if got := tt.p.String(); got != tt.want {
+			t.Errorf("Pitch(%d).String() = %q, want %q", tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestPitchFrequency(t *testing.T) {
+	tests := []struct {
+		p        string
+		a4, want float64
+	}{
+		{"A4", 440, 440},
+		{"A3", 440, 220},
+		{"A4", 442, 442},
+		{"C4", 440, 261.626},
+		{"E2", 440, 82.407},
+		{"B0", 440, 30.868},
+	}
+	for _, tt := range tests {
+		if got := MustParsePitch(tt.p).Frequency(tt.a4); math.Abs(got-tt.want) > 0.001 {
+			t.Errorf("%s at A4=%v = %.3f Hz, want %.3f", tt.p, tt.a4, got, tt.want)
+		}
+	}
+}
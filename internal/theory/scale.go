@@ -0,0 +1,104 @@
+package theory
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ScaleType is an interval formula that can be applied to any root.
+type ScaleType struct {
+	Name      string
+	Intervals []Interval
+}
+
+var scaleTypes = []ScaleType{
+	{"Major", []Interval{Unison, MajorSecond, MajorThird, PerfectFourth, PerfectFifth, MajorSixth, MajorSeventh}},
+	{"Minor", []Interval{Unison, MajorSecond, MinorThird, PerfectFourth, PerfectFifth, MinorSixth, MinorSeventh}},
+	{"Dorian", []Interval{Unison, MajorSecond, MinorThird, PerfectFourth, PerfectFifth, MajorSixth, MinorSeventh}},
+	{"Phrygian", []Interval{Unison, MinorSecond, MinorThird, PerfectFourth, PerfectFifth, MinorSixth, MinorSeventh}},
+	{"Lydian", []Interval{Unison, MajorSecond, MajorThird, AugmentedFourth, PerfectFifth, MajorSixth, MajorSeventh}},
+	{"Mixolydian", []Interval{Unison, MajorSecond, MajorThird, PerfectFourth, PerfectFifth, MajorSixth, MinorSeventh}},
+	{"Locrian", []Interval{Unison, MinorSecond, MinorThird, PerfectFourth, DiminishedFifth, MinorSixth, MinorSeventh}},
+	{"Harmonic Minor", []Interval{Unison, MajorSecond, MinorThird, PerfectFourth, PerfectFifth, MinorSixth, MajorSeventh}},
+	{"Melodic Minor", []Interval{Unison, MajorSecond, MinorThird, PerfectFourth, PerfectFifth, MajorSixth, MajorSeventh}},
+	{"Major Pentatonic", []Interval{Unison, MajorSecond, MajorThird, PerfectFifth, MajorSixth}},
+	{"Minor Pentatonic", []Interval{Unison, MinorThird, PerfectFourth, PerfectFifth, MinorSeventh}},
+	{"Blues", []Interval{Unison, MinorThird, PerfectFourth, DiminishedFifth, PerfectFifth, MinorSeventh}},
+	{"Major Blues", []Interval{Unison, MajorSecond, MinorThird, MajorThird, PerfectFifth, MajorSixth}},
+}
+
+// scaleTypeAliases maps alternative spellings (already normalised) to a canonical name.
+var scaleTypeAliases = map[string]string{
+	"ionian":           "major",
+	"natural minor":    "minor",
+	"aeolian":          "minor",
+	"minor blues":      "blues",
+	"harmonic":         "harmonic minor",
+	"melodic":          "melodic minor",
+	"jazz minor":       "melodic minor",
+	"pentatonic":       "major pentatonic",
+	"major pent":       "major pentatonic",
+	"minor pent":       "minor pentatonic",
+	"pentatonic major": "major pentatonic",
+	"pentatonic minor": "minor pentatonic",
+}
+
+func normaliseTypeName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = strings.NewReplacer("_", " ", "-", " ").Replace(name)
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// ScaleTypes returns all built-in scale types in display order.
+func ScaleTypes() []ScaleType {
+	out := make([]ScaleType, len(scaleTypes))
+	copy(out, scaleTypes)
+	return out
+}
+
+// LookupScaleType finds a scale type by name or alias, case-insensitively.
+// "natural_minor", "Natural Minor" and "aeolian" all resolve to Minor.
+func LookupScaleType(name string) (ScaleType, bool) {
+	key := normaliseTypeName(name)
+	if alias, ok := scaleTypeAliases[key]; ok {
+		key = alias
+	}
+	for _, st := range scaleTypes {
+		if normaliseTypeName(st.Name) == key {
+			return st, true
+		}
+	}
+	return ScaleType{}, false
+}
+
+// Notes spells the scale from root, one note per interval in the formula.
+func (st ScaleType) Notes(root Note) []Note {
+	notes := make([]Note, len(st.Intervals))
+	for i, iv := range st.Intervals {
+		notes[i] = root.Transpose(iv)
+	}
+	return notes
+}
+
+// BuildScale parses root and scale type names and returns the spelled notes
+// together with a display name such as "E Dorian".
+func BuildScale(root, scaleType string) (name string, notes []Note, err error) {
+	r, err := ParseNote(root)
+	if err != nil {
+		return "", nil, err
+	}
+	st, ok := LookupScaleType(scaleType)
+	if !ok {
+		return "", nil, fmt.Errorf("unknown scale type %q", scaleType)
+	}
+	return r.String() + " " + st.Name, st.Notes(r), nil
+}
+
+// NoteNames converts notes to their string names.
+func NoteNames(notes []Note) []string {
+	out := make([]string, len(notes))
+	for i, n := range notes {
+		out[i] = n.String()
+	}
+	return out
+}
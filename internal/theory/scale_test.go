@@ -0,0 +1,80 @@
+package theory
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestBuildScale checks scales are spelled for their key: one of each
+// letter in seven-note scales, sharps or flats but not both.
+func TestBuildScale(t *testing.T) {
+	tests := []struct {
+		root, scaleType string
+		wantName        string
+		want            []string
+	}{
+		{"C", "major", "C Major", []string{"C", "D", "E", "F", "G", "A", "B"}},
+		{"F#", "major", "F# Major", []string{"F#", "G#", "A#", "B", "C#", "D#", "E#"}},
+		{"Gb", "major", "Gb Major", []string{"Gb", "Ab", "Bb", "Cb", "Db", "Eb", "F"}},
+		{"F", "major", "F Major", []string{"F", "G", "A", "Bb", "C", "D", "E"}},
+		{"D", "natural_minor", "D Minor", []string{"D", "E", "F", "G", "A", "Bb", "C"}},
+		{"E", "dorian", "E Dorian", []string{"E", "F#", "G", "A", "B", "C#", "D"}},
+		{"E", "Phrygian", "E Phrygian", []string{"E", "F", "G", "A", "B", "C", "D"}},
+		{"F", "lydian", "F Lydian", []string{"F", "G", "A", "B", "C", "D", "E"}},
+		{"D", "mixolydian", "D Mixolydian", []string{"D", "E", "F#", "G", "A", "B", "C"}},
+		{"B", "locrian", "B Locrian", []string{"B", "C", "D", "E", "F", "G", "A"}},
+		{"A", "harmonic minor", "A Harmonic Minor", []string{"A", "B", "C", "D", "E", "F", "G#"}},
+		{"C", "jazz minor", "C Melodic Minor", []string{"C", "D", "Eb", "F", "G", "A", "B"}},
+		{"G#", "harmonic minor", "G# Harmonic Minor", []string{"G#", "A#", "B", "C#", "D#", "E", "F##"}},
+		{"A", "minor pentatonic", "A Minor Pentatonic", []string{"A", "C", "D", "E", "G"}},
+		{"G", "pentatonic", "G Major Pentatonic", []string{"G", "A", "B", "D", "E"}},
+		{"A", "blues", "A Blues", []string{"A", "C", "D", "Eb", "E", "G"}},
+		{"C", "major-blues", "C Major Blues", []string{"C", "D", "Eb", "E", "G", "A"}},
+	}
+	for _, tt := range tests {
+		name, notes, err := BuildScale(tt.root, tt.scaleType)
+		if err != nil {
+			t.Errorf("BuildScale(%q, %q): %v", tt.root, tt.scaleType, err)
+			continue
+		}
+		if name != tt.wantName {
+			t.Errorf("BuildScale(%q, %q) name = %q, want %q", tt.root, tt.scaleType, name, tt.wantName)
+		}
+		if got := NoteNames(notes); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("BuildScale(%q, %q) = %v, want %v", tt.root, tt.scaleType, got, tt.want)
+		}
+	}
+}
+
+func TestBuildScaleErrors(t *testing.T) {
+	tests := []struct{ root, scaleType string }{
+		{"H", "major"},
+		{"", "major"},
+		{"C", "bebop"},
+	}
+	for _, tt := range tests {
+		if _, _, err := BuildScale(tt.root, tt.scaleType); err == nil {
+			t.Errorf("BuildScale(%q, %q): no error", tt.root, tt.scaleType)
+		}
+	}
+}
+
+// TestScaleTypesSpelling checks every diatonic scale type uses each letter
+// once from every root a key signature can be written in.
+func TestScaleTypesSpelling(t *testing.T) {
+	roots := []string{"C", "G", "D", "A", "E", "B", "F#", "F", "Bb", "Eb", "Ab", "Db"}
+	for _, st := range ScaleTypes() {
+		if len(st.Intervals) != 7 {
+			continue
+		}
+		for _, r := range roots {
+			letters := map[byte]bool{}
+			for _, n := range st.Notes(MustParseNote(r)) {
+				letters[n.Letter] = true
+			}
+			if len(letters) != 7 {
+				t.Errorf("%s %s repeats a letter: %v", r, st.Name, NoteNames(st.Notes(MustParseNote(r))))
+			}
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/paulgreig/guitar-training/internal/audio"
+	"github.com/paulgreig/guitar-training/internal/obs"
+	"github.com/paulgreig/guitar-training/internal/pitch"
+)
+
+// Reference pitch limits for A4, from baroque pitch to a semitone above concert pitch.
+const (
+	MinA4 = 415.0
+	MaxA4 = 466.0
+)
+
+// audioOpenedMsg carries the audio input opened for a listening screen.
+type audioOpenedMsg struct {
+	gen     int
+	tracker *pitch.Tracker
+	err     error
+}
+
+// pitchFrameMsg carries the pitch heard in the latest frame of audio input.
+type pitchFrameMsg struct {
+	gen int
+	est pitch.Estimate
+	ok  bool
+	err error
+}
+
+// WithAudioInput returns a copy of the model that listens to the audio input
+// described by input: mic, none or wav:<path>.
+func (m Model) WithAudioInput(input string) Model {
+	m.audioIn = input
+	return m
+}
+
+// WithReference returns a copy of the model that names detected notes with
+// A4 at a4 Hz. Values outside MinA4-MaxA4 are logged and ignored.
+func (m Model) WithReference(a4 float64) Model {
+	if a4 < MinA4 || a4 > MaxA4 {
+		obs.Warn("reference pitch %.1f Hz out of range, using %.1f Hz", a4, m.a4)
+		return m
+	}
+	m.a4 = a4
+	return m
+}
+
+// openAudio starts opening the audio input for the current view. Frames
+// from an earlier opening are ignored from here on.
+func (m Model) openAudio() (Model, tea.Cmd) {
+	m.stopListening()
+	m.listenStatus = "Opening audio input…"
+	m.listenHeard, m.listenOK = pitch.Estimate{}, false
+	input, a4, gen := m.audioIn, m.a4, m.listenGen
+	return m, func() tea.Msg {
+		src, err := audio.OpenSource(input)
+		if err != nil {
+			return audioOpenedMsg{gen: gen, err: err}
+		}
+		return audioOpenedMsg{gen: gen, tracker: pitch.NewTracker(src, a4)}
+	}
+}
+
+// pitchFrameCmd detects the pitch of the next frame. It is re-issued after
+// every frame while a listening screen is open.
+func pitchFrameCmd(t *pitch.Tracker, gen int) tea.Cmd {
+	return func() tea.Msg {
+		est, ok, err := t.Next()
+		return pitchFrameMsg{gen: gen, est: est, ok: ok, err: err}
+	}
+}
+
+func (m Model) handleAudioOpened(msg audioOpenedMsg) (Model, tea.Cmd) {
+	if msg.gen != m.listenGen {
+		if msg.tracker != nil {
+			msg.tracker.Source.Close()
+		}
+		return m, nil
+	}
+	if msg.err != nil {
+		obs.Warn("audio input %q unavailable: %v", m.audioIn, msg.err)
+		m.listenStatus = "No audio input"
+		return m.notify("audio-input", severityError,
+			fmt.Sprintf("No audio input: %v (set audio_input to mic or wav:<path>)", msg.err)), nil
+	}
+	m.listener = msg.tracker
+	m.listenStatus = "Listening…"
+	return m.clearNotice("audio-input"), pitchFrameCmd(m.listener, m.listenGen)
+}
+
+// handlePitchFrame passes a frame to the listening screen and asks for the next.
+func (m Model) handlePitchFrame(msg pitchFrameMsg) (Model, tea.Cmd) {
+	if msg.gen != m.listenGen || m.listener == nil {
+		return m, nil
+	}
+	if msg.err != nil {
+		m.listenStatus = "Audio input ended"
+		if !errors.Is(msg.err, io.EOF) {
+			obs.Warn("audio input failed: %v", msg.err)
+			m.listenStatus = "Audio input failed"
+			m = m.notify("audio-input", severityError, fmt.Sprintf("Audio input failed: %v", msg.err))
+		}
+		m.stopListening()
+		return m, nil
+	}
+	if msg.ok {
+		// Name the note here rather than in the tracker so reference pitch
+		// changes apply straight away.
+		msg.est.Pitch, msg.est.Cents = pitch.Nearest(msg.est.Freq, m.a4)
+	}
+	m.listenHeard, m.listenOK = msg.est, msg.ok
+	switch m.view {
+	case "scale-listen":
+		m = m.followRun(msg.est, msg.ok)
+	case "tuner":
+		m = m.updateTuner(msg.est, msg.ok)
+	}
+	return m, pitchFrameCmd(m.listener, m.listenGen)
+}
+
+// stopListening closes the audio input, if one is open, and ignores any
+// frame or opening still in flight.
+func (m *Model) stopListening() {
+	if m.listener != nil {
+		m.listener.Source.Close()
+		m.listener = nil
+	}
+	m.listenGen++
+}
@@ -0,0 +1,259 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/paulgreig/guitar-training/internal/fretboard"
+	"github.com/paulgreig/guitar-training/internal/obs"
+	"github.com/paulgreig/guitar-training/internal/theory"
+)
+
+// generatedVoicingLimit caps how many voicings are generated for a chord.
+const generatedVoicingLimit = 6
+
+// chordNotes spells the chord from its root and quality.
+func chordNotes(c Chord) []theory.Note {
+	root, err := theory.ParseNote(c.Root)
+	if err != nil {
+		return nil
+	}
+	q, ok := theory.LookupChordQuality(c.Quality)
+	if !ok {
+		return nil
+	}
+	return q.Notes(root)
+}
+
+// chordVoicings returns the authored voicings for the tuning, or generated
+// ones when none were written for it. generated reports which it was.
+func chordVoicings(c Chord, tuning fretboard.Tuning) (voicings []fretboard.Voicing, generated bool) {
+	for _, v := range c.Voicings {
+		id := v.Tuning
+		if id == "" {
+			id = fretboard.Standard.ID
+		}
+		if t, ok := fretboard.LookupTuning(id); !ok || t.ID != tuning.ID || len(v.Frets) != len(tuning.Strings) {
+			continue
+		}
+		fingers := v.Fingers
+		if len(fingers) != len(v.Frets) {
+			fingers = make([]int, len(v.Frets))
+		}
+		voicings = append(voicings, fretboard.Voicing{Frets: v.Frets, Fingers: fingers})
+	}
+	if len(voicings) > 0 {
+		return voicings, false
+	}
+	pcs := make([]theory.PitchClass, 0)
+	for _, n := range chordNotes(c) {
+		pcs = append(pcs, n.PitchClass())
+	}
+	return tuning.Voicings(pcs, generatedVoicingLimit), true
+}
+
+// cycleVoicing moves to the next (or previous) voicing of the selected chord.
+func (m *Model) cycleVoicing(forward bool) {
+	voicings, _ := chordVoicings(m.chords[m.selectedIndex], m.tuning)
+	n := len(voicings)
+	if n == 0 {
+		return
+	}
+	if forward {
+		m.voicingIndex = (m.voicingIndex + 1) % n
+	} else {
+		m.voicingIndex = (m.voicingIndex + n - 1) % n
+	}
+	obs.Event("chord_voicing_selected", map[string]interface{}{
+		"index": m.voicingIndex,
+	})
+}
+
+// handleChordsKey builds a progression from the chord list for MIDI export.
+func (m Model) handleChordsKey(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	switch msg.String() {
+	case " ":
+		if m.cursor < len(m.chords) {
+			m.progression = append(m.progression, m.cursor)
+		}
+	case "backspace":
+		if len(m.progression) > 0 {
+			m.progression = m.progression[:len(m.progression)-1]
+		}
+	case "m":
+		m, cmd := m.exportProgression()
+		return m, cmd, true
+	default:
+		return m, nil, false
+	}
+	m.exportStatus = ""
+	return m, nil, true
+}
+
+func (m Model) renderChordsList() string {
+	title := m.styles.Title.Render("Guitar Chords")
+
+	if len(m.chords) == 0 {
+		return title + "\n\nNo chords loaded."
+	}
+
+	var list string
+	for i, chord := range m.chords {
+		if i == m.cursor {
+			list += m.styles.Selected.Render(fmt.Sprintf("> %s", chord.Name)) + "\n"
+		} else {
+			list += m.styles.Menu.Render(fmt.Sprintf("  %s", chord.Name)) + "\n"
+		}
+	}
+
+	var prog string
+	if len(m.progression) > 0 {
+		var names []string
+		for _, idx := range m.progression {
+			if idx < len(m.chords) {
+				names = append(names, m.chords[idx].Name)
+			}
+		}
+		prog = fmt.Sprintf("Progression: %s (%d BPM, %s)", strings.Join(names, " – "),
+			m.metronome.BPM, m.metronome.Signature)
+	}
+	if m.exportStatus != "" {
+		prog += "\n" + m.exportStatus
+	}
+
+	help := m.styles.Text.Render("\nUse ↑/↓ to navigate, Enter to view details, Esc to go back\n" +
+		"Space adds a chord to the progression, Backspace removes the last, m exports it as MIDI")
+
+	return lipgloss.JoinVertical(lipgloss.Left, title, list, m.styles.Text.Render(prog), help)
+}
+
+func (m Model) renderChordDetail() string {
+	if m.selectedIndex >= len(m.chords) {
+		return "Chord not found"
+	}
+
+	chord := m.chords[m.selectedIndex]
+	title := m.styles.Title.Render(chord.Name)
+
+	quality := chord.Quality
+	if q, ok := theory.LookupChordQuality(chord.Quality); ok {
+		quality = q.Name
+	}
+	info := fmt.Sprintf("Quality: %s\nNotes: %v\n\n", quality, theory.NoteNames(chordNotes(chord)))
+
+	voicings, generated := chordVoicings(chord, m.tuning)
+	var diagram string
+	if len(voicings) == 0 {
+		diagram = fmt.Sprintf("No playable voicing found for %s tuning.", m.tuning.Name)
+	} else {
+		idx := m.voicingIndex % len(voicings)
+		source := "authored"
+		if generated {
+			source = "generated"
+		}
+		info += fmt.Sprintf("Voicing %d/%d (%s, %s): %s\n\n",
+			idx+1, len(voicings), source, m.tuning.Name, voicingString(voicings[idx]))
+		diagram = renderChordDiagram(voicings[idx], m.tuning)
+	}
+
+	content := m.styles.Text.Render(info + diagram)
+	help := m.styles.Text.Render("\nPress v/V to cycle voicings, Esc to go back")
+
+	return lipgloss.JoinVertical(lipgloss.Left, title, content, help)
+}
+
+// voicingString renders frets compactly, low string first, e.g. "x32010".
+// Frets above 9 are separated with dashes ("x-10-12-12-12-10").
+func voicingString(v fretboard.Voicing) string {
+	parts := make([]string, len(v.Frets))
+	wide := false
+	for i, f := range v.Frets {
+		if f == fretboard.Muted {
+			parts[i] = "x"
+		} else {
+			parts[i] = fmt.Sprint(f)
+			wide = wide || f > 9
+		}
+	}
+	if wide {
+		return strings.Join(parts, "-")
+	}
+	return strings.Join(parts, "")
+}
+
+// renderChordDiagram draws a vertical chord box, strings left to right from
+// lowest to highest and frets top to bottom, e.g.
+//
+//	  E A D G B e
+//	  x       o
+//	  ===========
+//	1 | | | | 1 |
+//	  -----------
+//	2 | | 2 | | |
+func renderChordDiagram(v fretboard.Voicing, tuning fretboard.Tuning) string {
+	labels := tuning.StringLabels()
+	w := 2
+	for _, l := range labels {
+		if len(l)+1 > w {
+			w = len(l) + 1
+		}
+	}
+	cell := func(s string) string { return fmt.Sprintf("%-*s", w, s) }
+	width := w*(len(labels)-1) + 1
+
+	start, top := 1, 0
+	for _, f := range v.Frets {
+		if f > top {
+			top = f
+		}
+	}
+	if top > 4 {
+		start = v.BaseFret()
+	}
+	rows := top - start + 1
+	if rows < 4 {
+		rows = 4
+	}
+
+	var b strings.Builder
+	b.WriteString("    ")
+	for _, l := range labels {
+		b.WriteString(cell(l))
+	}
+	b.WriteString("\n    ")
+	for _, f := range v.Frets {
+		switch f {
+		case fretboard.Muted:
+			b.WriteString(cell("x"))
+		case 0:
+			b.WriteString(cell("o"))
+		default:
+			b.WriteString(cell(""))
+		}
+	}
+	b.WriteString("\n    ")
+	if start == 1 {
+		b.WriteString(strings.Repeat("=", width))
+	} else {
+		b.WriteString(strings.Repeat("-", width))
+	}
+	b.WriteString("\n")
+
+	for r := start; r < start+rows; r++ {
+		b.WriteString(fmt.Sprintf("%3d ", r))
+		for s, f := range v.Frets {
+			mark := "|"
+			if f == r {
+				mark = "●"
+				if s < len(v.Fingers) && v.Fingers[s] > 0 {
+					mark = fmt.Sprint(v.Fingers[s])
+				}
+			}
+			b.WriteString(cell(mark))
+		}
+		b.WriteString("\n    " + strings.Repeat("-", width) + "\n")
+	}
+	return b.String()
+}
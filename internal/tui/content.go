@@ -0,0 +1,90 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/paulgreig/guitar-training/data"
+)
+
+// source is one layer of content: the built-in files or a user directory
+// laid out the same way (scales.json, lessons.json, chords.json, lessons/).
+type source struct {
+	name string // directory, or "built-in"
+	fsys fs.FS
+}
+
+// contentSources returns the built-in content followed by dirs. Each layer's
+// entries replace those of the layers before it with the same name or ID,
+// and new entries are added after them.
+func contentSources(dirs []string) []source {
+	sources := []source{{name: "built-in", fsys: data.FS}}
+	for _, dir := range dirs {
+		sources = append(sources, source{name: dir, fsys: os.DirFS(dir)})
+	}
+	return sources
+}
+
+// LoadLessons returns the built-in lessons overlaid with those in dirs, for
+// commands that work with lessons outside the TUI.
+func LoadLessons(dirs []string) ([]Lesson, error) {
+	return loadLessonsFrom(contentSources(dirs))
+}
+
+// path names file within the source for messages.
+func (s source) path(file string) string {
+	return filepath.Join(s.name, filepath.FromSlash(file))
+}
+
+// readFile returns the contents of file. found is false when the source has
+// no such file, which is not an error.
+func (s source) readFile(file string) (data []byte, found bool, err error) {
+	data, err = fs.ReadFile(s.fsys, file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("could not read %s: %w", s.path(file), err)
+	}
+	return data, true, nil
+}
+
+// fileError is a content file that couldn't be read or parsed at all, so
+// none of its entries loaded.
+type fileError struct{ error }
+
+func (e fileError) Unwrap() error { return e.error }
+
+// fileFailed reports whether a load error includes a whole file failing.
+func fileFailed(err error) bool {
+	var fe fileError
+	return errors.As(err, &fe)
+}
+
+// problemErrors returns problems as errors, for joining into a load error.
+// Problems with a file as a whole are fileErrors.
+func problemErrors(problems []Problem) []error {
+	errs := make([]error, len(problems))
+	for i, p := range problems {
+		errs[i] = p
+		if p.Field == "" {
+			errs[i] = fileError{p}
+		}
+	}
+	return errs
+}
+
+// overlay adds item to list, replacing the entry with the same key.
+func overlay[T any](list []T, item T, key func(T) string) []T {
+	k := key(item)
+	for i := range list {
+		if key(list[i]) == k {
+			list[i] = item
+			return list
+		}
+	}
+	return append(list, item)
+}
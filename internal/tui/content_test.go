@@ -0,0 +1,190 @@
+package tui
+
+import (
+	"io/fs"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// withUser returns the built-in content with fsys layered over it.
+func withUser(fsys fstest.MapFS) []source {
+	return append(contentSources(nil), source{name: "user", fsys: fsys})
+}
+
+func TestOverlay(t *testing.T) {
+	key := func(s string) string { return strings.ToLower(s) }
+	list := overlay([]string{"a", "b", "c"}, "B", key)
+	list = overlay(list, "d", key)
+	if want := []string{"a", "B", "c", "d"}; !reflect.DeepEqual(list, want) {
+		t.Errorf("overlay = %v, want %v", list, want)
+	}
+}
+
+func TestContentSources(t *testing.T) {
+	sources := contentSources([]string{"team", "mine"})
+	var names []string
+	for _, s := range sources {
+		names = append(names, s.name)
+	}
+	if want := []string{"built-in", "team", "mine"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("sources = %v, want %v", names, want)
+	}
+	// Every built-in file loads without problems.
+	if _, err := loadScalesFrom(sources[:1]); err != nil {
+		t.Errorf("built-in scales: %v", err)
+	}
+	if _, err := loadLessonsFrom(sources[:1]); err != nil {
+		t.Errorf("built-in lessons: %v", err)
+	}
+	if _, err := loadChordsFrom(sources[:1]); err != nil {
+		t.Errorf("built-in chords: %v", err)
+	}
+}
+
+// TestOverlayMissingFiles checks a directory without some (or any) content
+// files leaves the built-in content as it is.
+func TestOverlayMissingFiles(t *testing.T) {
+	builtin := contentSources(nil)
+	scales, _ := loadScalesFrom(builtin)
+	lessons, _ := loadLessonsFrom(builtin)
+	chords, _ := loadChordsFrom(builtin)
+	for _, fsys := range []fstest.MapFS{
+		{},
+		{"notes.txt": {Data: []byte("not content")}},
+		{"lessons": {Mode: fs.ModeDir | 0o755}},
+	} {
+		sources := withUser(fsys)
+		got, err := loadScalesFrom(sources)
+		if err != nil || !reflect.DeepEqual(got, scales) {
+			t.Errorf("%v: scales changed (%v)", fsys, err)
+		}
+		gotLessons, err := loadLessonsFrom(sources)
+		if err != nil || !reflect.DeepEqual(gotLessons, lessons) {
+			t.Errorf("%v: lessons changed (%v)", fsys, err)
+		}
+		gotChords, err := loadChordsFrom(sources)
+		if err != nil || !reflect.DeepEqual(gotChords, chords) {
+			t.Errorf("%v: chords changed (%v)", fsys, err)
+		}
+	}
+}
+
+func TestOverlayScales(t *testing.T) {
+	builtin, _ := loadScalesFrom(contentSources(nil))
+	user := fstest.MapFS{"scales.json": {Data: []byte(`[
+  {"name": "C Major", "root": "C", "type": "major pentatonic"},
+  {"root": "E", "type": "phrygian"}
+]`)}}
+	scales, err := loadScalesFrom(withUser(user))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scales) != len(builtin)+1 {
+		t.Fatalf("got %d scales, want %d", len(scales), len(builtin)+1)
+	}
+	i := slices.IndexFunc(scales, func(s Scale) bool { return s.Name == "C Major" })
+	if i != slices.IndexFunc(builtin, func(s Scale) bool { return s.Name == "C Major" }) {
+		t.Errorf("C Major moved to %d", i)
+	}
+	if want := []string{"C", "D", "E", "G", "A"}; !reflect.DeepEqual(scales[i].Notes, want) {
+		t.Errorf("C Major notes = %v, want the user's %v", scales[i].Notes, want)
+	}
+	if last := scales[len(scales)-1]; last.Name != "E Phrygian" {
+		t.Errorf("last scale is %q, want E Phrygian", last.Name)
+	}
+}
+
+func TestOverlayLessons(t *testing.T) {
+	builtin, _ := loadLessonsFrom(contentSources(nil))
+	user := fstest.MapFS{
+		"lessons.json": {Data: []byte(`[
+  {"id": "lesson-001", "title": "Our first lesson", "level": "beginner", "content": "Ours."},
+  {"id": "team-001", "title": "Team lesson", "level": "advanced", "content": "New."}
+]`)},
+		"lessons/minor-pentatonic-first-box.md": {Data: []byte("# Our box\n\nOurs too.\n")},
+		"lessons/bends.md":                      {Data: []byte("---\nlevel: intermediate\n---\n# Bends\n\nPush.\n")},
+	}
+	lessons, err := loadLessonsFrom(withUser(user))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lessons) != len(builtin)+2 {
+		t.Fatalf("got %d lessons, want %d", len(lessons), len(builtin)+2)
+	}
+	for i, l := range builtin {
+		got := lessons[i]
+		if got.ID != l.ID {
+			t.Errorf("lesson %d is %s, want %s in its built-in place", i, got.ID, l.ID)
+		}
+		switch l.ID {
+		case "lesson-001":
+			if got.Title != "Our first lesson" || got.Content != "Ours." {
+				t.Errorf("lesson-001 = %q %q, want the user's", got.Title, got.Content)
+			}
+		case "minor-pentatonic-first-box":
+			// A replacement keeps none of the built-in lesson's fields.
+			if got.Title != "Our box" || got.Tags != nil || got.Prerequisites != nil {
+				t.Errorf("%s = %+v, want the user's", l.ID, got)
+			}
+		default:
+			if !reflect.DeepEqual(got, l) {
+				t.Errorf("%s changed", l.ID)
+			}
+		}
+	}
+	// Lessons are added in the order they're read: lessons.json, then the
+	// Markdown files by name.
+	var added []string
+	for _, l := range lessons[len(builtin):] {
+		added = append(added, l.ID+" "+l.Level)
+	}
+	if want := []string{"team-001 advanced", "bends intermediate"}; !reflect.DeepEqual(added, want) {
+		t.Errorf("added %v, want %v", added, want)
+	}
+}
+
+func TestOverlayChords(t *testing.T) {
+	builtin, _ := loadChordsFrom(contentSources(nil))
+	user := fstest.MapFS{"chords.json": {Data: []byte(`[
+  {"name": "C", "root": "C", "quality": "major", "voicings": [{"frets": [-1, 3, 2, 0, 1, 3]}]},
+  {"root": "F#", "quality": "minor"}
+]`)}}
+	chords, err := loadChordsFrom(withUser(user))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chords) != len(builtin)+1 {
+		t.Fatalf("got %d chords, want %d", len(chords), len(builtin)+1)
+	}
+	i := slices.IndexFunc(chords, func(c Chord) bool { return c.Name == "C" })
+	if len(chords[i].Voicings) != 1 || chords[i].Voicings[0].Frets[5] != 3 {
+		t.Errorf("C voicings = %+v, want the user's one", chords[i].Voicings)
+	}
+	if last := chords[len(chords)-1]; last.Name != "F#m" {
+		t.Errorf("last chord is %q, want F#m", last.Name)
+	}
+}
+
+// TestOverlayLayers checks later directories win over earlier ones, and a
+// bad file in one leaves the others' entries loaded.
+func TestOverlayLayers(t *testing.T) {
+	team := fstest.MapFS{"scales.json": {Data: []byte(`[{"name": "Team", "notes": ["C", "E", "G"]}, {"name": "Shared", "notes": ["C"]}]`)}}
+	mine := fstest.MapFS{"scales.json": {Data: []byte(`[{"name": "Shared", "notes": ["D"]}]`)}}
+	broken := fstest.MapFS{"scales.json": {Data: []byte(`[{"name": `)}}
+	sources := append(withUser(team), source{name: "mine", fsys: mine}, source{name: "broken", fsys: broken})
+
+	scales, err := loadScalesFrom(sources)
+	if err == nil || !fileFailed(err) || !strings.Contains(err.Error(), "broken") {
+		t.Errorf("error = %v, want broken/scales.json to fail", err)
+	}
+	byName := map[string][]string{}
+	for _, s := range scales {
+		byName[s.Name] = s.Notes
+	}
+	if !reflect.DeepEqual(byName["Shared"], []string{"D"}) || byName["Team"] == nil || byName["C Major"] == nil {
+		t.Errorf("loaded %v", byName)
+	}
+}
@@ -1,49 +1,96 @@
 package tui
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
+	"io/fs"
+	"path"
+	"slices"
+	"sort"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/paulgreig/guitar-training/internal/markdown"
 	"github.com/paulgreig/guitar-training/internal/obs"
+	"github.com/paulgreig/guitar-training/internal/theory"
 )
 
+// Scale is a named set of notes. Entries may list Notes explicitly or give
+// only Root and Type, in which case the notes are derived from theory.
 type Scale struct {
-	Name      string    `json:"name"`
-	Notes     []string  `json:"notes"`
-	Positions []Position `json:"positions"`
+	Name      string     `json:"name"`
+	Root      string     `json:"root,omitempty"`
+	Type      string     `json:"type,omitempty"`
+	Notes     []string   `json:"notes"`
+	Positions []Position `json:"positions,omitempty"`
 }
 
+// Position is a hand-authored fretboard marking: a fret and the strings (0 =
+// lowest) to mark at it. When a scale lists any positions they replace the
+// computed layout.
 type Position struct {
 	Fret    int   `json:"fret"`
 	Strings []int `json:"strings"`
 }
 
+// Lesson is a lesson whose Content is Markdown. Lessons come from
+// lessons.json in the data directory or from Markdown files in its lessons
+// directory, whose front-matter gives the other fields.
 type Lesson struct {
-	ID      string `json:"id"`
-	Title   string `json:"title"`
-	Level   string `json:"level"`
-	Content string `json:"content"`
+	ID            string   `json:"id"`
+	Title         string   `json:"title"`
+	Level         string   `json:"level"`
+	Tags          []string `json:"tags,omitempty"`
+	Prerequisites []string `json:"prerequisites,omitempty"` // lesson IDs
+	Content       string   `json:"content"`
 }
 
+// Chord is a named chord with optional hand-authored voicings. Voicings for
+// tunings without authored entries are generated from the chord's notes.
+type Chord struct {
+	Name     string    `json:"name"`
+	Root     string    `json:"root"`
+	Quality  string    `json:"quality"`
+	Voicings []Voicing `json:"voicings,omitempty"`
+}
+
+// Voicing is one chord shape: a fret per string from lowest to highest (-1
+// for a muted string) and optional fingers (0 open/muted, 1-4). Tuning is
+// the tuning ID it was written for and defaults to standard.
+type Voicing struct {
+	Frets   []int  `json:"frets"`
+	Fingers []int  `json:"fingers,omitempty"`
+	Tuning  string `json:"tuning,omitempty"`
+}
+
+// ScalesLoadedMsg carries the scales that loaded and, in Err, the problems
+// with any that didn't.
 type ScalesLoadedMsg struct {
 	Scales []Scale
+	Err    error
 }
 
+// LessonsLoadedMsg carries the lessons that loaded and any problems.
 type LessonsLoadedMsg struct {
 	Lessons []Lesson
+	Err     error
 }
 
-func loadScales() tea.Cmd {
+// ChordsLoadedMsg carries the chords that loaded and any problems.
+type ChordsLoadedMsg struct {
+	Chords []Chord
+	Err    error
+}
+
+func loadScales(dirs []string) tea.Cmd {
 	return func() tea.Msg {
-		scales, err := loadScalesFromFile()
+		scales, err := loadScalesFrom(contentSources(dirs))
 		if err != nil {
-			// Observability: log the error and record metrics, but keep the app usable.
+			// Observability: log the error and record metrics, but keep the app
+			// usable with whatever did load.
 			obs.Error("failed to load scales: %v", err)
 			obs.RecordDataLoadError()
-			return ScalesLoadedMsg{Scales: []Scale{}}
+			return ScalesLoadedMsg{Scales: scales, Err: err}
 		}
 		obs.Info("loaded scales successfully count=%d", len(scales))
 		obs.RecordDataLoadSuccess()
@@ -51,14 +98,15 @@ func loadScales() tea.Cmd {
 	}
 }
 
-func loadLessons() tea.Cmd {
+func loadLessons(dirs []string) tea.Cmd {
 	return func() tea.Msg {
-		lessons, err := loadLessonsFromFile()
+		lessons, err := loadLessonsFrom(contentSources(dirs))
 		if err != nil {
-			// Observability: log the error and record metrics, but keep the app usable.
+			// Observability: log the error and record metrics, but keep the app
+			// usable with whatever did load.
 			obs.Error("failed to load lessons: %v", err)
 			obs.RecordDataLoadError()
-			return LessonsLoadedMsg{Lessons: []Lesson{}}
+			return LessonsLoadedMsg{Lessons: lessons, Err: err}
 		}
 		obs.Info("loaded lessons successfully count=%d", len(lessons))
 		obs.RecordDataLoadSuccess()
@@ -66,36 +114,224 @@ func loadLessons() tea.Cmd {
 	}
 }
 
-func loadScalesFromFile() ([]Scale, error) {
-	// Try to load from data/scales.json
-	dataPath := filepath.Join("data", "scales.json")
-	
-	data, err := os.ReadFile(dataPath)
+func loadChords(dirs []string) tea.Cmd {
+	return func() tea.Msg {
+		chords, err := loadChordsFrom(contentSources(dirs))
+		if err != nil {
+			// Observability: log the error and record metrics, but keep the app
+			// usable with whatever did load.
+			obs.Error("failed to load chords: %v", err)
+			obs.RecordDataLoadError()
+			return ChordsLoadedMsg{Chords: chords, Err: err}
+		}
+		obs.Info("loaded chords successfully count=%d", len(chords))
+		obs.RecordDataLoadSuccess()
+		return ChordsLoadedMsg{Chords: chords}
+	}
+}
+
+// loadScalesFrom reads scales.json from each source in turn. Scales that
+// fail their checks are skipped and reported in the error, as are files
+// that can't be read.
+func loadScalesFrom(sources []source) ([]Scale, error) {
+	scales := []Scale{}
+	var errs []error
+	for _, src := range sources {
+		data, found, err := src.readFile("scales.json")
+		if err != nil {
+			errs = append(errs, fileError{fmt.Errorf("could not load scales: %w", err)})
+		}
+		if !found {
+			continue
+		}
+		more, problems := checkScales(src.path("scales.json"), data)
+		errs = append(errs, problemErrors(problems)...)
+		for _, s := range more {
+			scales = overlay(scales, s, func(s Scale) string { return s.Name })
+		}
+	}
+	return scales, errors.Join(errs...)
+}
+
+// resolveScale fills in Notes (and Name, if empty) for entries that only
+// specify a root and scale type. Entries that give notes as well as a root
+// and type must list the notes of that scale, in any order or spelling.
+func resolveScale(s *Scale) error {
+	if s.Root == "" && s.Type == "" {
+		return nil
+	}
+	if s.Root == "" || s.Type == "" {
+		return fmt.Errorf("both root and type are required (root=%q type=%q)", s.Root, s.Type)
+	}
+	name, notes, err := theory.BuildScale(s.Root, s.Type)
 	if err != nil {
-		return nil, fmt.Errorf("could not read scales file: %w", err)
+		return err
+	}
+	if len(s.Notes) == 0 {
+		s.Notes = theory.NoteNames(notes)
+	} else if !sameNotes(s.Notes, notes) {
+		return notesMismatchError{s.Notes, s.Root, s.Type, theory.NoteNames(notes)}
 	}
-	
-	var scales []Scale
-	if err := json.Unmarshal(data, &scales); err != nil {
-		return nil, fmt.Errorf("could not parse scales: %w", err)
+	if s.Name == "" {
+		s.Name = name
 	}
-	
-	return scales, nil
+	return nil
+}
+
+// notesMismatchError is a scale whose notes aren't those of its root and type.
+type notesMismatchError struct {
+	notes      []string
+	root, kind string
+	want       []string
 }
 
-func loadLessonsFromFile() ([]Lesson, error) {
-	// Try to load from data/lessons.json
-	dataPath := filepath.Join("data", "lessons.json")
-	
-	data, err := os.ReadFile(dataPath)
+func (e notesMismatchError) Error() string {
+	return fmt.Sprintf("%s doesn't match %s %s (%s)",
+		strings.Join(e.notes, " "), e.root, e.kind, strings.Join(e.want, " "))
+}
+
+// sameNotes reports whether names are the pitch classes of notes. A name
+// that isn't a note never matches.
+func sameNotes(names []string, notes []theory.Note) bool {
+	var have, want []theory.PitchClass
+	for _, name := range names {
+		n, err := theory.ParseNote(name)
+		if err != nil {
+			return false
+		}
+		have = append(have, n.PitchClass())
+	}
+	for _, n := range notes {
+		want = append(want, n.PitchClass())
+	}
+	for _, pc := range have {
+		if !slices.Contains(want, pc) {
+			return false
+		}
+	}
+	for _, pc := range want {
+		if !slices.Contains(have, pc) {
+			return false
+		}
+	}
+	return true
+}
+
+// loadLessonsFrom reads lessons.json and the Markdown lessons in lessons/
+// from each source in turn. Lessons that fail their checks are skipped and
+// reported in the error, as are files that can't be read.
+func loadLessonsFrom(sources []source) ([]Lesson, error) {
+	lessons := []Lesson{}
+	var errs []error
+	add := func(l Lesson) {
+		lessons = overlay(lessons, l, func(l Lesson) string { return l.ID })
+	}
+	for _, src := range sources {
+		data, found, err := src.readFile("lessons.json")
+		if err != nil {
+			errs = append(errs, fileError{fmt.Errorf("could not load lessons: %w", err)})
+		}
+		if found {
+			more, problems := checkLessons(src.path("lessons.json"), data, nil)
+			errs = append(errs, problemErrors(problems)...)
+			for _, l := range more {
+				add(l)
+			}
+		}
+
+		files, err := fs.Glob(src.fsys, "lessons/*.md")
+		if err != nil {
+			errs = append(errs, fileError{fmt.Errorf("could not list lesson files: %w", err)})
+			continue
+		}
+		sort.Strings(files)
+		for _, file := range files {
+			data, _, err := src.readFile(file)
+			if err != nil {
+				// One bad file shouldn't hide every other lesson.
+				errs = append(errs, fileError{fmt.Errorf("could not load lesson: %w", err)})
+				continue
+			}
+			l, ok, problems := checkLessonFile(src.path(file), data, nil)
+			errs = append(errs, problemErrors(problems)...)
+			if ok {
+				add(l)
+			}
+		}
+	}
+	return lessons, errors.Join(errs...)
+}
+
+// parseLessonFile reads a Markdown lesson. The ID defaults to the file name
+// and the title to the first heading.
+func parseLessonFile(file, doc string) (Lesson, markdown.FrontMatter, error) {
+	fm, body, err := markdown.SplitFrontMatter(doc)
 	if err != nil {
-		return nil, fmt.Errorf("could not read lessons file: %w", err)
+		return Lesson{}, nil, err
+	}
+	l := Lesson{
+		ID:            fm.Get("id"),
+		Title:         fm.Get("title"),
+		Level:         fm.Get("level"),
+		Tags:          fm.List("tags"),
+		Prerequisites: fm.List("prerequisites"),
+		Content:       body,
+	}
+	if l.ID == "" {
+		l.ID = strings.TrimSuffix(path.Base(file), path.Ext(file))
+	}
+	if l.Title == "" {
+		for _, line := range strings.Split(body, "\n") {
+			if strings.HasPrefix(line, "# ") {
+				l.Title = strings.TrimSpace(line[2:])
+				break
+			}
+		}
+	}
+	if l.Title == "" {
+		l.Title = l.ID
+	}
+	if l.Level == "" {
+		l.Level = "beginner"
+	}
+	return l, fm, nil
+}
+
+// loadChordsFrom reads chords.json from each source in turn. Chords that
+// fail their checks are skipped and reported in the error, as are files
+// that can't be read.
+func loadChordsFrom(sources []source) ([]Chord, error) {
+	chords := []Chord{}
+	var errs []error
+	for _, src := range sources {
+		data, found, err := src.readFile("chords.json")
+		if err != nil {
+			errs = append(errs, fileError{fmt.Errorf("could not load chords: %w", err)})
+		}
+		if !found {
+			continue
+		}
+		more, problems := checkChords(src.path("chords.json"), data)
+		errs = append(errs, problemErrors(problems)...)
+		for _, c := range more {
+			chords = overlay(chords, c, func(c Chord) string { return c.Name })
+		}
+	}
+	return chords, errors.Join(errs...)
+}
+
+// resolveChord checks the root and quality and fills in a default name such as "Am7".
+func resolveChord(c *Chord) error {
+	root, err := theory.ParseNote(c.Root)
+	if err != nil {
+		return err
+	}
+	q, ok := theory.LookupChordQuality(c.Quality)
+	if !ok {
+		return fmt.Errorf("unknown chord quality %q", c.Quality)
 	}
-	
-	var lessons []Lesson
-	if err := json.Unmarshal(data, &lessons); err != nil {
-		return nil, fmt.Errorf("could not parse lessons: %w", err)
+	if c.Name == "" {
+		c.Name = root.String() + q.Symbol
 	}
-	
-	return lessons, nil
+	return nil
 }
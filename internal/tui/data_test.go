@@ -0,0 +1,47 @@
+package tui
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestResolveScale(t *testing.T) {
+	tests := []struct {
+		name      string
+		scale     Scale
+		wantName  string
+		wantNotes []string
+		mismatch  bool
+		wantErr   bool
+	}{
+		{"notes only", Scale{Name: "Mine", Notes: []string{"C", "E", "G"}}, "Mine", []string{"C", "E", "G"}, false, false},
+		{"root and type", Scale{Root: "A", Type: "minor pentatonic"}, "A Minor Pentatonic", []string{"A", "C", "D", "E", "G"}, false, false},
+		{"keeps name", Scale{Name: "Blues box", Root: "A", Type: "minor pentatonic"}, "Blues box", []string{"A", "C", "D", "E", "G"}, false, false},
+		{"matching notes", Scale{Root: "A", Type: "minor pentatonic", Notes: []string{"G", "E", "D", "C", "A"}}, "A Minor Pentatonic", []string{"G", "E", "D", "C", "A"}, false, false},
+		{"enharmonic notes", Scale{Root: "A", Type: "minor pentatonic", Notes: []string{"A", "B#", "D", "E", "G"}}, "A Minor Pentatonic", []string{"A", "B#", "D", "E", "G"}, false, false},
+		{"wrong note", Scale{Root: "C", Type: "major", Notes: []string{"C", "D", "Eb", "F", "G", "A", "B"}}, "", nil, true, true},
+		{"missing note", Scale{Root: "C", Type: "major", Notes: []string{"C", "D", "E"}}, "", nil, true, true},
+		{"not a note", Scale{Root: "C", Type: "major", Notes: []string{"C", "D", "H"}}, "", nil, true, true},
+		{"root without type", Scale{Root: "C"}, "", nil, false, true},
+		{"unknown type", Scale{Root: "C", Type: "zorblax"}, "", nil, false, true},
+	}
+	for _, tt := range tests {
+		s := tt.scale
+		err := resolveScale(&s)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: error %v, want error %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		var mismatch notesMismatchError
+		if errors.As(err, &mismatch) != tt.mismatch {
+			t.Errorf("%s: error %v, want a mismatch %v", tt.name, err, tt.mismatch)
+		}
+		if err != nil {
+			continue
+		}
+		if s.Name != tt.wantName || !slices.Equal(s.Notes, tt.wantNotes) {
+			t.Errorf("%s: got %q %v, want %q %v", tt.name, s.Name, s.Notes, tt.wantName, tt.wantNotes)
+		}
+	}
+}
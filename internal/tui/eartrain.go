@@ -0,0 +1,149 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/paulgreig/guitar-training/internal/audio"
+	"github.com/paulgreig/guitar-training/internal/eartrain"
+	"github.com/paulgreig/guitar-training/internal/obs"
+	"github.com/paulgreig/guitar-training/internal/practice"
+)
+
+// audioPlayedMsg reports the result of sending synthesised audio to the sink.
+type audioPlayedMsg struct {
+	err error
+}
+
+// WithSink returns a copy of the model that plays synthesised audio to s.
+func (m Model) WithSink(s audio.Sink) Model {
+	m.sink = s
+	return m
+}
+
+// startEarTraining opens ear training and plays the first question.
+func (m Model) startEarTraining() (Model, tea.Cmd) {
+	if m.ear == nil {
+		m.ear = eartrain.NewGenerator(time.Now().UnixNano())
+	}
+	m.earAsked, m.earCorrect = 0, 0
+	m.earStart = time.Now()
+	m.view = "ear"
+	return m.nextEarQuestion()
+}
+
+func (m Model) nextEarQuestion() (Model, tea.Cmd) {
+	m.earQ = m.ear.Next(eartrain.Categories[m.earCategory])
+	m.earAnswer = -1
+	m.cursor = 0
+	return m, playQuestionCmd(m.sink, m.earQ)
+}
+
+// playQuestionCmd renders the question and writes it to the sink off the UI goroutine.
+func playQuestionCmd(sink audio.Sink, q eartrain.Question) tea.Cmd {
+	return func() tea.Msg {
+		return audioPlayedMsg{err: sink.Write(eartrain.Render(q, sink.SampleRate()))}
+	}
+}
+
+// handleEarKey handles answering, replaying and switching categories. Up and
+// down fall through to the global handler, which moves the cursor over the choices.
+func (m Model) handleEarKey(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	key := msg.String()
+	switch key {
+	case " ", "r":
+		return m, playQuestionCmd(m.sink, m.earQ), true
+	case "tab":
+		m.earCategory = (m.earCategory + 1) % len(eartrain.Categories)
+		m, cmd := m.nextEarQuestion()
+		return m, cmd, true
+	case "n":
+		m, cmd := m.nextEarQuestion()
+		return m, cmd, true
+	case "enter":
+		if m.earAnswer >= 0 {
+			m, cmd := m.nextEarQuestion()
+			return m, cmd, true
+		}
+		return m.answerEar(m.cursor), nil, true
+	case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		choice := int(key[0] - '1')
+		if m.earAnswer >= 0 || choice >= len(m.earQ.Choices) {
+			return m, nil, true
+		}
+		m.cursor = choice
+		return m.answerEar(choice), nil, true
+	case "esc":
+		obs.Event("ear_training_finished", map[string]interface{}{
+			"asked":   m.earAsked,
+			"correct": m.earCorrect,
+		})
+		m.cursor = 0
+		return m.finishSession(practice.Session{
+			Kind:    practice.KindQuiz,
+			Item:    "ear",
+			Title:   "Ear training",
+			Start:   m.earStart,
+			Seconds: time.Since(m.earStart).Seconds(),
+		}, "menu"), nil, true
+	}
+	return m, nil, false
+}
+
+func (m Model) answerEar(choice int) Model {
+	correct := m.earQ.Correct(choice)
+	m.earAnswer = choice
+	m.earAsked++
+	if correct {
+		m.earCorrect++
+	}
+	obs.Event("ear_question_answered", map[string]interface{}{
+		"category": m.earQ.Category.String(),
+		"answer":   m.earQ.Answer,
+		"chosen":   m.earQ.Choices[choice],
+		"correct":  correct,
+	})
+	return m
+}
+
+func (m Model) renderEarTraining() string {
+	title := m.styles.Title.Render("Ear training – " + m.earQ.Category.String())
+	prompt := m.styles.Text.Render("What did you hear?\n")
+
+	var list string
+	for i, c := range m.earQ.Choices {
+		line := fmt.Sprintf("%d. %s", i+1, c)
+		if m.earAnswer >= 0 {
+			switch {
+			case c == m.earQ.Answer:
+				line += "  ✓"
+			case i == m.earAnswer:
+				line += "  ✗"
+			}
+		}
+		if i == m.cursor {
+			list += m.styles.Selected.Render("> "+line) + "\n"
+		} else {
+			list += m.styles.Menu.Render("  "+line) + "\n"
+		}
+	}
+
+	status := fmt.Sprintf("Score: %d/%d this session", m.earCorrect, m.earAsked)
+	if m.earAnswer >= 0 {
+		if m.earQ.Correct(m.earAnswer) {
+			status = "Correct! " + status
+		} else {
+			status = fmt.Sprintf("It was %s. %s", m.earQ.Answer, status)
+		}
+	}
+
+	help := "\nPress 1-4 or ↑/↓ and Enter to answer, Space to replay, Tab for the next category, Esc to finish"
+	if m.earAnswer >= 0 {
+		help = "\nPress Enter for the next question, Space to replay, Tab for the next category, Esc to finish"
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, title, prompt, list,
+		m.styles.Text.Render(status), m.styles.Text.Render(help))
+}
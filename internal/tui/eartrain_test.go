@@ -0,0 +1,89 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/paulgreig/guitar-training/internal/config"
+	"github.com/paulgreig/guitar-training/internal/eartrain"
+)
+
+// recordingSink keeps what the model plays instead of sounding it.
+type recordingSink struct {
+	writes int
+}
+
+func (s *recordingSink) Write(samples []float64) error {
+	if len(samples) > 0 {
+		s.writes++
+	}
+	return nil
+}
+
+func (s *recordingSink) SampleRate() int { return 8000 }
+func (s *recordingSink) Close() error    { return nil }
+
+// play runs cmd as the program would and checks it reported playing.
+func play(t *testing.T, cmd tea.Cmd) {
+	t.Helper()
+	if cmd == nil {
+		t.Fatal("no command to play the question")
+	}
+	msg, ok := cmd().(audioPlayedMsg)
+	if !ok || msg.err != nil {
+		t.Fatalf("play returned %#v", msg)
+	}
+}
+
+func TestEarTraining(t *testing.T) {
+	sink := &recordingSink{}
+	m := NewModel(config.Default()).WithSink(sink)
+	m.ear = eartrain.NewGenerator(7)
+	m, cmd := m.startEarTraining()
+	play(t, cmd)
+
+	right := -1
+	for i := range m.earQ.Choices {
+		if m.earQ.Correct(i) {
+			right = i
+		}
+	}
+	wrong := (right + 1) % len(m.earQ.Choices)
+	steps := []struct {
+		key            string
+		plays          bool
+		asked, correct int
+		answer         int
+	}{
+		{"r", true, 0, 0, -1},
+		{string(rune('1' + right)), false, 1, 1, right},
+		{string(rune('1' + wrong)), false, 1, 1, right}, // already answered
+		{"enter", true, 1, 1, -1},
+		{"9", false, 1, 1, -1}, // no ninth choice
+	}
+	for _, st := range steps {
+		var handled bool
+		m, cmd, handled = m.handleEarKey(keyMsg(st.key))
+		if !handled {
+			t.Errorf("%q: not handled", st.key)
+		}
+		if st.plays {
+			play(t, cmd)
+		} else if cmd != nil {
+			t.Errorf("%q: unexpected command", st.key)
+		}
+		if m.earAsked != st.asked || m.earCorrect != st.correct || m.earAnswer != st.answer {
+			t.Errorf("%q: asked %d correct %d answer %d, want %d %d %d",
+				st.key, m.earAsked, m.earCorrect, m.earAnswer, st.asked, st.correct, st.answer)
+		}
+	}
+	if sink.writes != 3 {
+		t.Errorf("sink played %d times, want 3", sink.writes)
+	}
+
+	m, cmd, _ = m.handleEarKey(keyMsg("tab"))
+	play(t, cmd)
+	if m.earQ.Category != eartrain.Triads {
+		t.Errorf("tab moved to %s, want Triads", m.earQ.Category)
+	}
+}
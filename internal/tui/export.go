@@ -0,0 +1,202 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/paulgreig/guitar-training/internal/audio"
+	"github.com/paulgreig/guitar-training/internal/exercise"
+	"github.com/paulgreig/guitar-training/internal/fretboard"
+	"github.com/paulgreig/guitar-training/internal/midi"
+	"github.com/paulgreig/guitar-training/internal/obs"
+	"github.com/paulgreig/guitar-training/internal/synth"
+)
+
+// exportedMsg reports the result of writing an export file.
+type exportedMsg struct {
+	path string
+	err  error
+}
+
+// WithExportDir returns a copy of the model that writes exported files to dir.
+func (m Model) WithExportDir(dir string) Model {
+	m.exportDir = dir
+	return m
+}
+
+// scaleRun returns the selected scale's current run and a name for it.
+func (m Model) scaleRun() ([]exercise.Note, string) {
+	scale := m.scales[m.selectedIndex]
+	locs, _ := m.runLocations(scale)
+	pattern := exercise.Patterns[m.runPattern]
+	return exercise.Run(m.tuning, locs, pattern), scale.Name + " " + pattern.Name
+}
+
+// scaleSequence turns the selected scale's current run into one note per beat.
+func (m Model) scaleSequence() ([]synth.Note, string) {
+	run, name := m.scaleRun()
+	notes := make([]synth.Note, len(run))
+	for i, n := range run {
+		notes[i] = synth.Note{Pitch: n.Pitch, String: n.Location.String, Beat: float64(i), Beats: 1}
+	}
+	return notes, name
+}
+
+// exportScaleWAV renders the selected scale's run at the run tempo and writes it as WAV.
+func (m Model) exportScaleWAV() (Model, tea.Cmd) {
+	notes, name := m.scaleSequence()
+	if len(notes) == 0 {
+		return m, nil
+	}
+	path := filepath.Join(m.exportDir, FileName(name, ".wav"))
+	renderer := synth.NewRenderer(audio.DefaultSampleRate, len(m.tuning.Strings))
+	bpm := m.runBPM
+	m.exportStatus = "Exporting " + path + "…"
+	return m, func() tea.Msg {
+		err := writeWAVFile(path, renderer.Render(notes, bpm), renderer.Rate)
+		if err == nil {
+			obs.Event("scale_exported", map[string]interface{}{
+				"path":   path,
+				"format": "wav",
+				"notes":  len(notes),
+				"bpm":    bpm,
+			})
+		}
+		return exportedMsg{path: path, err: err}
+	}
+}
+
+// listenScale renders the selected scale's run and streams it to the audio sink.
+func (m Model) listenScale() (Model, tea.Cmd) {
+	notes, _ := m.scaleSequence()
+	if len(notes) == 0 {
+		return m, nil
+	}
+	if _, silent := m.sink.(audio.Discard); silent {
+		m.exportStatus = "No audio device – press e to export a WAV file instead"
+		return m, nil
+	}
+	sink := m.sink
+	renderer := synth.NewRenderer(sink.SampleRate(), len(m.tuning.Strings))
+	bpm := m.runBPM
+	m.exportStatus = fmt.Sprintf("Playing (%s)…", synth.Duration(notes, bpm).Round(time.Second))
+	return m, func() tea.Msg {
+		return audioPlayedMsg{err: sink.Write(renderer.Render(notes, bpm))}
+	}
+}
+
+// exportScaleMIDI writes the selected scale's run as a MIDI file at the run
+// tempo in the metronome's time signature.
+func (m Model) exportScaleMIDI() (Model, tea.Cmd) {
+	run, name := m.scaleRun()
+	if len(run) == 0 {
+		return m, nil
+	}
+	seq := midi.FromRun(name, run, m.runBPM)
+	seq.Numerator, seq.Denominator = m.metronome.Signature.Beats, m.metronome.Signature.Unit
+	return m.exportMIDI(seq, "scale_exported")
+}
+
+// exportProgression writes the chords queued in the chord list as a MIDI
+// progression, one bar each, using each chord's first voicing.
+func (m Model) exportProgression() (Model, tea.Cmd) {
+	var voicings []fretboard.Voicing
+	var names []string
+	for _, i := range m.progression {
+		if i >= len(m.chords) {
+			continue
+		}
+		v, _ := chordVoicings(m.chords[i], m.tuning)
+		if len(v) == 0 {
+			m.exportStatus = fmt.Sprintf("No playable voicing for %s in %s tuning", m.chords[i].Name, m.tuning.Name)
+			return m, nil
+		}
+		voicings = append(voicings, v[0])
+		names = append(names, m.chords[i].Name)
+	}
+	if len(voicings) == 0 {
+		return m, nil
+	}
+	sig := m.metronome.Signature
+	bar := float64(sig.Beats) * 4 / float64(sig.Unit) // in quarter notes: 3.5 in 7/8
+	seq := midi.Progression(strings.Join(names, " "), m.tuning, voicings, m.metronome.BPM, bar)
+	seq.Numerator, seq.Denominator = sig.Beats, sig.Unit
+	return m.exportMIDI(seq, "progression_exported")
+}
+
+// exportMIDI saves seq as a format 1 MIDI file in the export directory.
+func (m Model) exportMIDI(seq midi.Sequence, event string) (Model, tea.Cmd) {
+	path := filepath.Join(m.exportDir, FileName(seq.Name, ".mid"))
+	m.exportStatus = "Exporting " + path + "…"
+	return m, func() tea.Msg {
+		f, err := seq.File(1)
+		if err == nil {
+			err = os.MkdirAll(filepath.Dir(path), 0o755)
+		}
+		if err == nil {
+			err = f.Save(path)
+		}
+		if err == nil {
+			obs.Event(event, map[string]interface{}{
+				"path":   path,
+				"format": "midi",
+				"notes":  len(seq.Notes),
+				"bpm":    seq.BPM,
+			})
+		}
+		return exportedMsg{path: path, err: err}
+	}
+}
+
+func writeWAVFile(path string, samples []float64, rate int) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("could not create export directory: %w", err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("could not create %s: %w", path, err)
+	}
+	if err := audio.WriteWAV(f, samples, rate); err != nil {
+		f.Close()
+		return fmt.Errorf("could not write %s: %w", path, err)
+	}
+	return f.Close()
+}
+
+// handleExported shows where an export was saved, or why it failed in the
+// status bar.
+func (m Model) handleExported(msg exportedMsg) Model {
+	if msg.err != nil {
+		obs.Error("export failed: %v", msg.err)
+		m.exportStatus = ""
+		return m.notify("export", severityError, "Export failed: "+msg.err.Error())
+	}
+	m.exportStatus = "Saved " + msg.path
+	return m.clearNotice("export")
+}
+
+// FileName turns a display name into the file name used for exports, e.g.
+// "C Major Up and down" with ext ".wav" becomes "c-major-up-and-down.wav".
+func FileName(name, ext string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r == '#':
+			b.WriteString("sharp")
+			dash = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+			dash = false
+		case !dash && b.Len() > 0:
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-") + ext
+}
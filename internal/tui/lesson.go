@@ -0,0 +1,157 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/paulgreig/guitar-training/internal/fretboard"
+	"github.com/paulgreig/guitar-training/internal/markdown"
+	"github.com/paulgreig/guitar-training/internal/tab"
+	"github.com/paulgreig/guitar-training/internal/theory"
+)
+
+// defaultWidth is the layout width used before the terminal size is known.
+const defaultWidth = 80
+
+// contentWidth is the width lesson content is laid out in.
+func (m Model) contentWidth() int {
+	if m.width <= 0 {
+		return defaultWidth
+	}
+	return m.width
+}
+
+// lessonRenderer lays out lesson Markdown wrapped to width. Fenced blocks
+// can hold widgets drawn for the current tuning:
+//
+//	```scale C major```          fretboard of the scale (add "shape N" for one shape)
+//	```chord Am```               chord diagram of the first voicing
+//	```tab                       ASCII tab, aligned and wrapped
+//	e|-0-2-3-|
+//	```
+//
+// Widgets that can't be drawn are shown with the problem below them.
+func (m Model) lessonRenderer(width int) markdown.Renderer {
+	r := markdown.Renderer{Width: width, Styles: m.styles.Lesson}
+	r.Block = func(info, body string) (string, bool) {
+		kind, arg, _ := strings.Cut(info, " ")
+		arg = strings.TrimSpace(arg)
+		switch strings.ToLower(kind) {
+		case "tab":
+			return renderTabBlock(body, width), true
+		case "scale":
+			return m.renderScaleWidget(arg), true
+		case "chord":
+			return m.renderChordWidget(arg), true
+		}
+		return "", false
+	}
+	return r
+}
+
+// lessonTitles returns the titles of the lessons with the given IDs, or the
+// ID itself for lessons that aren't loaded.
+func (m Model) lessonTitles(ids []string) []string {
+	out := make([]string, len(ids))
+	for i, id := range ids {
+		out[i] = id
+		for _, l := range m.lessons {
+			if l.ID == id {
+				out[i] = l.Title
+				break
+			}
+		}
+	}
+	return out
+}
+
+func renderTabBlock(text string, width int) string {
+	t, err := tab.Parse(text)
+	if err != nil {
+		return text + "\n(tab error: " + err.Error() + ")"
+	}
+	return t.Render(width)
+}
+
+// parseScaleSpec reads a scale widget's spec: a root and scale type such as
+// "A minor pentatonic", optionally followed by "shape 2". shape is 0 when
+// no shape is given.
+func parseScaleSpec(spec string) (scale Scale, shape int, err error) {
+	if fields := strings.Fields(spec); len(fields) > 2 && strings.EqualFold(fields[len(fields)-2], "shape") {
+		n, err := strconv.Atoi(fields[len(fields)-1])
+		if err != nil || n < 1 {
+			return Scale{}, 0, fmt.Errorf("bad shape %q", fields[len(fields)-1])
+		}
+		shape, spec = n, strings.Join(fields[:len(fields)-2], " ")
+	}
+	rootName, typ, _ := strings.Cut(strings.TrimSpace(spec), " ")
+	scale = Scale{Root: rootName, Type: strings.TrimSpace(typ)}
+	if err := resolveScale(&scale); err != nil {
+		return Scale{}, 0, err
+	}
+	return scale, shape, nil
+}
+
+// renderScaleWidget draws spec ("A minor pentatonic", optionally followed by
+// "shape 2") on the neck.
+func (m Model) renderScaleWidget(spec string) string {
+	scale, shape, err := parseScaleSpec(spec)
+	if err != nil {
+		return fmt.Sprintf("(scale error: %v)", err)
+	}
+
+	tuning := m.tuning
+	frets := fretboard.DefaultFrets
+	marks := scaleMarks(scale, tuning, frets)
+	heading := fmt.Sprintf("%s: %s", scale.Name, strings.Join(scale.Notes, " "))
+	if shape > 0 {
+		shapes := scaleShapes(scale, tuning)
+		if shape > len(shapes) {
+			return fmt.Sprintf("(scale error: %s has %d shapes)", scale.Name, len(shapes))
+		}
+		s := shapes[shape-1]
+		lo, hi := s.FretRange()
+		heading += fmt.Sprintf(" – %s, frets %d-%d", s.Name, lo, hi)
+		marks = make(map[fretboard.Location]bool, len(s.Locations))
+		for _, loc := range s.Locations {
+			marks[loc] = true
+		}
+		frets = max(frets, hi)
+	}
+	root, hasRoot := scaleRoot(scale)
+	return strings.TrimRight(m.renderNeck(heading, frets, func(loc fretboard.Location) string {
+		switch {
+		case !marks[loc]:
+			return ""
+		case hasRoot && tuning.PitchAt(loc.String, loc.Fret).PitchClass() == root:
+			return "◉"
+		default:
+			return "●"
+		}
+	}), "\n ")
+}
+
+// renderChordWidget draws the first voicing of a chord symbol such as "Am7",
+// using the authored voicings when the chord is in the chord list.
+func (m Model) renderChordWidget(sym string) string {
+	chord, ok := Chord{}, false
+	for _, c := range m.chords {
+		if c.Name == sym {
+			chord, ok = c, true
+			break
+		}
+	}
+	if !ok {
+		root, q, err := theory.ParseChordSymbol(sym)
+		if err != nil {
+			return fmt.Sprintf("(chord error: %v)", err)
+		}
+		chord = Chord{Name: sym, Root: root.String(), Quality: q.Name}
+	}
+	voicings, _ := chordVoicings(chord, m.tuning)
+	if len(voicings) == 0 {
+		return fmt.Sprintf("(no playable voicing for %s in %s tuning)", sym, m.tuning.Name)
+	}
+	return fmt.Sprintf("%s: %s\n%s", chord.Name, voicingString(voicings[0]), strings.TrimRight(renderChordDiagram(voicings[0], m.tuning), "\n"))
+}
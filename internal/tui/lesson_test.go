@@ -0,0 +1,59 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/paulgreig/guitar-training/internal/config"
+	"github.com/paulgreig/guitar-training/internal/fretboard"
+)
+
+// TestLessonWidgets checks each widget block draws, or says why it can't.
+func TestLessonWidgets(t *testing.T) {
+	tests := []struct {
+		name     string
+		doc      string
+		contains []string
+	}{
+		{"scale", "```scale A minor pentatonic```", []string{"A Minor Pentatonic: A C D E G", "◉", "●"}},
+		{"scale shape", "```scale A minor pentatonic shape 1```", []string{"A Minor Pentatonic: A C D E G – ", ", frets "}},
+		{"too few shapes", "```scale A minor pentatonic shape 99```", []string{"(scale error: A Minor Pentatonic has 5 shapes)"}},
+		{"bad shape", "```scale A minor pentatonic shape x```", []string{`(scale error: bad shape "x")`}},
+		{"bad root", "```scale H major```", []string{"(scale error: "}},
+		{"no type", "```scale C```", []string{"(scale error: both root and type are required"}},
+		{"chord", "```chord Am```", []string{"Am: x02210"}},
+		{"chord symbol", "```chord Cmaj7```", []string{"Cmaj7: "}},
+		{"bad chord", "```chord Q7```", []string{"(chord error: "}},
+		{"tab", "```tab\ne|-0-2-3-|\nB|-1-----|\n```", []string{"e|-0-2-3-|", "B|-1-----|"}},
+		{"bad tab", "```tab\nnot a tab\n```", []string{"not a tab", "(tab error: line 1: not a tab line"}},
+		{"other block", "```text\n```chord Am``` stays code\n```", []string{"```chord Am``` stays code"}},
+	}
+	m := NewModel(config.Default())
+	for _, tt := range tests {
+		got := m.lessonRenderer(80).Render(tt.doc)
+		for _, s := range tt.contains {
+			if !strings.Contains(got, s) {
+				t.Errorf("%s: rendered\n%s\nwithout %q", tt.name, got, s)
+			}
+		}
+	}
+}
+
+// TestLessonWidgetsTuning checks widgets are drawn for the chosen tuning.
+func TestLessonWidgetsTuning(t *testing.T) {
+	tests := []struct {
+		tuning, chord, want string
+	}{
+		{"standard", "D", "D: xx0232"},
+		{"drop-d", "D", "D: 000232"},
+		{"bass-4", "G", "G: 3200"},
+		{"drop-d", "D5", "(no playable voicing for D5 in Drop D tuning)"},
+	}
+	m := NewModel(config.Default())
+	for _, tt := range tests {
+		m.tuning, _ = fretboard.LookupTuning(tt.tuning)
+		if got := m.renderChordWidget(tt.chord); !strings.HasPrefix(got, tt.want) {
+			t.Errorf("%s in %s is\n%s", tt.chord, tt.tuning, got)
+		}
+	}
+}
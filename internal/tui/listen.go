@@ -0,0 +1,129 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/paulgreig/guitar-training/internal/exercise"
+	"github.com/paulgreig/guitar-training/internal/fretboard"
+	"github.com/paulgreig/guitar-training/internal/obs"
+	"github.com/paulgreig/guitar-training/internal/pitch"
+	"github.com/paulgreig/guitar-training/internal/practice"
+)
+
+// listenHold is how many consecutive frames must hear the expected note
+// before the run moves on, so passing noise and pick attacks don't count.
+const listenHold = 3
+
+// startListening builds the run for the selected scale and opens the audio
+// input to listen for it.
+func (m Model) startListening() (Model, tea.Cmd) {
+	m.run, _ = m.scaleRun()
+	if len(m.run) == 0 {
+		return m, nil
+	}
+	m.listenStep, m.listenLaps, m.listenHeld = 0, 0, 0
+	m.listenStart = time.Now()
+	m.view = "scale-listen"
+	obs.Event("scale_listen_started", map[string]interface{}{
+		"scale": m.scales[m.selectedIndex].Name,
+		"input": m.audioIn,
+		"notes": len(m.run),
+	})
+	return m.openAudio()
+}
+
+// followRun moves the run on once the expected note has been heard for
+// listenHold frames in a row. A repeated note must be played again, so the
+// held note has to stop or change before it counts a second time.
+func (m Model) followRun(est pitch.Estimate, ok bool) Model {
+	want := m.run[m.listenStep].Pitch
+	switch {
+	case ok && est.Pitch == want:
+		if m.listenHeld >= 0 {
+			m.listenHeld++
+		}
+	default:
+		m.listenHeld = 0
+	}
+	if m.listenHeld >= listenHold {
+		m.listenStep++
+		if m.listenStep >= len(m.run) {
+			m.listenStep = 0
+			m.listenLaps++
+		}
+		m.listenHeld = 0
+		if m.run[m.listenStep].Pitch == want {
+			m.listenHeld = -1
+		}
+	}
+	return m
+}
+
+// handleListenKey handles the listening screen: restart, pattern and leaving.
+func (m Model) handleListenKey(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	switch msg.String() {
+	case "r", "R":
+		m.cycleRunPattern(msg.String() == "r")
+		m.run, _ = m.scaleRun()
+		m.listenStep, m.listenHeld = 0, 0
+	case "esc":
+		m.stopListening()
+		obs.Event("scale_listen_stopped", map[string]interface{}{
+			"step": m.listenStep,
+			"laps": m.listenLaps,
+		})
+		return m.finishSession(practice.Session{
+			Kind:     practice.KindScale,
+			Item:     m.scales[m.selectedIndex].Name,
+			Exercise: exercise.Patterns[m.runPattern].Name + " (listening)",
+			Start:    m.listenStart,
+			Seconds:  time.Since(m.listenStart).Seconds(),
+		}, "scale-detail"), nil, true
+	default:
+		return m, nil, false
+	}
+	return m, nil, true
+}
+
+func (m Model) renderListen() string {
+	if m.selectedIndex >= len(m.scales) || len(m.run) == 0 {
+		return "Nothing to play"
+	}
+	scale := m.scales[m.selectedIndex]
+	title := m.styles.Title.Render(scale.Name + " – listening")
+
+	cur := m.run[m.listenStep]
+	inRun := make(map[fretboard.Location]bool, len(m.run))
+	frets := fretboard.DefaultFrets
+	for _, n := range m.run {
+		inRun[n.Location] = true
+		if n.Location.Fret > frets {
+			frets = n.Location.Fret
+		}
+	}
+
+	heard := "–"
+	if m.listenOK {
+		heard = fmt.Sprintf("%s %+.0f¢ (%.1f Hz)", m.listenHeard.Pitch, m.listenHeard.Cents, m.listenHeard.Freq)
+	}
+	info := m.styles.Text.Render(fmt.Sprintf("%s – note %d/%d, lap %d\nPlay: %s   Heard: %s\n%s\n",
+		exercise.Patterns[m.runPattern].Name, m.listenStep+1, len(m.run), m.listenLaps+1,
+		cur.Pitch, heard, m.listenStatus))
+
+	neck := m.renderNeck(fmt.Sprintf("Fretboard (%s):", m.tuning.Name), frets, func(loc fretboard.Location) string {
+		switch {
+		case loc == cur.Location:
+			return m.styles.Selected.Render("●")
+		case inRun[loc]:
+			return "·"
+		default:
+			return ""
+		}
+	})
+
+	help := m.styles.Text.Render("\nPlay each note to move on – r/R pattern, Esc to stop")
+	return lipgloss.JoinVertical(lipgloss.Left, title, info, neck, help)
+}
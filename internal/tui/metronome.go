@@ -0,0 +1,149 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/paulgreig/guitar-training/internal/metronome"
+	"github.com/paulgreig/guitar-training/internal/obs"
+)
+
+// metronomeTickMsg fires when the next metronome tick is due. gen guards
+// against ticks scheduled before the metronome was stopped or retimed.
+type metronomeTickMsg struct {
+	gen int
+}
+
+// WithClicker returns a copy of the model that sounds metronome ticks with c.
+func (m Model) WithClicker(c metronome.Clicker) Model {
+	m.clicker = c
+	return m
+}
+
+// scheduleMetronomeTick waits one metronome interval and then delivers a tick.
+func (m Model) scheduleMetronomeTick() tea.Cmd {
+	gen := m.metronomeGen
+	return tea.Tick(m.metronome.Interval(), func(time.Time) tea.Msg {
+		return metronomeTickMsg{gen: gen}
+	})
+}
+
+// clickCmd plays a tick off the UI goroutine.
+func clickCmd(c metronome.Clicker, level metronome.Level) tea.Cmd {
+	if c == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		if err := c.Click(level); err != nil {
+			obs.Warn("metronome click failed: %v", err)
+		}
+		return nil
+	}
+}
+
+// handleMetronomeTick advances the metronome, sounds the tick and schedules the next.
+func (m Model) handleMetronomeTick(msg metronomeTickMsg) (Model, tea.Cmd) {
+	if msg.gen != m.metronomeGen || !m.metronome.Running {
+		return m, nil
+	}
+	tick := m.metronome.Next()
+	return m, tea.Batch(clickCmd(m.clicker, tick.Level), m.scheduleMetronomeTick())
+}
+
+// restartMetronome restarts the bar with fresh timing if it is running.
+func (m Model) restartMetronome() (Model, tea.Cmd) {
+	m.metronomeGen++
+	if !m.metronome.Running {
+		return m, nil
+	}
+	m.metronome.Start()
+	tick := m.metronome.Next()
+	return m, tea.Batch(clickCmd(m.clicker, tick.Level), m.scheduleMetronomeTick())
+}
+
+// handleMetronomeKey handles the metronome screen's controls. Keys it does
+// not use are left for the global handler.
+func (m Model) handleMetronomeKey(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	switch msg.String() {
+	case " ":
+		if m.metronome.Running {
+			m.metronome.Stop()
+			m.metronomeGen++
+			obs.Event("metronome_stopped", map[string]interface{}{"bpm": m.metronome.BPM})
+			return m, nil, true
+		}
+		m.metronome.Running = true
+		obs.Event("metronome_started", map[string]interface{}{
+			"bpm":         m.metronome.BPM,
+			"signature":   m.metronome.Signature.String(),
+			"subdivision": m.metronome.Subdivision.Name,
+		})
+		m, cmd := m.restartMetronome()
+		return m, cmd, true
+	case "up", "k", "+", "=":
+		m.metronome.SetBPM(m.metronome.BPM + 1)
+	case "down", "j", "-":
+		m.metronome.SetBPM(m.metronome.BPM - 1)
+	case "right", "l":
+		m.metronome.SetBPM(m.metronome.BPM + 5)
+	case "left", "h":
+		m.metronome.SetBPM(m.metronome.BPM - 5)
+	case "t":
+		if !m.metronome.Tap(time.Now()) {
+			return m, nil, true
+		}
+	case "b":
+		m.metronome.CycleSignature()
+	case "d":
+		m.metronome.CycleSubdivision()
+	default:
+		return m, nil, false
+	}
+	m, cmd := m.restartMetronome()
+	return m, cmd, true
+}
+
+func (m Model) renderMetronome() string {
+	title := m.styles.Title.Render("Metronome")
+	met := m.metronome
+
+	state := "stopped"
+	if met.Running {
+		state = "running"
+	}
+	info := fmt.Sprintf("Tempo: %d BPM   Time: %s   Subdivision: %s   (%s)\n\n",
+		met.BPM, met.Signature, met.Subdivision.Name, state)
+
+	// Beat indicator: one block per beat, subdivision dots between beats.
+	cur := met.Current()
+	var beats []string
+	for b := 0; b < met.Signature.Beats; b++ {
+		for p := 0; p < met.Subdivision.Parts; p++ {
+			active := met.Running && cur.Beat == b && cur.Part == p
+			mark := "·"
+			switch {
+			case p == 0 && b == 0:
+				mark = "◆"
+				if active {
+					mark = m.styles.Selected.Render("◆")
+				}
+			case p == 0:
+				mark = "○"
+				if active {
+					mark = m.styles.Selected.Render("●")
+				}
+			case active:
+				mark = m.styles.Selected.Render("•")
+			}
+			beats = append(beats, mark)
+		}
+	}
+	indicator := strings.Join(beats, " ")
+
+	help := m.styles.Text.Render("\nSpace start/stop, ↑/↓ ±1 BPM, ←/→ ±5 BPM, t tap tempo,\nb time signature, d subdivision, Esc to go back")
+
+	return lipgloss.JoinVertical(lipgloss.Left, title, m.styles.Text.Render(info), indicator, help)
+}
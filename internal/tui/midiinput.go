@@ -0,0 +1,36 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/paulgreig/guitar-training/internal/midi"
+)
+
+// midiNoteMsg carries a note played on the MIDI input.
+type midiNoteMsg struct {
+	event midi.NoteEvent
+}
+
+// midiClosedMsg reports that the MIDI input has ended.
+type midiClosedMsg struct{}
+
+// WithMIDIInput returns a copy of the model that judges scale runs against
+// notes played on in.
+func (m Model) WithMIDIInput(in midi.Input) Model {
+	m.midiIn = in
+	return m
+}
+
+// waitForMIDI waits for the next note from in. It is re-issued after every
+// note, so the input is read one event at a time.
+func waitForMIDI(in midi.Input) tea.Cmd {
+	if in == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		e, ok := <-in.Events()
+		if !ok {
+			return midiClosedMsg{}
+		}
+		return midiNoteMsg{event: e}
+	}
+}
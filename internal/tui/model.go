@@ -2,27 +2,133 @@ package tui
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/paulgreig/guitar-training/internal/audio"
+	"github.com/paulgreig/guitar-training/internal/config"
+	"github.com/paulgreig/guitar-training/internal/eartrain"
+	"github.com/paulgreig/guitar-training/internal/exercise"
+	"github.com/paulgreig/guitar-training/internal/fretboard"
+	"github.com/paulgreig/guitar-training/internal/markdown"
+	"github.com/paulgreig/guitar-training/internal/metronome"
+	"github.com/paulgreig/guitar-training/internal/midi"
 	"github.com/paulgreig/guitar-training/internal/obs"
+	"github.com/paulgreig/guitar-training/internal/pitch"
+	"github.com/paulgreig/guitar-training/internal/practice"
+	"github.com/paulgreig/guitar-training/internal/quiz"
+	"github.com/paulgreig/guitar-training/internal/theory"
 )
 
 type Model struct {
 	// Current view state
-	view string // "menu", "scales", "lessons", "scale-detail", "lesson-detail"
+	view string // "menu", "scales", "lessons", "chords", "scale-detail", "lesson-detail", "chord-detail", "tunings", "metronome", "scale-run", "scale-listen", "rate", "progress", "today", "quiz", "ear", "tuner"
 	
 	// Data
-	scales  []Scale
-	lessons []Lesson
+	dataPaths    []string // content directories layered over the built-in content
+	scales       []Scale
+	lessons      []Lesson
+	chords       []Chord
+	contentStamp string // sizes and times of the content files when last loaded
 	
 	// Navigation
 	selectedIndex int
 	cursor        int
+	width         int // terminal width, 0 until the first resize message
+	height        int // terminal height, 0 until the first resize message
+
+	// Instrument
+	tuning       fretboard.Tuning
+	shapeIndex   int // 0 = whole neck, n = nth shape of the selected scale
+	voicingIndex int // voicing shown for the selected chord
+
+	// Scale-run practice
+	runPattern int // index into exercise.Patterns
+	runBPM     int
+	run        []exercise.Note
+	runStep    int
+	runLaps    int
+	runPlaying bool
+	runGen     int
+	runStart   time.Time
+	runMaxBPM  int
+
+	// MIDI input
+	midiIn        midi.Input
+	runJudge      exercise.Judge
+	runLastPlayed string
+
+	// Audio input: listening practice and tuner
+	audioIn      string
+	a4           float64 // reference pitch for naming detected notes
+	listener     *pitch.Tracker
+	listenGen    int
+	listenStep   int
+	listenLaps   int
+	listenHeld   int // frames the expected note has been heard; -1 until a repeated note is replayed
+	listenHeard  pitch.Estimate
+	listenOK     bool
+	listenStatus string
+	listenStart  time.Time
+	tunerNote    pitch.Estimate // smoothed latest reading
+	tunerQuiet   int            // frames since a pitch was last heard
+
+	// Practice log
+	store        *practice.Store
+	pending      *practice.Session // awaiting a self-rating
+	ratingReturn string            // view to show after rating
+	lessonStart  time.Time
+	fromToday    bool      // detail view was opened from today's queue
+	todayStart   time.Time // when it was opened
+	todayRated   bool      // a session on it has been rated since
+
+	// Lesson reader
+	lessonScroll int            // first line of the lesson on screen
+	lessonPos    map[string]int // last read position by lesson ID
+	lessonTOC    bool           // showing the table of contents
+	tocCursor    int
+	layout       *readerLayout // the lesson last laid out, shared by copies of the model
+
+	// Fretboard quiz
+	quiz         *quiz.Quiz // persists across visits so weak areas keep their weight
+	quizKind     quiz.Kind
+	quizQ        quiz.Question
+	quizInput    string
+	quizCursor   fretboard.Location
+	quizPicked   map[fretboard.Location]bool
+	quizFeedback string
+	quizAsked    int
+	quizCorrect  int
+	quizStart    time.Time
+
+	// Ear training
+	sink        audio.Sink
+	ear         *eartrain.Generator
+	earCategory int // index into eartrain.Categories
+	earQ        eartrain.Question
+	earAnswer   int // chosen option, -1 until answered
+	earAsked    int
+	earCorrect  int
+	earStart    time.Time
+
+	// Export
+	exportDir    string
+	exportStatus string // result of the last export, shown on the detail screen
+	progression  []int  // chord indexes queued for MIDI export
+
+	// Metronome
+	metronome    metronome.Metronome
+	metronomeGen int
+	clicker      metronome.Clicker
 	
+	// Status bar
+	notices []notice // newest last
+
 	// Styles
 	styles Styles
+	keys   map[string]tea.KeyMsg // configured key → the key it stands for
 }
 
 type Styles struct {
@@ -30,15 +136,65 @@ type Styles struct {
 	Menu     lipgloss.Style
 	Selected lipgloss.Style
 	Text     lipgloss.Style
+	Info     lipgloss.Style
+	Warning  lipgloss.Style
+	Error    lipgloss.Style
+	InTune   lipgloss.Style // tuner readings by how far off they are
+	Close    lipgloss.Style
+	Off      lipgloss.Style
+	Lesson   markdown.Styles
 }
 
-func NewModel() Model {
-	return Model{
+// NewModel returns the model for cfg: its content directories, tuning, theme,
+// key bindings and the other settings that don't need opening first.
+func NewModel(cfg *config.Config) Model {
+	m := Model{
 		view:          "menu",
+		dataPaths:     cfg.DataPaths,
+		contentStamp:  stampContent(cfg.DataPaths),
 		selectedIndex: 0,
 		cursor:        0,
-		styles:        defaultStyles(),
+		tuning:        fretboard.Standard,
+		runBPM:        defaultRunBPM,
+		metronome:     metronome.New(),
+		clicker:       metronome.Silent{},
+		sink:          audio.Discard{},
+		exportDir:     ".",
+		audioIn:       "none",
+		a4:            theory.StandardA4,
+		lessonPos:     map[string]int{},
+		layout:        &readerLayout{},
+		styles:        themeStyles(cfg.Theme),
+		keys:          keyBindings(cfg.Keys),
+	}
+	return m.WithTuning(cfg.Tuning).WithExportDir(cfg.Export).WithAudioInput(cfg.AudioIn).WithReference(cfg.A4)
+}
+
+// WithTuning returns a copy of the model using the named tuning. Unknown
+// names are logged and leave the current tuning in place.
+func (m Model) WithTuning(name string) Model {
+	t, ok := fretboard.LookupTuning(name)
+	if !ok {
+		obs.Warn("unknown tuning %q, using %s", name, m.tuning.Name)
+		return m
 	}
+	m.tuning = t
+	return m
+}
+
+// menuItems lists the main menu entries in display order.
+var menuItems = []string{
+	"Today's Practice",
+	"View Scales",
+	"View Lessons",
+	"View Chords",
+	"Note Quiz",
+	"Ear Training",
+	"Tuner",
+	"Metronome",
+	"Progress",
+	"Tuning",
+	"Quit",
 }
 
 func defaultStyles() Styles {
@@ -54,18 +210,45 @@ func defaultStyles() Styles {
 			Bold(true),
 		Text: lipgloss.NewStyle().
 			Foreground(lipgloss.Color("252")),
+		Info: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("75")),
+		Warning: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("214")),
+		Error: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("196")).
+			Bold(true),
+		InTune: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("46")).
+			Bold(true),
+		Close: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("220")).
+			Bold(true),
+		Off: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("196")).
+			Bold(true),
+		Lesson: markdown.DefaultStyles(),
 	}
 }
 
 func (m Model) Init() tea.Cmd {
-	// Load scales and lessons data
-	return tea.Batch(loadScales(), loadLessons())
+	// Load scales and lessons data, and reload them when their files change
+	return tea.Batch(loadContent(m.dataPaths), watchContent(m.dataPaths), waitForMIDI(m.midiIn))
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		obs.RecordKeyPress()
+		if bound, ok := m.keys[msg.String()]; ok {
+			msg = bound
+		}
+		if next, cmd, ok := m.handleStatusKey(msg); ok {
+			return next, cmd
+		}
+		m = m.clearInfo()
+		if next, cmd, ok := m.handleModeKey(msg); ok {
+			return next, cmd
+		}
 		switch msg.String() {
 		case "ctrl+c", "q":
 			obs.Event("quit_requested", map[string]interface{}{
@@ -82,6 +265,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.cursor < maxItems-1 {
 				m.cursor++
 			}
+		case "v", "V":
+			if m.view == "chord-detail" && m.selectedIndex < len(m.chords) {
+				m.cycleVoicing(msg.String() == "v")
+			}
 		case "enter":
 			return m.handleEnter()
 		case "esc":
@@ -90,36 +277,167 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.cursor = 0
 			}
 		}
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m = m.clearLayout()
+		if m.view == "lesson-detail" && m.selectedIndex < len(m.lessons) {
+			// Rewrapping changes the line count; keep the position in range.
+			m = m.scrollLesson(m.lessonScroll)
+		}
 	case ScalesLoadedMsg:
-		m.scales = msg.Scales
+		m = m.setScales(msg.Scales, msg.Err)
 	case LessonsLoadedMsg:
-		m.lessons = msg.Lessons
+		m = m.setLessons(msg.Lessons, msg.Err)
+	case ChordsLoadedMsg:
+		m = m.setChords(msg.Chords, msg.Err)
+	case contentPolledMsg:
+		return m.handleContentPolled(msg)
+	case metronomeTickMsg:
+		return m.handleMetronomeTick(msg)
+	case scaleRunTickMsg:
+		return m.handleScaleRunTick(msg)
+	case practiceSavedMsg:
+		if msg.err != nil {
+			obs.Error("failed to save practice session: %v", msg.err)
+			m = m.notify("practice", severityError, fmt.Sprintf("Could not save practice session: %v", msg.err))
+		} else {
+			m = m.clearNotice("practice")
+		}
+	case midiNoteMsg:
+		m = m.handleMIDINote(msg.event)
+		return m, waitForMIDI(m.midiIn)
+	case midiClosedMsg:
+		obs.Warn("midi input closed")
+		m.midiIn = nil
+		m = m.notify("midi", severityWarning, "MIDI input closed; scale runs are no longer scored")
+	case audioPlayedMsg:
+		if msg.err != nil {
+			obs.Warn("could not play audio: %v", msg.err)
+			m = m.notify("audio", severityWarning, fmt.Sprintf("Could not play audio: %v", msg.err))
+		}
+	case exportedMsg:
+		m = m.handleExported(msg)
+	case audioOpenedMsg:
+		return m.handleAudioOpened(msg)
+	case pitchFrameMsg:
+		return m.handlePitchFrame(msg)
 	}
 
 	return m, nil
 }
 
+// handleModeKey gives the active view first refusal on a key press, so
+// screens with their own controls can use keys that mean something else in
+// the menus. ok reports whether the key was consumed.
+func (m Model) handleModeKey(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	switch m.view {
+	case "metronome":
+		return m.handleMetronomeKey(msg)
+	case "scale-detail":
+		return m.handleScaleDetailKey(msg)
+	case "scale-run":
+		return m.handleScaleRunKey(msg)
+	case "scale-listen":
+		return m.handleListenKey(msg)
+	case "lesson-detail":
+		return m.handleLessonDetailKey(msg)
+	case "rate":
+		return m.handleRateKey(msg)
+	case "chords":
+		return m.handleChordsKey(msg)
+	case "quiz":
+		return m.handleQuizKey(msg)
+	case "ear":
+		return m.handleEarKey(msg)
+	case "tuner":
+		return m.handleTunerKey(msg)
+	}
+	return m, nil, false
+}
+
 func (m Model) handleEnter() (Model, tea.Cmd) {
 	start := time.Now()
 	switch m.view {
 	case "menu":
-		switch m.cursor {
-		case 0:
+		m.fromToday = false
+		switch menuItems[m.cursor] {
+		case "Today's Practice":
+			obs.Event("navigate_to_today", map[string]interface{}{})
+			m.view = "today"
+			m.cursor = 0
+		case "View Scales":
 			obs.RecordScalesListView()
 			obs.RecordMenuSelectionDuration("scales_list", time.Since(start))
 			obs.Event("navigate_to_scales", map[string]interface{}{})
 			m.view = "scales"
 			m.cursor = 0
-		case 1:
+		case "View Lessons":
 			obs.RecordLessonsListView()
 			obs.RecordMenuSelectionDuration("lessons_list", time.Since(start))
 			obs.Event("navigate_to_lessons", map[string]interface{}{})
 			m.view = "lessons"
 			m.cursor = 0
-		case 2:
+		case "View Chords":
+			obs.RecordChordsListView()
+			obs.RecordMenuSelectionDuration("chords_list", time.Since(start))
+			obs.Event("navigate_to_chords", map[string]interface{}{})
+			m.view = "chords"
+			m.cursor = 0
+			m.exportStatus = ""
+		case "Note Quiz":
+			obs.Event("navigate_to_quiz", map[string]interface{}{})
+			m = m.startQuiz()
+		case "Ear Training":
+			obs.Event("navigate_to_ear_training", map[string]interface{}{})
+			return m.startEarTraining()
+		case "Tuner":
+			obs.Event("navigate_to_tuner", map[string]interface{}{})
+			return m.startTuner()
+		case "Metronome":
+			obs.Event("navigate_to_metronome", map[string]interface{}{})
+			m.view = "metronome"
+			m.cursor = 0
+		case "Progress":
+			obs.Event("navigate_to_progress", map[string]interface{}{})
+			m.view = "progress"
+			m.cursor = 0
+		case "Tuning":
+			obs.Event("navigate_to_tunings", map[string]interface{}{})
+			m.view = "tunings"
+			m.cursor = 0
+			for i, t := range fretboard.Tunings() {
+				if t.ID == m.tuning.ID {
+					m.cursor = i
+				}
+			}
+		case "Quit":
 			obs.Event("menu_quit_selected", map[string]interface{}{})
 			return m, tea.Quit
 		}
+	case "today":
+		return m.openTodayItem()
+	case "chords":
+		if len(m.chords) > 0 && m.cursor < len(m.chords) {
+			obs.RecordChordDetailView()
+			obs.RecordMenuSelectionDuration("chord_detail", time.Since(start))
+			obs.Event("chord_detail_view", map[string]interface{}{
+				"index": m.cursor,
+				"name":  m.chords[m.cursor].Name,
+			})
+			m.view = "chord-detail"
+			m.selectedIndex = m.cursor
+			m.voicingIndex = 0
+		}
+	case "tunings":
+		tunings := fretboard.Tunings()
+		if m.cursor < len(tunings) {
+			m.tuning = tunings[m.cursor]
+			obs.Event("tuning_selected", map[string]interface{}{
+				"tuning": m.tuning.ID,
+			})
+			m.view = "menu"
+			m.cursor = 0
+		}
 	case "scales":
 		if len(m.scales) > 0 && m.cursor < len(m.scales) {
 			obs.RecordScaleDetailView()
@@ -130,6 +448,8 @@ func (m Model) handleEnter() (Model, tea.Cmd) {
 			})
 			m.view = "scale-detail"
 			m.selectedIndex = m.cursor
+			m.shapeIndex = 0
+			m.exportStatus = ""
 		}
 	case "lessons":
 		if len(m.lessons) > 0 && m.cursor < len(m.lessons) {
@@ -140,14 +460,22 @@ func (m Model) handleEnter() (Model, tea.Cmd) {
 				"title": m.lessons[m.cursor].Title,
 				"level": m.lessons[m.cursor].Level,
 			})
-			m.view = "lesson-detail"
-			m.selectedIndex = m.cursor
+			m = m.openLesson(m.cursor)
 		}
 	}
 	return m, nil
 }
 
+// View draws the current screen with the status bar, if any, below it.
 func (m Model) View() string {
+	screen := m.renderScreen()
+	if bar := m.statusBar(); bar != "" {
+		return lipgloss.JoinVertical(lipgloss.Left, screen, bar)
+	}
+	return screen
+}
+
+func (m Model) renderScreen() string {
 	switch m.view {
 	case "menu":
 		return m.renderMenu()
@@ -159,6 +487,30 @@ func (m Model) View() string {
 		return m.renderScaleDetail()
 	case "lesson-detail":
 		return m.renderLessonDetail()
+	case "chords":
+		return m.renderChordsList()
+	case "chord-detail":
+		return m.renderChordDetail()
+	case "tunings":
+		return m.renderTunings()
+	case "scale-run":
+		return m.renderScaleRun()
+	case "scale-listen":
+		return m.renderListen()
+	case "rate":
+		return m.renderRate()
+	case "progress":
+		return m.renderProgress()
+	case "today":
+		return m.renderToday()
+	case "quiz":
+		return m.renderQuiz()
+	case "ear":
+		return m.renderEarTraining()
+	case "tuner":
+		return m.renderTuner()
+	case "metronome":
+		return m.renderMetronome()
 	default:
 		return "Unknown view"
 	}
@@ -167,12 +519,6 @@ func (m Model) View() string {
 func (m Model) renderMenu() string {
 	title := m.styles.Title.Render("🎸 Guitar Training")
 	
-	menuItems := []string{
-		"View Scales",
-		"View Lessons",
-		"Quit",
-	}
-	
 	var menu string
 	for i, item := range menuItems {
 		if i == m.cursor {
@@ -182,9 +528,35 @@ func (m Model) renderMenu() string {
 		}
 	}
 	
+	statusLine := "Tuning: " + m.tuning.Name
+	if m.metronome.Running {
+		statusLine += fmt.Sprintf("   Metronome: %d BPM", m.metronome.BPM)
+	}
+	status := m.styles.Text.Render(statusLine)
 	help := m.styles.Text.Render("\nUse ↑/↓ to navigate, Enter to select, q to quit")
 	
-	return lipgloss.JoinVertical(lipgloss.Left, title, menu, help)
+	return lipgloss.JoinVertical(lipgloss.Left, title, menu, status, help)
+}
+
+func (m Model) renderTunings() string {
+	title := m.styles.Title.Render("Tuning")
+
+	var list string
+	for i, t := range fretboard.Tunings() {
+		line := fmt.Sprintf("%-18s %s", t.Name, strings.Join(t.StringLabels(), " "))
+		if t.ID == m.tuning.ID {
+			line += " ✓"
+		}
+		if i == m.cursor {
+			list += m.styles.Selected.Render("> "+line) + "\n"
+		} else {
+			list += m.styles.Menu.Render("  "+line) + "\n"
+		}
+	}
+
+	help := m.styles.Text.Render("\nUse ↑/↓ to navigate, Enter to select, Esc to go back")
+
+	return lipgloss.JoinVertical(lipgloss.Left, title, list, help)
 }
 
 func (m Model) renderScalesList() string {
@@ -237,81 +609,157 @@ func (m Model) renderScaleDetail() string {
 	scale := m.scales[m.selectedIndex]
 	title := m.styles.Title.Render(scale.Name)
 	
-	content := m.styles.Text.Render(fmt.Sprintf("Notes: %v\n\n", scale.Notes))
+	notes := m.styles.Text.Render(fmt.Sprintf("Notes: %v\n", scale.Notes))
 	
 	// Render scale positions on fretboard
-	content += m.renderFretboard(scale)
+	fb := m.renderFretboard(scale)
+	runInfo := fmt.Sprintf("Play along: %s at %d BPM", exercise.Patterns[m.runPattern].Name, m.runBPM)
+	if m.exportStatus != "" {
+		runInfo += "\n" + m.exportStatus
+	}
+	run := m.styles.Text.Render(runInfo)
 	
-	help := m.styles.Text.Render("\nPress Esc to go back")
+	help := m.styles.Text.Render("\nPress s/S to cycle shapes, r/R pattern, +/- tempo, p to play along,\nl to play it into the mic, a to hear it,\ne to export WAV, m to export MIDI, Esc to go back")
 	
-	return lipgloss.JoinVertical(lipgloss.Left, title, content, help)
+	return lipgloss.JoinVertical(lipgloss.Left, title, notes, fb, run, help)
 }
 
-func (m Model) renderLessonDetail() string {
-	if m.selectedIndex >= len(m.lessons) {
-		return "Lesson not found"
+func (m Model) renderFretboard(scale Scale) string {
+	// Text-based fretboard, lowest string first. Scale notes are marked ●
+	// and the root ◉, e.g.
+	//    0    1    2    3 ...
+	// E |--◉----------●--
+	// A |-------●-------
+
+	tuning := m.tuning
+	frets := fretboard.DefaultFrets
+	marks := scaleMarks(scale, tuning, frets)
+	root, hasRoot := scaleRoot(scale)
+
+	heading := fmt.Sprintf("Fretboard (%s):", tuning.Name)
+	shapes := scaleShapes(scale, tuning)
+	if m.shapeIndex > 0 && m.shapeIndex <= len(shapes) {
+		shape := shapes[m.shapeIndex-1]
+		lo, hi := shape.FretRange()
+		heading = fmt.Sprintf("Fretboard (%s) – %s, frets %d-%d [%d/%d]:",
+			tuning.Name, shape.Name, lo, hi, m.shapeIndex, len(shapes))
+		marks = make(map[fretboard.Location]bool, len(shape.Locations))
+		for _, loc := range shape.Locations {
+			marks[loc] = true
+		}
+		if hi > frets {
+			frets = hi
+		}
+	} else if len(shapes) > 0 {
+		heading = fmt.Sprintf("Fretboard (%s) – whole neck, %d shapes:", tuning.Name, len(shapes))
 	}
-	
-	lesson := m.lessons[m.selectedIndex]
-	title := m.styles.Title.Render(lesson.Title)
-	
-	level := m.styles.Text.Render(fmt.Sprintf("Level: %s\n", lesson.Level))
-	content := m.styles.Text.Render(lesson.Content)
-	
-	help := m.styles.Text.Render("\nPress Esc to go back")
-	
-	return lipgloss.JoinVertical(lipgloss.Left, title, level, content, help)
+
+	return m.renderNeck(heading, frets, func(loc fretboard.Location) string {
+		switch {
+		case !marks[loc]:
+			return ""
+		case hasRoot && tuning.PitchAt(loc.String, loc.Fret).PitchClass() == root:
+			return "◉"
+		default:
+			return "●"
+		}
+	})
 }
 
-func (m Model) renderFretboard(scale Scale) string {
-	// Simple text-based fretboard representation
-	// E|--0--1--2--3--4--5--
-	// B|--0--1--2--3--4--5--
-	// etc.
-	
-	fretboard := "Fretboard:\n"
-	strings := []string{"E", "A", "D", "G", "B", "e"}
-	
-	for i, str := range strings {
-		line := fmt.Sprintf("%s|", str)
-		for fret := 0; fret <= 12; fret++ {
-			// Check if this position is in the scale
-			marked := false
-			for _, pos := range scale.Positions {
-				if pos.Fret == fret && contains(pos.Strings, i) {
-					line += "--●--"
-					marked = true
-					break
-				}
-			}
-			if !marked {
+// renderNeck draws frets 0..frets of every string in the active tuning under
+// a heading. mark returns the symbol for a cell, or "" to leave it empty.
+func (m Model) renderNeck(heading string, frets int, mark func(fretboard.Location) string) string {
+	fb := heading + "\n   "
+	for fret := 0; fret <= frets; fret++ {
+		fb += fmt.Sprintf("%-5s", fmt.Sprintf("  %d", fret))
+	}
+	fb += "\n"
+
+	for i, label := range m.tuning.StringLabels() {
+		line := fmt.Sprintf("%-2s|", label)
+		for fret := 0; fret <= frets; fret++ {
+			if sym := mark(fretboard.Location{String: i, Fret: fret}); sym != "" {
+				line += "--" + sym + "--"
+			} else {
 				line += "-----"
 			}
 		}
-		fretboard += line + "\n"
+		fb += line + "\n"
 	}
-	
-	return m.styles.Text.Render(fretboard)
+
+	return m.styles.Text.Render(fb)
+}
+
+// scaleMarks returns the fretboard cells to highlight for a scale. Hand-authored
+// Positions take precedence; otherwise every fret holding a scale note is marked.
+func scaleMarks(scale Scale, tuning fretboard.Tuning, frets int) map[fretboard.Location]bool {
+	marks := make(map[fretboard.Location]bool)
+	if len(scale.Positions) > 0 {
+		for _, pos := range scale.Positions {
+			for _, str := range pos.Strings {
+				marks[fretboard.Location{String: str, Fret: pos.Fret}] = true
+			}
+		}
+		return marks
+	}
+	for _, loc := range tuning.Find(fretboard.PitchClasses(scale.Notes), frets) {
+		marks[loc] = true
+	}
+	return marks
+}
+
+// scaleShapes returns the computed playable shapes for a scale, or nil when
+// the scale uses hand-authored positions.
+func scaleShapes(scale Scale, tuning fretboard.Tuning) []fretboard.Shape {
+	if len(scale.Positions) > 0 {
+		return nil
+	}
+	return tuning.Shapes(fretboard.PitchClasses(scale.Notes))
+}
+
+// cycleShape moves to the next (or previous) shape of the selected scale,
+// wrapping through the whole-neck view.
+func (m *Model) cycleShape(forward bool) {
+	n := len(scaleShapes(m.scales[m.selectedIndex], m.tuning)) + 1
+	if forward {
+		m.shapeIndex = (m.shapeIndex + 1) % n
+	} else {
+		m.shapeIndex = (m.shapeIndex + n - 1) % n
+	}
+	obs.Event("scale_shape_selected", map[string]interface{}{
+		"index": m.shapeIndex,
+	})
+}
+
+// scaleRoot returns the pitch class of the first note of the scale.
+func scaleRoot(scale Scale) (theory.PitchClass, bool) {
+	if len(scale.Notes) == 0 {
+		return 0, false
+	}
+	n, err := theory.ParseNote(scale.Notes[0])
+	if err != nil {
+		return 0, false
+	}
+	return n.PitchClass(), true
 }
 
 func (m Model) getMaxItems() int {
 	switch m.view {
 	case "menu":
-		return 3 // Menu has 3 items
+		return len(menuItems)
 	case "scales":
 		return len(m.scales)
 	case "lessons":
 		return len(m.lessons)
+	case "chords":
+		return len(m.chords)
+	case "tunings":
+		return len(fretboard.Tunings())
+	case "today":
+		return len(m.todayQueue())
+	case "ear":
+		return len(m.earQ.Choices)
 	default:
 		return 1
 	}
 }
-
-func contains(slice []int, val int) bool {
-	for _, v := range slice {
-		if v == val {
-			return true
-		}
-	}
-	return false
-}
@@ -0,0 +1,186 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/paulgreig/guitar-training/internal/obs"
+	"github.com/paulgreig/guitar-training/internal/practice"
+)
+
+// minSessionDuration is the shortest session worth recording; anything
+// shorter is treated as an accidental visit.
+const minSessionDuration = 5 * time.Second
+
+// progressListLimit caps the rows shown per section of the progress screen.
+const progressListLimit = 8
+
+// practiceSavedMsg reports the result of persisting a session.
+type practiceSavedMsg struct {
+	err error
+}
+
+// WithStore returns a copy of the model that records practice sessions to s.
+func (m Model) WithStore(s *practice.Store) Model {
+	m.store = s
+	return m
+}
+
+// finishSession asks for a self-rating of sess before saving it, then
+// returns to the given view. Without a store, or for very short sessions,
+// it goes straight back; items opened from today's queue are rated however
+// short the session, since only a rating reschedules them.
+func (m Model) finishSession(sess practice.Session, returnTo string) Model {
+	m.view = returnTo
+	if m.store == nil || sess.Duration() < minSessionDuration && !m.fromToday {
+		return m
+	}
+	m.pending = &sess
+	m.ratingReturn = returnTo
+	m.view = "rate"
+	return m
+}
+
+// handleRateKey takes a 1-5 rating (or skips with Enter/Esc) and saves the pending session.
+func (m Model) handleRateKey(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	key := msg.String()
+	switch key {
+	case "1", "2", "3", "4", "5":
+		m.pending.Rating = int(key[0] - '0')
+		m.todayRated = m.fromToday
+	case "enter", "esc", "0":
+	default:
+		// Swallow everything else so a stray key can't leave without saving.
+		return m, nil, true
+	}
+	sess := *m.pending
+	m.pending = nil
+	m.view = m.ratingReturn
+	return m, saveSessionCmd(m.store, sess), true
+}
+
+func saveSessionCmd(store *practice.Store, sess practice.Session) tea.Cmd {
+	return func() tea.Msg {
+		err := store.Add(sess)
+		if err == nil {
+			obs.Event("practice_session_saved", map[string]interface{}{
+				"kind":    sess.Kind,
+				"item":    sess.Item,
+				"seconds": int(sess.Seconds),
+				"bpm":     sess.TempoBPM,
+				"rating":  sess.Rating,
+			})
+		}
+		return practiceSavedMsg{err: err}
+	}
+}
+
+func (m Model) renderRate() string {
+	title := m.styles.Title.Render("Session complete")
+	if m.pending == nil {
+		return title
+	}
+	s := m.pending
+	info := s.Label()
+	if s.Exercise != "" {
+		info += " – " + s.Exercise
+	}
+	info += fmt.Sprintf("\nDuration: %s", s.Duration().Round(time.Second))
+	if s.TempoBPM > 0 {
+		info += fmt.Sprintf("\nTempo reached: %d BPM", s.TempoBPM)
+	}
+	prompt := "\n\nHow did it go? 1 (struggled) – 5 (nailed it)"
+	help := m.styles.Text.Render("\nPress 1-5 to rate, Enter to skip")
+	return lipgloss.JoinVertical(lipgloss.Left, title, m.styles.Text.Render(info+prompt), help)
+}
+
+func (m Model) renderProgress() string {
+	title := m.styles.Title.Render("Progress")
+	if m.store == nil {
+		return title + "\n\nPractice log unavailable."
+	}
+	sessions := m.store.Sessions()
+	if len(sessions) == 0 {
+		return lipgloss.JoinVertical(lipgloss.Left, title,
+			m.styles.Text.Render("No practice recorded yet. Play along with a scale or read a lesson to get started."),
+			m.styles.Text.Render("\nPress Esc to go back"))
+	}
+
+	now := time.Now()
+	current, longest := practice.Streaks(sessions, now)
+	var b strings.Builder
+	fmt.Fprintf(&b, "Streak: %d day(s)   Longest: %d day(s)\n", current, longest)
+	fmt.Fprintf(&b, "Total: %s over %d session(s)\n\n", formatMinutes(practice.TotalDuration(sessions)), len(sessions))
+
+	b.WriteString("Time per item:\n")
+	for i, t := range practice.TotalsByItem(sessions) {
+		if i == progressListLimit {
+			break
+		}
+		fmt.Fprintf(&b, "  %-28s %-7s %8s\n", truncate(t.Label, 28), t.Kind, formatMinutes(t.Duration))
+	}
+
+	if history := practice.TempoHistory(sessions, now.Location()); len(history) > 0 {
+		b.WriteString("\nTempo over time:\n")
+		for i, ts := range history {
+			if i == progressListLimit {
+				break
+			}
+			bpms := make([]int, len(ts.Points))
+			for j, p := range ts.Points {
+				bpms[j] = p.BPM
+			}
+			name := ts.Label
+			if ts.Exercise != "" {
+				name += " – " + ts.Exercise
+			}
+			fmt.Fprintf(&b, "  %-36s %s %d→%d BPM\n", truncate(name, 36), sparkline(bpms),
+				bpms[0], bpms[len(bpms)-1])
+		}
+	}
+
+	help := m.styles.Text.Render("\nPress Esc to go back")
+	return lipgloss.JoinVertical(lipgloss.Left, title, m.styles.Text.Render(b.String()), help)
+}
+
+// sparkline renders values as a row of block characters scaled to their range.
+func sparkline(values []int) string {
+	const blocks = "▁▂▃▄▅▆▇█"
+	runes := []rune(blocks)
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		if v < lo {
+			lo = v
+		}
+		if v > hi {
+			hi = v
+		}
+	}
+	var b strings.Builder
+	for _, v := range values {
+		i := len(runes) - 1
+		if hi > lo {
+			i = (v - lo) * (len(runes) - 1) / (hi - lo)
+		}
+		b.WriteRune(runes[i])
+	}
+	return b.String()
+}
+
+func formatMinutes(d time.Duration) string {
+	if d < time.Minute {
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	}
+	return fmt.Sprintf("%d min", int(d.Minutes()))
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
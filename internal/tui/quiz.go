@@ -0,0 +1,256 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/paulgreig/guitar-training/internal/fretboard"
+	"github.com/paulgreig/guitar-training/internal/obs"
+	"github.com/paulgreig/guitar-training/internal/practice"
+	"github.com/paulgreig/guitar-training/internal/quiz"
+	"github.com/paulgreig/guitar-training/internal/theory"
+)
+
+// quizWeakLimit caps the weak regions listed under the quiz.
+const quizWeakLimit = 3
+
+// startQuiz opens the quiz, keeping the answer history for the current
+// tuning so earlier mistakes keep steering the questions.
+func (m Model) startQuiz() Model {
+	if m.quiz == nil || m.quiz.Tuning.ID != m.tuning.ID {
+		m.quiz = quiz.New(m.tuning, fretboard.DefaultFrets, time.Now().UnixNano())
+	}
+	m.quizAsked, m.quizCorrect = 0, 0
+	m.quizFeedback = ""
+	m.quizStart = time.Now()
+	m.view = "quiz"
+	return m.nextQuestion()
+}
+
+func (m Model) nextQuestion() Model {
+	m.quizQ = m.quiz.Next(m.quizKind, time.Now())
+	m.quizInput = ""
+	m.quizPicked = make(map[fretboard.Location]bool)
+	m.quizCursor = fretboard.Location{}
+	return m
+}
+
+// handleQuizKey handles note entry, fretboard selection and switching
+// between question kinds.
+func (m Model) handleQuizKey(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	key := msg.String()
+	switch key {
+	case "tab":
+		if m.quizKind == quiz.NameNote {
+			m.quizKind = quiz.FindAll
+		} else {
+			m.quizKind = quiz.NameNote
+		}
+		m.quizFeedback = ""
+		return m.nextQuestion(), nil, true
+	case "esc":
+		obs.Event("quiz_finished", map[string]interface{}{
+			"asked":   m.quizAsked,
+			"correct": m.quizCorrect,
+		})
+		m.cursor = 0
+		return m.finishSession(practice.Session{
+			Kind:    practice.KindQuiz,
+			Item:    "fretboard",
+			Title:   "Fretboard quiz",
+			Start:   m.quizStart,
+			Seconds: time.Since(m.quizStart).Seconds(),
+		}, "menu"), nil, true
+	}
+	if m.quizKind == quiz.FindAll {
+		return m.handleQuizFindKey(key)
+	}
+	return m.handleQuizNameKey(key)
+}
+
+// handleQuizNameKey builds up a note name: a letter in either case, then #
+// for a sharp or - for a flat. Letters always pick the letter, so b is B
+// rather than a flat; an accidental replaces any accidental typed before.
+func (m Model) handleQuizNameKey(key string) (Model, tea.Cmd, bool) {
+	switch {
+	case key == "enter":
+		if m.quizInput == "" {
+			return m, nil, true
+		}
+		n, err := theory.ParseNote(m.quizInput)
+		if err != nil {
+			m.quizInput = ""
+			return m, nil, true
+		}
+		correct := m.quiz.AnswerName(m.quizQ, n.PitchClass(), time.Now())
+		answer := m.spell(m.quizQ.Target)
+		if correct {
+			m.quizFeedback = fmt.Sprintf("✓ %s is %s", locationName(m.quizQ.Location, m.tuning), answer)
+		} else {
+			m.quizFeedback = fmt.Sprintf("✗ %s is %s, not %s", locationName(m.quizQ.Location, m.tuning), answer, n)
+		}
+		return m.scoreQuestion(correct), nil, true
+	case key == "backspace":
+		m.quizInput = ""
+	case m.quizInput != "" && key == "#":
+		m.quizInput = m.quizInput[:1] + "#"
+	case m.quizInput != "" && key == "-":
+		m.quizInput = m.quizInput[:1] + "b"
+	case len(key) == 1 && strings.Contains("abcdefgABCDEFG", key):
+		m.quizInput = strings.ToUpper(key)
+	default:
+		return m, nil, false
+	}
+	return m, nil, true
+}
+
+// handleQuizFindKey moves the fretboard cursor and toggles selections.
+func (m Model) handleQuizFindKey(key string) (Model, tea.Cmd, bool) {
+	switch key {
+	case "up", "k":
+		if m.quizCursor.String > 0 {
+			m.quizCursor.String--
+		}
+	case "down", "j":
+		if m.quizCursor.String < len(m.tuning.Strings)-1 {
+			m.quizCursor.String++
+		}
+	case "left", "h":
+		if m.quizCursor.Fret > 0 {
+			m.quizCursor.Fret--
+		}
+	case "right", "l":
+		if m.quizCursor.Fret < m.quiz.Frets {
+			m.quizCursor.Fret++
+		}
+	case " ", "x":
+		m.quizPicked[m.quizCursor] = !m.quizPicked[m.quizCursor]
+	case "enter":
+		var selected []fretboard.Location
+		for loc, on := range m.quizPicked {
+			if on {
+				selected = append(selected, loc)
+			}
+		}
+		res := m.quiz.AnswerFind(m.quizQ, selected, time.Now())
+		name := m.spell(m.quizQ.Target)
+		if res.Correct() {
+			m.quizFeedback = fmt.Sprintf("✓ found all %d %s's", res.Found, name)
+		} else {
+			var missed []string
+			for _, loc := range res.Missed {
+				missed = append(missed, locationName(loc, m.tuning))
+			}
+			m.quizFeedback = fmt.Sprintf("✗ found %d of %d %s's, %d wrong", res.Found, len(m.quizQ.Answers), name, len(res.Wrong))
+			if len(missed) > 0 {
+				m.quizFeedback += "\n  missed: " + strings.Join(missed, "; ")
+			}
+		}
+		return m.scoreQuestion(res.Correct()), nil, true
+	default:
+		return m, nil, false
+	}
+	return m, nil, true
+}
+
+func (m Model) scoreQuestion(correct bool) Model {
+	m.quizAsked++
+	if correct {
+		m.quizCorrect++
+	}
+	obs.Event("quiz_answered", map[string]interface{}{
+		"kind":    m.quizKind.String(),
+		"string":  m.quizQ.Location.String,
+		"fret":    m.quizQ.Location.Fret,
+		"correct": correct,
+		"ms":      time.Since(m.quizQ.Asked).Milliseconds(),
+	})
+	return m.nextQuestion()
+}
+
+// spell names a pitch class using the tuning's preferred accidentals.
+func (m Model) spell(pc theory.PitchClass) string {
+	return theory.SpellPitchClass(pc, m.tuning.PreferFlats).String()
+}
+
+// locationName describes a location as e.g. "A string, fret 5".
+func locationName(loc fretboard.Location, tuning fretboard.Tuning) string {
+	return fmt.Sprintf("%s string, fret %d", tuning.StringLabels()[loc.String], loc.Fret)
+}
+
+func (m Model) renderQuiz() string {
+	if m.quiz == nil {
+		return "No quiz running"
+	}
+	title := m.styles.Title.Render("Fretboard quiz – " + m.quizKind.String())
+
+	var prompt, neck string
+	q := m.quizQ
+	if q.Kind == quiz.FindAll {
+		prompt = fmt.Sprintf("Find all the %s's (%d selected)", m.spell(q.Target), countPicked(m.quizPicked))
+		neck = m.renderNeck(fmt.Sprintf("Fretboard (%s):", m.tuning.Name), m.quiz.Frets, func(loc fretboard.Location) string {
+			switch {
+			case loc == m.quizCursor && m.quizPicked[loc]:
+				return m.styles.Selected.Render("◉")
+			case loc == m.quizCursor:
+				return m.styles.Selected.Render("□")
+			case m.quizPicked[loc]:
+				return "●"
+			default:
+				return ""
+			}
+		})
+	} else {
+		input := m.quizInput
+		if input == "" {
+			input = "_"
+		}
+		prompt = fmt.Sprintf("Which note is at %s?  %s", locationName(q.Location, m.tuning), input)
+		neck = m.renderNeck(fmt.Sprintf("Fretboard (%s):", m.tuning.Name), m.quiz.Frets, func(loc fretboard.Location) string {
+			if loc == q.Location {
+				return m.styles.Selected.Render("?")
+			}
+			return ""
+		})
+	}
+
+	var b strings.Builder
+	if m.quizFeedback != "" {
+		b.WriteString(m.quizFeedback + "\n")
+	}
+	fmt.Fprintf(&b, "Score: %d/%d this session", m.quizCorrect, m.quizAsked)
+	if weak := m.quiz.Weakest(quizWeakLimit); len(weak) > 0 {
+		b.WriteString("\nWeak spots:")
+		for _, r := range weak {
+			s := m.quiz.Stats[r]
+			lo, hi := r.Frets()
+			if hi > m.quiz.Frets {
+				hi = m.quiz.Frets
+			}
+			fmt.Fprintf(&b, "\n  %s string, frets %d-%d: %.0f%% correct, %.1fs avg",
+				m.tuning.StringLabels()[r.String], lo, hi, s.Accuracy()*100, s.MeanTime().Seconds())
+		}
+	}
+
+	help := "\nType the note (a-g, then # for sharp or - for flat) and Enter; Tab to find notes instead; Esc to finish"
+	if q.Kind == quiz.FindAll {
+		help = "\nArrows or h/j/k/l move, Space selects, Enter checks; Tab to name notes instead; Esc to finish"
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, title,
+		m.styles.Text.Render(prompt+"\n"), neck,
+		m.styles.Text.Render(b.String()), m.styles.Text.Render(help))
+}
+
+func countPicked(picked map[fretboard.Location]bool) int {
+	n := 0
+	for _, on := range picked {
+		if on {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,34 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/paulgreig/guitar-training/internal/config"
+)
+
+func TestQuizNameEntry(t *testing.T) {
+	tests := []struct {
+		keys []string
+		want string
+	}{
+		{[]string{"b"}, "B"},
+		{[]string{"e", "b"}, "B"},
+		{[]string{"B", "-"}, "Bb"},
+		{[]string{"b", "-"}, "Bb"},
+		{[]string{"f", "#"}, "F#"},
+		{[]string{"f", "#", "-"}, "Fb"},
+		{[]string{"e", "-", "g"}, "G"},
+		{[]string{"#"}, ""},
+		{[]string{"-"}, ""},
+		{[]string{"a", "backspace"}, ""},
+	}
+	for _, tt := range tests {
+		m := NewModel(config.Default()).startQuiz()
+		for _, key := range tt.keys {
+			m, _, _ = m.handleQuizNameKey(key)
+		}
+		if m.quizInput != tt.want {
+			t.Errorf("keys %q: input = %q, want %q", tt.keys, m.quizInput, tt.want)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/paulgreig/guitar-training/internal/markdown"
+	"github.com/paulgreig/guitar-training/internal/obs"
+	"github.com/paulgreig/guitar-training/internal/practice"
+)
+
+// defaultHeight is the screen height used before the terminal size is known.
+const defaultHeight = 24
+
+// readerFooterLines is the height of the reader's status and help lines.
+const readerFooterLines = 3
+
+// openLesson shows lesson i at the position it was last read from.
+func (m Model) openLesson(i int) Model {
+	m.view = "lesson-detail"
+	m.selectedIndex = i
+	m.lessonStart = time.Now()
+	m.lessonTOC = false
+	m.lessonScroll = m.lessonPos[m.lessons[i].ID]
+	return m.scrollLesson(m.lessonScroll)
+}
+
+// readerLayout is a lesson laid out for the screen. The model keeps the last
+// one, so scrolling and redrawing don't lay the lesson out again on every
+// key press.
+type readerLayout struct {
+	id       string
+	width    int
+	tuning   string // widgets are drawn for the tuning
+	lines    []string
+	headings []markdown.Heading
+}
+
+// lessonLayout is the selected lesson's content as screen lines, with its
+// headings for the table of contents.
+func (m Model) lessonLayout() ([]string, []markdown.Heading) {
+	lesson, width := m.lessons[m.selectedIndex], m.contentWidth()
+	if c := m.layout; c != nil && c.lines != nil && c.id == lesson.ID && c.width == width && c.tuning == m.tuning.ID {
+		return c.lines, c.headings
+	}
+	lines, headings := m.lessonRenderer(width).Layout(lesson.Content)
+	if m.layout != nil {
+		*m.layout = readerLayout{id: lesson.ID, width: width, tuning: m.tuning.ID, lines: lines, headings: headings}
+	}
+	return lines, headings
+}
+
+// clearLayout drops the kept layout, for when the lessons or the chords the
+// widgets draw have changed, or the screen has.
+func (m Model) clearLayout() Model {
+	m.layout = &readerLayout{}
+	return m
+}
+
+// lessonHeader is the title and details shown above the lesson text.
+func (m Model) lessonHeader() string {
+	lesson := m.lessons[m.selectedIndex]
+	info := fmt.Sprintf("Level: %s", lesson.Level)
+	if len(lesson.Tags) > 0 {
+		info += "   Tags: " + strings.Join(lesson.Tags, ", ")
+	}
+	if len(lesson.Prerequisites) > 0 {
+		info += "\nBefore this: " + strings.Join(m.lessonTitles(lesson.Prerequisites), ", ")
+	}
+	return lipgloss.JoinVertical(lipgloss.Left,
+		m.styles.Title.Render(lesson.Title),
+		m.styles.Text.Render(info+"\n"))
+}
+
+// readerRows is how many lines of lesson text fit on screen.
+func (m Model) readerRows() int {
+	height := m.height
+	if height <= 0 {
+		height = defaultHeight
+	}
+	rows := height - lipgloss.Height(m.lessonHeader()) - readerFooterLines
+	if bar := m.statusBar(); bar != "" {
+		rows -= lipgloss.Height(bar)
+	}
+	return max(rows, 3)
+}
+
+// scrollLesson moves the reader so line top is at the top of the screen,
+// keeping a full screen of text in view, and remembers the position.
+func (m Model) scrollLesson(top int) Model {
+	lines, _ := m.lessonLayout()
+	m.lessonScroll = max(min(top, len(lines)-m.readerRows()), 0)
+	m.lessonPos[m.lessons[m.selectedIndex].ID] = m.lessonScroll
+	return m
+}
+
+// currentSection is the index of the heading at or above the top line, or -1.
+func currentSection(headings []markdown.Heading, top int) int {
+	section := -1
+	for i, h := range headings {
+		if h.Line <= top {
+			section = i
+		}
+	}
+	return section
+}
+
+// handleLessonDetailKey scrolls the reader and opens the table of contents,
+// and records the reading session when leaving a lesson.
+func (m Model) handleLessonDetailKey(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	if m.selectedIndex >= len(m.lessons) {
+		return m, nil, false
+	}
+	if m.lessonTOC {
+		return m.handleTOCKey(msg)
+	}
+	rows := m.readerRows()
+	switch msg.String() {
+	case "up", "k":
+		m = m.scrollLesson(m.lessonScroll - 1)
+	case "down", "j":
+		m = m.scrollLesson(m.lessonScroll + 1)
+	case "pgdown", " ", "f":
+		m = m.scrollLesson(m.lessonScroll + rows - 1)
+	case "pgup", "b":
+		m = m.scrollLesson(m.lessonScroll - rows + 1)
+	case "g", "home":
+		m = m.scrollLesson(0)
+	case "G", "end":
+		lines, _ := m.lessonLayout()
+		m = m.scrollLesson(len(lines))
+	case "t":
+		_, headings := m.lessonLayout()
+		if len(headings) > 0 {
+			m.lessonTOC = true
+			m.tocCursor = max(currentSection(headings, m.lessonScroll), 0)
+		}
+	case "esc":
+		lesson := m.lessons[m.selectedIndex]
+		obs.Event("lesson_closed", map[string]interface{}{
+			"id":       lesson.ID,
+			"position": m.lessonScroll,
+		})
+		m.cursor = m.selectedIndex
+		if m.fromToday {
+			m.cursor = 0
+		}
+		return m.finishSession(practice.Session{
+			Kind:    practice.KindLesson,
+			Item:    lesson.ID,
+			Title:   lesson.Title,
+			Start:   m.lessonStart,
+			Seconds: time.Since(m.lessonStart).Seconds(),
+		}, m.returnView("lessons")), nil, true
+	default:
+		return m, nil, false
+	}
+	return m, nil, true
+}
+
+// handleTOCKey moves through the table of contents and jumps to a section.
+func (m Model) handleTOCKey(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	_, headings := m.lessonLayout()
+	switch msg.String() {
+	case "up", "k":
+		if m.tocCursor > 0 {
+			m.tocCursor--
+		}
+	case "down", "j":
+		if m.tocCursor < len(headings)-1 {
+			m.tocCursor++
+		}
+	case "enter":
+		if m.tocCursor < len(headings) {
+			m = m.scrollLesson(headings[m.tocCursor].Line)
+		}
+		m.lessonTOC = false
+	case "t", "esc":
+		m.lessonTOC = false
+	default:
+		return m, nil, false
+	}
+	return m, nil, true
+}
+
+func (m Model) renderLessonDetail() string {
+	if m.selectedIndex >= len(m.lessons) {
+		return "Lesson not found"
+	}
+	header := m.lessonHeader()
+	lines, headings := m.lessonLayout()
+	if m.lessonTOC {
+		return lipgloss.JoinVertical(lipgloss.Left, header, m.renderTOC(headings),
+			m.styles.Text.Render("\nUse ↑/↓ to choose a section, Enter to jump to it, t or Esc to close"))
+	}
+
+	rows := m.readerRows()
+	top := min(m.lessonScroll, len(lines))
+	end := min(top+rows, len(lines))
+	page := append([]string(nil), lines[top:end]...)
+	for len(page) < rows {
+		page = append(page, "")
+	}
+
+	status := fmt.Sprintf("Lines %d-%d of %d", min(top+1, end), end, len(lines))
+	if len(lines) > rows {
+		status += fmt.Sprintf(" (%d%%)", 100*end/len(lines))
+	}
+	if s := currentSection(headings, top); s >= 0 {
+		status += " – " + headings[s].Text
+	}
+	help := "↑/↓ scroll, PgUp/PgDn page, g/G top/bottom"
+	if len(headings) > 0 {
+		help += ", t contents"
+	}
+	footer := m.styles.Text.Render("\n" + status + "\n" + help + ", Esc to go back")
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, strings.Join(page, "\n"), footer)
+}
+
+// renderTOC lists the lesson's headings, indented by level.
+func (m Model) renderTOC(headings []markdown.Heading) string {
+	top := headings[0].Level
+	for _, h := range headings {
+		top = min(top, h.Level)
+	}
+	var list string
+	for i, h := range headings {
+		entry := strings.Repeat("  ", h.Level-top) + h.Text
+		if i == m.tocCursor {
+			list += m.styles.Selected.Render("> "+entry) + "\n"
+		} else {
+			list += m.styles.Menu.Render(entry) + "\n"
+		}
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, m.styles.Text.Render("Contents\n"), list)
+}
@@ -0,0 +1,173 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/paulgreig/guitar-training/internal/config"
+)
+
+// readerModel returns a model on a 20-line screen with two long lessons,
+// showing the lessons list. Their last section is shorter than the screen.
+func readerModel(t *testing.T) Model {
+	t.Helper()
+	var lessons []Lesson
+	for _, id := range []string{"long", "other"} {
+		var doc strings.Builder
+		for _, section := range []string{"Warm-up", "Practice", "Cool-down"} {
+			fmt.Fprintf(&doc, "## %s\n\n", section)
+			paragraphs := 15
+			if section == "Cool-down" {
+				paragraphs = 3
+			}
+			for i := 1; i <= paragraphs; i++ {
+				fmt.Fprintf(&doc, "%s line %d.\n\n", section, i)
+			}
+		}
+		lessons = append(lessons, Lesson{ID: id, Title: id, Level: "beginner", Content: doc.String()})
+	}
+	m := NewModel(config.Default())
+	next, _ := m.Update(tea.WindowSizeMsg{Width: 80, Height: 20})
+	next, _ = next.(Model).Update(LessonsLoadedMsg{Lessons: lessons})
+	m = next.(Model)
+	m.view, m.cursor = "lessons", 0
+	return m
+}
+
+func TestReaderScroll(t *testing.T) {
+	m, _ := press(readerModel(t), "enter")
+	if m.view != "lesson-detail" || m.lessonScroll != 0 {
+		t.Fatalf("opened %s at line %d", m.view, m.lessonScroll)
+	}
+	lines, _ := m.lessonLayout()
+	rows := m.readerRows()
+	last := len(lines) - rows
+	tests := []struct {
+		key  string
+		want int
+	}{
+		{"k", 0},
+		{"j", 1},
+		{"f", rows},
+		{"b", 1},
+		{"G", last},
+		{"j", last},
+		{"pgdown", last},
+		{"g", 0},
+		{"pgup", 0},
+		{"end", last},
+		{"home", 0},
+	}
+	for _, tt := range tests {
+		m, _ = press(m, tt.key)
+		if m.lessonScroll != tt.want {
+			t.Errorf("after %s the top line is %d, want %d", tt.key, m.lessonScroll, tt.want)
+		}
+	}
+	if got := strings.Count(m.renderLessonDetail(), "\n") + 1; got > 20 {
+		t.Errorf("the reader is %d lines on a 20-line screen", got)
+	}
+}
+
+func TestReaderContents(t *testing.T) {
+	m, _ := press(readerModel(t), "enter")
+	_, headings := m.lessonLayout()
+	if len(headings) != 3 {
+		t.Fatalf("got %d headings, want 3", len(headings))
+	}
+
+	// The contents open on the section being read.
+	m, _ = press(m, "t")
+	if !m.lessonTOC || m.tocCursor != 0 {
+		t.Fatalf("contents open %v at %d", m.lessonTOC, m.tocCursor)
+	}
+	m, _ = press(m, "j", "enter")
+	if m.lessonTOC || m.lessonScroll != headings[1].Line {
+		t.Errorf("jumped to line %d, want %d", m.lessonScroll, headings[1].Line)
+	}
+	if !strings.Contains(m.renderLessonDetail(), "– Practice") {
+		t.Errorf("the status doesn't name the section being read")
+	}
+	m, _ = press(m, "t")
+	if m.tocCursor != 1 {
+		t.Errorf("contents opened at %d, want 1", m.tocCursor)
+	}
+
+	// The last section starts too near the end to be at the top.
+	m, _ = press(m, "j", "j", "enter")
+	lines, _ := m.lessonLayout()
+	if want := len(lines) - m.readerRows(); m.lessonScroll != want {
+		t.Errorf("jumping to the last section went to line %d, want %d", m.lessonScroll, want)
+	}
+	m, _ = press(m, "t", "esc")
+	if m.lessonTOC || m.view != "lesson-detail" {
+		t.Errorf("esc in the contents left %s with contents %v", m.view, m.lessonTOC)
+	}
+}
+
+// TestReaderPosition checks each lesson reopens where it was left.
+func TestReaderPosition(t *testing.T) {
+	m, _ := press(readerModel(t), "enter", "j", "j", "j", "esc")
+	if m.view != "lessons" {
+		t.Fatalf("esc went to %s", m.view)
+	}
+	m, _ = press(m, "j", "enter")
+	if m.selectedIndex != 1 || m.lessonScroll != 0 {
+		t.Errorf("the other lesson opened at line %d", m.lessonScroll)
+	}
+	m, _ = press(m, "j", "esc", "k", "enter")
+	if m.selectedIndex != 0 || m.lessonScroll != 3 {
+		t.Errorf("the first lesson reopened at line %d, want 3", m.lessonScroll)
+	}
+
+	// A taller screen shows more lines, so the remembered position is
+	// pulled back to keep a full screen of text.
+	m, _ = press(m, "G")
+	bottom := m.lessonScroll
+	next, _ := m.Update(tea.WindowSizeMsg{Width: 80, Height: 30})
+	m = next.(Model)
+	if m.lessonScroll != bottom-10 {
+		t.Errorf("after growing the screen the top line is %d, want %d", m.lessonScroll, bottom-10)
+	}
+}
+
+// TestReaderLayoutCache checks the layout is reused while nothing it
+// depends on changes, and redone when the lessons or the width do.
+func TestReaderLayoutCache(t *testing.T) {
+	m, _ := press(readerModel(t), "enter")
+	lines, _ := m.lessonLayout()
+	m, _ = press(m, "j", "j")
+	if again, _ := m.lessonLayout(); &again[0] != &lines[0] {
+		t.Errorf("scrolling laid the lesson out again")
+	}
+
+	next, _ := m.Update(tea.WindowSizeMsg{Width: 30, Height: 20})
+	m = next.(Model)
+	narrow, _ := m.lessonLayout()
+	if &narrow[0] == &lines[0] {
+		t.Errorf("resizing kept the old layout")
+	}
+	for _, l := range narrow {
+		if len([]rune(l)) > 30 {
+			t.Errorf("line %q is wider than the screen", l)
+		}
+	}
+
+	lessons := append([]Lesson(nil), m.lessons...)
+	lessons[0].Content = "# Rewritten\n\nShort now."
+	next, _ = m.Update(LessonsLoadedMsg{Lessons: lessons})
+	m = next.(Model)
+	if got, _ := m.lessonLayout(); len(got) != 3 || got[0] != "Rewritten" {
+		t.Errorf("after reloading the lesson is laid out as %q", got)
+	}
+	if m.lessonScroll != 0 {
+		t.Errorf("after shortening the lesson the top line is %d", m.lessonScroll)
+	}
+
+	m.selectedIndex = 1
+	if got, _ := m.lessonLayout(); got[0] != "Warm-up" {
+		t.Errorf("the other lesson is laid out as %q", got[:1])
+	}
+}
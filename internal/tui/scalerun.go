@@ -0,0 +1,299 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/paulgreig/guitar-training/internal/exercise"
+	"github.com/paulgreig/guitar-training/internal/fretboard"
+	"github.com/paulgreig/guitar-training/internal/metronome"
+	"github.com/paulgreig/guitar-training/internal/midi"
+	"github.com/paulgreig/guitar-training/internal/obs"
+	"github.com/paulgreig/guitar-training/internal/practice"
+)
+
+// defaultRunBPM is the starting tempo for scale runs, one note per beat.
+const defaultRunBPM = 60
+
+// scaleRunTickMsg advances the play-along run. gen guards against ticks
+// scheduled before the run was paused or restarted.
+type scaleRunTickMsg struct {
+	gen int
+}
+
+// runLocations picks the notes to walk: the selected shape, else the first
+// shape, else every marked cell for scales with hand-authored positions.
+func (m Model) runLocations(scale Scale) ([]fretboard.Location, string) {
+	shapes := scaleShapes(scale, m.tuning)
+	switch {
+	case m.shapeIndex > 0 && m.shapeIndex <= len(shapes):
+		s := shapes[m.shapeIndex-1]
+		return s.Locations, s.Name
+	case len(shapes) > 0:
+		return shapes[0].Locations, shapes[0].Name
+	}
+	var locs []fretboard.Location
+	for loc := range scaleMarks(scale, m.tuning, fretboard.DefaultFrets) {
+		locs = append(locs, loc)
+	}
+	return locs, "authored positions"
+}
+
+// handleScaleDetailKey handles the run settings and shape cycling on the
+// scale detail screen.
+func (m Model) handleScaleDetailKey(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	if m.selectedIndex >= len(m.scales) {
+		return m, nil, false
+	}
+	switch msg.String() {
+	case "s", "S":
+		m.cycleShape(msg.String() == "s")
+	case "r", "R":
+		m.cycleRunPattern(msg.String() == "r")
+	case "+", "=":
+		m.runBPM = clampBPM(m.runBPM + 5)
+	case "-":
+		m.runBPM = clampBPM(m.runBPM - 5)
+	case "p":
+		return m.startScaleRun()
+	case "e":
+		m, cmd := m.exportScaleWAV()
+		return m, cmd, true
+	case "a":
+		m, cmd := m.listenScale()
+		return m, cmd, true
+	case "m":
+		m, cmd := m.exportScaleMIDI()
+		return m, cmd, true
+	case "l":
+		m, cmd := m.startListening()
+		return m, cmd, true
+	case "esc":
+		if !m.fromToday {
+			return m, nil, false
+		}
+		m.cursor = 0
+		if m.todayRated {
+			m.view = "today"
+			return m, nil, true
+		}
+		// Rate even a look at the scale; unrated, it would stay due.
+		return m.finishSession(practice.Session{
+			Kind:    practice.KindScale,
+			Item:    m.scales[m.selectedIndex].Name,
+			Start:   m.todayStart,
+			Seconds: time.Since(m.todayStart).Seconds(),
+		}, "today"), nil, true
+	default:
+		return m, nil, false
+	}
+	return m, nil, true
+}
+
+// handleScaleRunKey handles the play-along screen: pause, tempo, pattern and leaving.
+func (m Model) handleScaleRunKey(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	switch msg.String() {
+	case " ":
+		m.runPlaying = !m.runPlaying
+		m.runGen++
+		if m.runPlaying {
+			m.runJudge.Retime(time.Now(), m.beat())
+			return m, m.scheduleScaleRunTick(), true
+		}
+		return m, nil, true
+	case "+", "=":
+		m.runBPM = clampBPM(m.runBPM + 5)
+		if m.runBPM > m.runMaxBPM {
+			m.runMaxBPM = m.runBPM
+		}
+	case "-":
+		m.runBPM = clampBPM(m.runBPM - 5)
+	case "r", "R":
+		m.cycleRunPattern(msg.String() == "r")
+		return m.startScaleRun()
+	case "esc":
+		m.runPlaying = false
+		m.runGen++
+		obs.Event("scale_run_stopped", map[string]interface{}{
+			"step": m.runStep,
+			"laps": m.runLaps,
+		})
+		scale := m.scales[m.selectedIndex]
+		sess := practice.Session{
+			Kind:     practice.KindScale,
+			Item:     scale.Name,
+			Exercise: exercise.Patterns[m.runPattern].Name,
+			Start:    m.runStart,
+			Seconds:  time.Since(m.runStart).Seconds(),
+			TempoBPM: m.runMaxBPM,
+		}
+		if m.midiIn != nil && m.runJudge.Score.Judged() > 0 {
+			sess.Score = m.runJudge.Score.Percent()
+		}
+		return m.finishSession(sess, "scale-detail"), nil, true
+	default:
+		return m, nil, false
+	}
+	// Tempo changes restart the current note.
+	m.runGen++
+	m.runJudge.Retime(time.Now(), m.beat())
+	if m.runPlaying {
+		return m, m.scheduleScaleRunTick(), true
+	}
+	return m, nil, true
+}
+
+func (m *Model) cycleRunPattern(forward bool) {
+	n := len(exercise.Patterns)
+	if forward {
+		m.runPattern = (m.runPattern + 1) % n
+	} else {
+		m.runPattern = (m.runPattern + n - 1) % n
+	}
+}
+
+func clampBPM(bpm int) int {
+	if bpm < metronome.MinBPM {
+		return metronome.MinBPM
+	}
+	if bpm > metronome.MaxBPM {
+		return metronome.MaxBPM
+	}
+	return bpm
+}
+
+// startScaleRun builds the run for the selected scale and starts playing it.
+func (m Model) startScaleRun() (Model, tea.Cmd, bool) {
+	scale := m.scales[m.selectedIndex]
+	locs, source := m.runLocations(scale)
+	pattern := exercise.Patterns[m.runPattern]
+	m.run = exercise.Run(m.tuning, locs, pattern)
+	if len(m.run) == 0 {
+		return m, nil, true
+	}
+	m.runStep, m.runLaps = 0, 0
+	m.runStart, m.runMaxBPM = time.Now(), m.runBPM
+	m.runPlaying = true
+	m.runGen++
+	m.runJudge = exercise.NewJudge(m.run)
+	m.runJudge.Advance(0, m.runStart, m.beat())
+	m.view = "scale-run"
+	obs.Event("scale_run_started", map[string]interface{}{
+		"scale":   scale.Name,
+		"pattern": pattern.Name,
+		"source":  source,
+		"bpm":     m.runBPM,
+		"notes":   len(m.run),
+	})
+	return m, tea.Batch(clickCmd(m.clicker, metronome.Accent), m.scheduleScaleRunTick()), true
+}
+
+// beat is the time between notes of the run.
+func (m Model) beat() time.Duration {
+	return time.Minute / time.Duration(m.runBPM)
+}
+
+func (m Model) scheduleScaleRunTick() tea.Cmd {
+	gen := m.runGen
+	return tea.Tick(m.beat(), func(time.Time) tea.Msg {
+		return scaleRunTickMsg{gen: gen}
+	})
+}
+
+// handleScaleRunTick moves to the next note, looping at the end of the run.
+func (m Model) handleScaleRunTick(msg scaleRunTickMsg) (Model, tea.Cmd) {
+	if msg.gen != m.runGen || !m.runPlaying || len(m.run) == 0 {
+		return m, nil
+	}
+	m.runStep++
+	level := metronome.Beat
+	if m.runStep >= len(m.run) {
+		m.runStep = 0
+		m.runLaps++
+		level = metronome.Accent
+	}
+	m.runJudge.Advance(m.runStep, time.Now(), m.beat())
+	return m, tea.Batch(clickCmd(m.clicker, level), m.scheduleScaleRunTick())
+}
+
+func (m Model) renderScaleRun() string {
+	if m.selectedIndex >= len(m.scales) || len(m.run) == 0 {
+		return "Nothing to play"
+	}
+	scale := m.scales[m.selectedIndex]
+	title := m.styles.Title.Render(scale.Name + " – play along")
+
+	cur := m.run[m.runStep]
+	next := m.run[(m.runStep+1)%len(m.run)]
+	inRun := make(map[fretboard.Location]bool, len(m.run))
+	frets := fretboard.DefaultFrets
+	for _, n := range m.run {
+		inRun[n.Location] = true
+		if n.Location.Fret > frets {
+			frets = n.Location.Fret
+		}
+	}
+
+	state := "playing"
+	if !m.runPlaying {
+		state = "paused"
+	}
+	info := m.styles.Text.Render(fmt.Sprintf("%s at %d BPM – note %d/%d, lap %d (%s)\nNow: %s   Next: %s\n",
+		exercise.Patterns[m.runPattern].Name, m.runBPM, m.runStep+1, len(m.run), m.runLaps+1, state,
+		cur.Pitch, next.Pitch))
+
+	neck := m.renderNeck(fmt.Sprintf("Fretboard (%s):", m.tuning.Name), frets, func(loc fretboard.Location) string {
+		switch {
+		case loc == cur.Location:
+			return m.styles.Selected.Render("●")
+		case loc == next.Location:
+			return "○"
+		case inRun[loc]:
+			return "·"
+		default:
+			return ""
+		}
+	})
+
+	help := m.styles.Text.Render("\nSpace pause/resume, +/- tempo, r/R pattern, Esc to stop")
+
+	if m.midiIn == nil {
+		return lipgloss.JoinVertical(lipgloss.Left, title, info, neck, help)
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, title, info, neck, m.renderRunScore(), help)
+}
+
+// handleMIDINote judges a note played on the MIDI input against the run.
+func (m Model) handleMIDINote(e midi.NoteEvent) Model {
+	if !e.On || m.view != "scale-run" || !m.runPlaying {
+		return m
+	}
+	v := m.runJudge.Play(e.Pitch, e.Time)
+	if v != exercise.Pending {
+		m.runLastPlayed = fmt.Sprintf("%s %s", v.Symbol(), e.Pitch)
+	}
+	return m
+}
+
+// renderRunScore shows the verdict of each note in the current lap and the
+// running score from MIDI input.
+func (m Model) renderRunScore() string {
+	var lap strings.Builder
+	for i, v := range m.runJudge.Verdicts {
+		if i == m.runStep && v == exercise.Pending {
+			lap.WriteString("▸")
+			continue
+		}
+		lap.WriteString(v.Symbol())
+	}
+	s := m.runJudge.Score
+	info := fmt.Sprintf("Lap: %s\nScore: %d%% – ✓ %d on time, ~ %d late, ✗ %d wrong, – %d missed",
+		lap.String(), s.Percent(), s.Correct, s.Late, s.Wrong, s.Missed)
+	if m.runLastPlayed != "" {
+		info += "\nLast played: " + m.runLastPlayed
+	}
+	return m.styles.Text.Render("\n" + info)
+}
@@ -0,0 +1,159 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/paulgreig/guitar-training/internal/obs"
+)
+
+// severity ranks notices in the status bar; the most severe is shown first.
+type severity int
+
+const (
+	severityInfo severity = iota
+	severityWarning
+	severityError
+)
+
+// notice is a message in the status bar. It stays until it is dismissed or
+// replaced by a newer notice from the same source, so a retried load or
+// export shows only its latest outcome. Info notices also clear on the next
+// key press, as there is nothing to do about them once read.
+type notice struct {
+	source   string // what raised it: a content kind, "export", "practice", ...
+	severity severity
+	text     string
+}
+
+// notify shows text in the status bar, replacing any notice from source.
+func (m Model) notify(source string, sev severity, text string) Model {
+	m = m.clearNotice(source)
+	m.notices = append(m.notices, notice{source: source, severity: sev, text: text})
+	return m
+}
+
+// clearNotice removes the notice from source, if there is one.
+func (m Model) clearNotice(source string) Model {
+	var kept []notice
+	for _, n := range m.notices {
+		if n.source != source {
+			kept = append(kept, n)
+		}
+	}
+	m.notices = kept
+	return m
+}
+
+// clearInfo removes the info notices, leaving warnings and errors.
+func (m Model) clearInfo() Model {
+	var kept []notice
+	for _, n := range m.notices {
+		if n.severity > severityInfo {
+			kept = append(kept, n)
+		}
+	}
+	m.notices = kept
+	return m
+}
+
+// currentNotice is the notice the bar shows: the newest of the most severe.
+func (m Model) currentNotice() (notice, bool) {
+	var cur notice
+	found := false
+	for _, n := range m.notices {
+		if !found || n.severity >= cur.severity {
+			cur, found = n, true
+		}
+	}
+	return cur, found
+}
+
+// noteLoad reports the outcome of loading kind: its problems, or that it
+// loaded cleanly once problems shown earlier were fixed.
+func (m Model) noteLoad(kind string, err error) Model {
+	if err == nil {
+		for _, n := range m.notices {
+			if n.source == kind && n.severity > severityInfo {
+				return m.notify(kind, severityInfo, "All "+kind+" loaded")
+			}
+		}
+		return m
+	}
+	errs := []error{err}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs = joined.Unwrap()
+	}
+	text := errs[0].Error()
+	if len(errs) > 1 {
+		text = fmt.Sprintf("%d problems loading %s: %s", len(errs), kind, errs[0])
+	}
+	return m.notify(kind, severityWarning, text)
+}
+
+// loadContent loads every kind of content, reporting each as it arrives.
+func loadContent(dirs []string) tea.Cmd {
+	return tea.Batch(loadScales(dirs), loadLessons(dirs), loadChords(dirs))
+}
+
+// handleStatusKey dismisses the shown notice and reloads the content on any
+// screen; Update clears info notices on any other key. These keys are never
+// printable, so they can't clash with a screen's own keys or typed answers.
+func (m Model) handleStatusKey(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	switch msg.String() {
+	case "ctrl+x":
+		n, ok := m.currentNotice()
+		if !ok {
+			return m, nil, false
+		}
+		obs.Event("notice_dismissed", map[string]interface{}{
+			"source": n.source,
+		})
+		return m.clearNotice(n.source), nil, true
+	case "ctrl+r":
+		obs.Event("content_reload_requested", map[string]interface{}{
+			"view": m.view,
+		})
+		return m.clearInfo(), loadContent(m.dataPaths), true
+	}
+	return m, nil, false
+}
+
+// statusBar is the line showing the current notice, how many more are
+// waiting and the keys that act on it, or "" when there are none.
+func (m Model) statusBar() string {
+	n, ok := m.currentNotice()
+	if !ok {
+		return ""
+	}
+	icon, style := "ℹ", m.styles.Info
+	switch n.severity {
+	case severityWarning:
+		icon, style = "⚠", m.styles.Warning
+	case severityError:
+		icon, style = "✖", m.styles.Error
+	}
+	keys := "ctrl+x dismiss"
+	if len(m.notices) > 1 {
+		keys = fmt.Sprintf("%d more – %s", len(m.notices)-1, keys)
+	}
+	if isContentKind(n.source) {
+		keys += ", ctrl+r reload"
+	}
+	text := icon + " " + strings.ReplaceAll(n.text, "\n", " ")
+	if m.width > 0 {
+		text = truncate(text, max(m.width-len([]rune(keys))-4, 10))
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, style.Render(text), m.styles.Text.Render("  ("+keys+")"))
+}
+
+// isContentKind reports whether source is a kind of content.
+func isContentKind(source string) bool {
+	switch source {
+	case "scales", "lessons", "chords":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,141 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/paulgreig/guitar-training/internal/config"
+)
+
+func TestCurrentNotice(t *testing.T) {
+	tests := []struct {
+		name    string
+		notices []notice
+		want    string
+	}{
+		{"none", nil, ""},
+		{"one", []notice{{"export", severityInfo, "a"}}, "a"},
+		{"most severe", []notice{
+			{"scales", severityWarning, "a"},
+			{"export", severityError, "b"},
+			{"lessons", severityInfo, "c"},
+		}, "b"},
+		{"newest of the most severe", []notice{
+			{"scales", severityWarning, "a"},
+			{"lessons", severityWarning, "b"},
+			{"chords", severityInfo, "c"},
+		}, "b"},
+	}
+	for _, tt := range tests {
+		m := NewModel(config.Default())
+		m.notices = tt.notices
+		n, ok := m.currentNotice()
+		if ok != (tt.want != "") || n.text != tt.want {
+			t.Errorf("%s: current notice is %q, %v; want %q", tt.name, n.text, ok, tt.want)
+		}
+	}
+}
+
+func TestNotifyReplacesSource(t *testing.T) {
+	m := NewModel(config.Default())
+	m = m.notify("scales", severityWarning, "bad scale")
+	m = m.notify("export", severityError, "export failed")
+	m = m.notify("scales", severityWarning, "another bad scale")
+	if len(m.notices) != 2 {
+		t.Fatalf("got %d notices, want 2", len(m.notices))
+	}
+	if n := m.notices[1]; n.source != "scales" || n.text != "another bad scale" {
+		t.Errorf("newest notice is %+v", n)
+	}
+	m = m.clearNotice("scales")
+	if len(m.notices) != 1 || m.notices[0].source != "export" {
+		t.Errorf("after clearing scales the notices are %+v", m.notices)
+	}
+}
+
+func TestNoteLoad(t *testing.T) {
+	m := NewModel(config.Default())
+	if m = m.noteLoad("scales", nil); len(m.notices) != 0 {
+		t.Errorf("a clean first load gave notices %+v", m.notices)
+	}
+	m = m.noteLoad("scales", errors.Join(errors.New("a: bad root"), errors.New("b: bad type")))
+	if n, _ := m.currentNotice(); n.severity != severityWarning || n.text != "2 problems loading scales: a: bad root" {
+		t.Errorf("two problems gave %+v", n)
+	}
+	m = m.noteLoad("scales", errors.New("a: bad root"))
+	if n, _ := m.currentNotice(); n.text != "a: bad root" {
+		t.Errorf("one problem gave %+v", n)
+	}
+	m = m.noteLoad("scales", nil)
+	if n, _ := m.currentNotice(); n.severity != severityInfo || n.text != "All scales loaded" {
+		t.Errorf("fixing the problems gave %+v", n)
+	}
+}
+
+// TestInfoClearsOnKey checks an info notice goes at the next key press while
+// warnings and errors stay until dismissed.
+func TestInfoClearsOnKey(t *testing.T) {
+	m := NewModel(config.Default())
+	m = m.notify("export", severityError, "export failed")
+	m = m.notify("scales", severityInfo, "All scales loaded")
+	m, _ = press(m, "j")
+	if len(m.notices) != 1 || m.notices[0].source != "export" {
+		t.Fatalf("after a key press the notices are %+v", m.notices)
+	}
+
+	// ctrl+x dismisses the error shown over the info notice, and the info
+	// notice, now shown, clears at the key after.
+	m = m.notify("scales", severityInfo, "All scales loaded")
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlX})
+	m = next.(Model)
+	if len(m.notices) != 1 || m.notices[0].source != "scales" {
+		t.Errorf("dismissing the error left %+v", m.notices)
+	}
+	if m, _ = press(m, "k"); len(m.notices) != 0 {
+		t.Errorf("after another key press the notices are %+v", m.notices)
+	}
+}
+
+func TestStatusBar(t *testing.T) {
+	long := strings.Repeat("x", 200)
+	tests := []struct {
+		name     string
+		width    int
+		notices  []notice
+		contains []string
+		fits     bool
+	}{
+		{"empty", 80, nil, nil, true},
+		{"content kind", 80, []notice{{"scales", severityWarning, "bad scale"}},
+			[]string{"⚠ bad scale", "(ctrl+x dismiss, ctrl+r reload)"}, true},
+		{"more waiting", 80, []notice{
+			{"export", severityError, "export failed"},
+			{"lessons", severityWarning, "bad lesson"},
+		}, []string{"✖ export failed", "(1 more – ctrl+x dismiss)"}, true},
+		{"truncated", 60, []notice{{"export", severityError, long}},
+			[]string{"…", "(ctrl+x dismiss)"}, true},
+		{"newlines", 80, []notice{{"export", severityError, "a\nb"}},
+			[]string{"✖ a b"}, true},
+		{"narrow", 10, []notice{{"export", severityError, long}},
+			[]string{"…"}, false},
+	}
+	for _, tt := range tests {
+		m := NewModel(config.Default())
+		m.width = tt.width
+		m.notices = tt.notices
+		bar := m.statusBar()
+		if tt.notices == nil && bar != "" {
+			t.Errorf("%s: bar is %q, want none", tt.name, bar)
+		}
+		for _, s := range tt.contains {
+			if !strings.Contains(bar, s) {
+				t.Errorf("%s: bar %q doesn't contain %q", tt.name, bar, s)
+			}
+		}
+		if tt.fits && len([]rune(bar)) > tt.width {
+			t.Errorf("%s: bar is %d wide, more than %d", tt.name, len([]rune(bar)), tt.width)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/paulgreig/guitar-training/internal/markdown"
+	"github.com/paulgreig/guitar-training/internal/obs"
+)
+
+// themeStyles returns the styles of a theme named in config.Themes. Unknown
+// names are logged and get the dark theme.
+func themeStyles(name string) Styles {
+	switch name {
+	case "dark", "":
+		return defaultStyles()
+	case "light":
+		return lightStyles()
+	case "plain":
+		return plainStyles()
+	}
+	obs.Warn("unknown theme %q, using dark", name)
+	return defaultStyles()
+}
+
+// lightStyles suit terminals with a light background.
+func lightStyles() Styles {
+	return Styles{
+		Title:    lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("25")).Padding(1, 2),
+		Menu:     lipgloss.NewStyle().PaddingLeft(2),
+		Selected: lipgloss.NewStyle().Foreground(lipgloss.Color("161")).Bold(true),
+		Text:     lipgloss.NewStyle().Foreground(lipgloss.Color("235")),
+		Info:     lipgloss.NewStyle().Foreground(lipgloss.Color("25")),
+		Warning:  lipgloss.NewStyle().Foreground(lipgloss.Color("130")),
+		Error:    lipgloss.NewStyle().Foreground(lipgloss.Color("160")).Bold(true),
+		InTune:   lipgloss.NewStyle().Foreground(lipgloss.Color("28")).Bold(true),
+		Close:    lipgloss.NewStyle().Foreground(lipgloss.Color("130")).Bold(true),
+		Off:      lipgloss.NewStyle().Foreground(lipgloss.Color("160")).Bold(true),
+		Lesson: markdown.Styles{
+			Headings: []lipgloss.Style{
+				lipgloss.NewStyle().Bold(true).Underline(true).Foreground(lipgloss.Color("25")),
+				lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("31")),
+				lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("235")),
+			},
+			Text:   lipgloss.NewStyle().Foreground(lipgloss.Color("235")),
+			Bold:   lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("232")),
+			Italic: lipgloss.NewStyle().Italic(true).Foreground(lipgloss.Color("235")),
+			Code:   lipgloss.NewStyle().Foreground(lipgloss.Color("130")),
+			Quote:  lipgloss.NewStyle().Italic(true).Foreground(lipgloss.Color("242")),
+			Rule:   lipgloss.NewStyle().Foreground(lipgloss.Color("248")),
+		},
+	}
+}
+
+// plainStyles use no colour, only bold, italic and underline.
+func plainStyles() Styles {
+	plain := lipgloss.NewStyle()
+	return Styles{
+		Title:    lipgloss.NewStyle().Bold(true).Padding(1, 2),
+		Menu:     lipgloss.NewStyle().PaddingLeft(2),
+		Selected: lipgloss.NewStyle().Bold(true),
+		Text:     plain,
+		Info:     plain,
+		Warning:  lipgloss.NewStyle().Bold(true),
+		Error:    lipgloss.NewStyle().Bold(true).Underline(true),
+		InTune:   lipgloss.NewStyle().Bold(true),
+		Close:    plain,
+		Off:      lipgloss.NewStyle().Underline(true),
+		Lesson: markdown.Styles{
+			Headings: []lipgloss.Style{
+				lipgloss.NewStyle().Bold(true).Underline(true),
+				lipgloss.NewStyle().Bold(true),
+			},
+			Text:   plain,
+			Bold:   lipgloss.NewStyle().Bold(true),
+			Italic: lipgloss.NewStyle().Italic(true),
+			Code:   plain,
+			Quote:  lipgloss.NewStyle().Italic(true),
+			Rule:   plain,
+		},
+	}
+}
+
+// actionKeys are the keys the screens understand for each action in
+// config.KeyActions.
+var actionKeys = map[string]tea.KeyMsg{
+	"up":      {Type: tea.KeyUp},
+	"down":    {Type: tea.KeyDown},
+	"select":  {Type: tea.KeyEnter},
+	"back":    {Type: tea.KeyEsc},
+	"quit":    {Type: tea.KeyCtrlC},
+	"dismiss": {Type: tea.KeyCtrlX},
+	"reload":  {Type: tea.KeyCtrlR},
+}
+
+// keyBindings maps each configured key to the key of its action, so a key
+// bound to "up" acts as the up arrow on every screen. The built-in keys
+// keep working.
+func keyBindings(bound map[string][]string) map[string]tea.KeyMsg {
+	keys := map[string]tea.KeyMsg{}
+	for action, names := range bound {
+		k, ok := actionKeys[action]
+		if !ok {
+			obs.Warn("unknown key action %q", action)
+			continue
+		}
+		for _, name := range names {
+			keys[name] = k
+		}
+	}
+	return keys
+}
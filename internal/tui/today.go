@@ -0,0 +1,128 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/paulgreig/guitar-training/internal/obs"
+	"github.com/paulgreig/guitar-training/internal/practice"
+)
+
+// newItemsPerDay caps how many never-practised items join today's queue.
+const newItemsPerDay = 3
+
+// practiceItems lists every scale and lesson as a schedulable item.
+func (m Model) practiceItems() []practice.Item {
+	items := make([]practice.Item, 0, len(m.scales)+len(m.lessons))
+	for _, s := range m.scales {
+		items = append(items, practice.Item{Kind: practice.KindScale, ID: s.Name, Title: s.Name})
+	}
+	for _, l := range m.lessons {
+		items = append(items, practice.Item{Kind: practice.KindLesson, ID: l.ID, Title: l.Title})
+	}
+	return items
+}
+
+// todayQueue returns the items due for practice today.
+func (m Model) todayQueue() []practice.Card {
+	if m.store == nil {
+		return nil
+	}
+	now := time.Now()
+	cards := practice.Schedule(m.practiceItems(), m.store.Sessions(), now.Location())
+	return practice.DueToday(cards, now, newItemsPerDay)
+}
+
+// openTodayItem opens the queued item under the cursor in its detail view.
+func (m Model) openTodayItem() (Model, tea.Cmd) {
+	queue := m.todayQueue()
+	if m.cursor >= len(queue) {
+		return m, nil
+	}
+	card := queue[m.cursor]
+	obs.Event("today_item_opened", map[string]interface{}{
+		"kind": card.Kind,
+		"item": card.ID,
+		"new":  card.New(),
+	})
+	switch card.Kind {
+	case practice.KindScale:
+		for i, s := range m.scales {
+			if s.Name == card.ID {
+				m.cursor = i
+				m.view = "scales"
+				m, cmd := m.handleEnter()
+				return m.openedFromToday(), cmd
+			}
+		}
+	case practice.KindLesson:
+		for i, l := range m.lessons {
+			if l.ID == card.ID {
+				m.cursor = i
+				m.view = "lessons"
+				m, cmd := m.handleEnter()
+				return m.openedFromToday(), cmd
+			}
+		}
+	}
+	return m, nil
+}
+
+// openedFromToday marks the detail view as opened from today's queue, so
+// leaving it asks for a rating that takes the item off the queue.
+func (m Model) openedFromToday() Model {
+	m.fromToday = true
+	m.todayStart = time.Now()
+	m.todayRated = false
+	return m
+}
+
+// returnView is where to go after leaving a detail view: today's queue when
+// the item was opened from it, otherwise fallback.
+func (m Model) returnView(fallback string) string {
+	if m.fromToday {
+		return "today"
+	}
+	return fallback
+}
+
+func (m Model) renderToday() string {
+	title := m.styles.Title.Render("Today's practice")
+	if m.store == nil {
+		return title + "\n\nPractice log unavailable."
+	}
+	queue := m.todayQueue()
+	if len(queue) == 0 {
+		return lipgloss.JoinVertical(lipgloss.Left, title,
+			m.styles.Text.Render("Nothing due today. Nice work!"),
+			m.styles.Text.Render("\nPress Esc to go back"))
+	}
+
+	now := time.Now()
+	var list string
+	for i, c := range queue {
+		status := "new"
+		if !c.New() {
+			switch d := c.DaysOverdue(now); d {
+			case 0:
+				status = "due today"
+			case 1:
+				status = "1 day overdue"
+			default:
+				status = fmt.Sprintf("%d days overdue", d)
+			}
+		}
+		line := fmt.Sprintf("%-32s %-7s %s", truncate(c.Title, 32), c.Kind, status)
+		if i == m.cursor {
+			list += m.styles.Selected.Render("> "+line) + "\n"
+		} else {
+			list += m.styles.Menu.Render("  "+line) + "\n"
+		}
+	}
+
+	help := m.styles.Text.Render("\nUse ↑/↓ to navigate, Enter to practise, Esc to go back.\nRate each session when you finish to schedule its next review.")
+
+	return lipgloss.JoinVertical(lipgloss.Left, title, list, help)
+}
@@ -0,0 +1,138 @@
+package tui
+
+import (
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/paulgreig/guitar-training/internal/config"
+	"github.com/paulgreig/guitar-training/internal/practice"
+)
+
+func keyMsg(key string) tea.KeyMsg {
+	switch key {
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		return tea.KeyMsg{Type: tea.KeyEsc}
+	case "tab":
+		return tea.KeyMsg{Type: tea.KeyTab}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+}
+
+// press sends keys to the model as the program would and returns the
+// command from the last one.
+func press(m Model, keys ...string) (Model, tea.Cmd) {
+	var cmd tea.Cmd
+	for _, key := range keys {
+		var next tea.Model
+		next, cmd = m.Update(keyMsg(key))
+		m = next.(Model)
+	}
+	return m, cmd
+}
+
+// todayModel returns a model with the built-in content and an empty
+// practice log, showing today's queue.
+func todayModel(t *testing.T) (Model, *practice.Store) {
+	t.Helper()
+	store, err := practice.Open(filepath.Join(t.TempDir(), "practice.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewModel(config.Default()).WithStore(store)
+	for _, load := range []tea.Cmd{loadScales(nil), loadLessons(nil)} {
+		next, _ := m.Update(load())
+		m = next.(Model)
+	}
+	m.view, m.cursor = "today", 0
+	return m, store
+}
+
+func queued(m Model, id string) bool {
+	for _, c := range m.todayQueue() {
+		if c.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// TestTodayScaleRated checks a scale looked at from today's queue is rated
+// on leaving, however briefly it was open, and leaves the queue once rated.
+func TestTodayScaleRated(t *testing.T) {
+	tests := []struct {
+		name   string
+		rating string
+		queued bool
+	}{
+		{"rated", "4", false},
+		{"skipped", "enter", true},
+	}
+	for _, tt := range tests {
+		m, store := todayModel(t)
+		item := m.todayQueue()[0]
+		if item.Kind != practice.KindScale {
+			t.Fatalf("first queued item is a %s", item.Kind)
+		}
+		m, _ = press(m, "enter")
+		if m.view != "scale-detail" || m.scales[m.selectedIndex].Name != item.ID {
+			t.Fatalf("%s: enter opened %s", tt.name, m.view)
+		}
+		m, _ = press(m, "esc")
+		if m.view != "rate" || m.pending == nil || m.pending.Item != item.ID {
+			t.Fatalf("%s: leaving showed %s with %+v, want a rating prompt", tt.name, m.view, m.pending)
+		}
+		m, cmd := press(m, tt.rating)
+		if m.view != "today" || cmd == nil {
+			t.Fatalf("%s: rating went to %s", tt.name, m.view)
+		}
+		if msg := cmd().(practiceSavedMsg); msg.err != nil {
+			t.Fatal(msg.err)
+		}
+		if got := len(store.Sessions()); got != 1 {
+			t.Errorf("%s: %d sessions saved, want 1", tt.name, got)
+		}
+		if queued(m, item.ID) != tt.queued {
+			t.Errorf("%s: %s still queued %v, want %v", tt.name, item.ID, !tt.queued, tt.queued)
+		}
+	}
+}
+
+// TestTodayRunRatedOnce checks a rated run is the item's result, so leaving
+// the scale afterwards doesn't ask again.
+func TestTodayRunRatedOnce(t *testing.T) {
+	m, store := todayModel(t)
+	item := m.todayQueue()[0]
+	m, _ = press(m, "enter", "p", "esc")
+	if m.view != "rate" {
+		t.Fatalf("leaving the run showed %s, want a rating prompt", m.view)
+	}
+	m, cmd := press(m, "5")
+	cmd()
+	if m.view != "scale-detail" {
+		t.Fatalf("rating went to %s, want the scale", m.view)
+	}
+	m, _ = press(m, "esc")
+	if m.view != "today" {
+		t.Errorf("leaving the scale showed %s, want today's queue", m.view)
+	}
+	if got := store.Sessions(); len(got) != 1 || got[0].Exercise == "" {
+		t.Errorf("saved %+v, want just the run", got)
+	}
+	if queued(m, item.ID) {
+		t.Errorf("%s still queued after a rated run", item.ID)
+	}
+}
+
+// TestScaleNotFromToday checks scales opened from the list aren't rated on
+// leaving.
+func TestScaleNotFromToday(t *testing.T) {
+	m, _ := todayModel(t)
+	m.view = "scales"
+	m, _ = press(m, "enter", "esc")
+	if m.view == "rate" {
+		t.Error("leaving a scale opened from the list asked for a rating")
+	}
+}
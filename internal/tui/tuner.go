@@ -0,0 +1,144 @@
+package tui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/paulgreig/guitar-training/internal/obs"
+	"github.com/paulgreig/guitar-training/internal/pitch"
+	"github.com/paulgreig/guitar-training/internal/theory"
+)
+
+// Tuner display settings.
+const (
+	needleWidth    = 51 // characters spanning -50 to +50 cents
+	inTuneCents    = 5  // within this many cents counts as in tune
+	closeCents     = 15
+	tunerSmoothing = 0.5 // weight of the newest reading in the needle position
+	tunerHold      = 20  // frames (about a second) to keep showing the last note after it fades
+)
+
+// startTuner opens the tuner and starts listening.
+func (m Model) startTuner() (Model, tea.Cmd) {
+	m.view = "tuner"
+	m.tunerNote, m.tunerQuiet = pitch.Estimate{}, tunerHold
+	return m.openAudio()
+}
+
+// updateTuner takes a new reading, smoothing the needle while the same note
+// is held so it doesn't jitter.
+func (m Model) updateTuner(est pitch.Estimate, ok bool) Model {
+	if !ok {
+		m.tunerQuiet++
+		return m
+	}
+	if m.tunerQuiet == 0 && est.Pitch == m.tunerNote.Pitch {
+		est.Cents = tunerSmoothing*est.Cents + (1-tunerSmoothing)*m.tunerNote.Cents
+	}
+	m.tunerNote, m.tunerQuiet = est, 0
+	return m
+}
+
+// handleTunerKey handles the tuner: reference pitch and leaving.
+func (m Model) handleTunerKey(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	switch msg.String() {
+	case "+", "=":
+		m.a4 = math.Min(m.a4+1, MaxA4)
+	case "-":
+		m.a4 = math.Max(m.a4-1, MinA4)
+	case "0":
+		m.a4 = theory.StandardA4
+	case "esc":
+		m.stopListening()
+		m.view, m.cursor = "menu", 0
+		return m, nil, true
+	default:
+		return m, nil, false
+	}
+	obs.Event("tuner_reference_changed", map[string]interface{}{"a4": m.a4})
+	return m, nil, true
+}
+
+func (m Model) renderTuner() string {
+	title := m.styles.Title.Render("Tuner")
+
+	note, str, guide := "–", "", "Play a string…"
+	needle := m.renderNeedle(0, false)
+	if est := m.tunerNote; m.tunerQuiet < tunerHold && est.Freq > 0 {
+		style := m.tuneStyle(est.Cents)
+		note = fmt.Sprintf("%s   %.1f Hz", style.Render(est.Pitch.String()), est.Freq)
+		s, cents := m.tuning.NearestString(est.Freq, m.a4)
+		open := m.tuning.Strings[s]
+		str = fmt.Sprintf("String: %s (%s), %+.0f¢ from open", m.tuning.StringLabels()[s], open, cents)
+		needle = m.renderNeedle(est.Cents, true)
+		switch {
+		case math.Abs(est.Cents) <= inTuneCents:
+			guide = m.styles.InTune.Render("In tune")
+		case est.Cents < 0:
+			guide = style.Render(fmt.Sprintf("%.0f¢ flat – tune up", -est.Cents))
+		default:
+			guide = style.Render(fmt.Sprintf("%.0f¢ sharp – tune down", est.Cents))
+		}
+	}
+
+	reading := lipgloss.JoinVertical(lipgloss.Left,
+		"Note: "+note,
+		str,
+		"",
+		needle,
+		"",
+		guide,
+	)
+	status := m.styles.Text.Render(fmt.Sprintf("\nA4 = %.0f Hz   Tuning: %s   Input: %s\n%s",
+		m.a4, m.tuning.Name, m.audioIn, m.listenStatus))
+	help := m.styles.Text.Render("\n+/- reference pitch, 0 resets to 440 Hz, Esc to go back")
+	return lipgloss.JoinVertical(lipgloss.Left, title, m.styles.Text.Render(reading), status, help)
+}
+
+// renderNeedle draws a cents scale from -50 to +50 with the needle at cents,
+// coloured by how close it is to the centre.
+func (m Model) renderNeedle(cents float64, show bool) string {
+	mid := needleWidth / 2
+	pos := mid + int(math.Round(cents/100*float64(needleWidth-1)))
+	if pos < 0 {
+		pos = 0
+	}
+	if pos >= needleWidth {
+		pos = needleWidth - 1
+	}
+
+	var marker, track strings.Builder
+	for i := 0; i < needleWidth; i++ {
+		switch {
+		case show && i == pos:
+			marker.WriteString(m.tuneStyle(cents).Render("▼"))
+		default:
+			marker.WriteString(" ")
+		}
+		switch {
+		case i == mid:
+			track.WriteString("┼")
+		case i%5 == 0:
+			track.WriteString("┴")
+		default:
+			track.WriteString("─")
+		}
+	}
+	labels := fmt.Sprintf("%-*s%s%*s", mid-1, "-50", " 0 ", needleWidth-mid-2, "+50")
+	return lipgloss.JoinVertical(lipgloss.Left, marker.String(), track.String(), labels)
+}
+
+// tuneStyle is the theme's style for a reading cents away from the note.
+func (m Model) tuneStyle(cents float64) lipgloss.Style {
+	switch c := math.Abs(cents); {
+	case c <= inTuneCents:
+		return m.styles.InTune
+	case c <= closeCents:
+		return m.styles.Close
+	default:
+		return m.styles.Off
+	}
+}
@@ -0,0 +1,643 @@
+package tui
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/paulgreig/guitar-training/internal/fretboard"
+	"github.com/paulgreig/guitar-training/internal/tab"
+	"github.com/paulgreig/guitar-training/internal/theory"
+)
+
+// maxFret is the highest fret content may use.
+const maxFret = 24
+
+// lessonLevels are the levels a lesson may have.
+var lessonLevels = []string{"beginner", "intermediate", "advanced"}
+
+// frontMatterKeys are the fields a Markdown lesson's front-matter may set.
+var frontMatterKeys = []string{"id", "title", "level", "tags", "prerequisites"}
+
+// Problem is a mistake in a content file. Field is the path of the value at
+// fault, such as "[2].positions[0].fret", or empty when the file as a whole
+// can't be read; Line is 0 when it isn't known.
+type Problem struct {
+	File    string
+	Line    int
+	Field   string
+	Message string
+}
+
+func (p Problem) Error() string {
+	s := p.File
+	if p.Line > 0 {
+		s += fmt.Sprintf(":%d", p.Line)
+	}
+	if p.Field != "" {
+		s += ": " + p.Field
+	}
+	return s + ": " + p.Message
+}
+
+// issue is a problem with one field of an entry; field is relative to the
+// entry and line, if set, is a line of a lesson's content.
+type issue struct {
+	field string
+	msg   string
+	line  int
+}
+
+// lintRefs is the content that checks of one file compare against: lesson
+// IDs and chord names defined anywhere, and where each lesson ID in the
+// linted directory was first defined. Loaders check files on their own and
+// pass nil, which skips these cross-checks and the checks of lesson text.
+type lintRefs struct {
+	lessons map[string]bool
+	chords  map[string]bool
+	defined map[string]string
+}
+
+// jsonFile is a content file being checked, with the line each value in it
+// starts on, by path.
+type jsonFile struct {
+	name     string
+	data     []byte
+	lines    map[string]int
+	problems []Problem
+}
+
+// report records a problem with the value at field, on the line of the
+// value or of the nearest enclosing one whose line is known.
+func (f *jsonFile) report(field, format string, args ...interface{}) {
+	line := 0
+	for path := field; ; {
+		if l, ok := f.lines[path]; ok {
+			line = l
+			break
+		}
+		i := strings.LastIndexAny(path, ".[")
+		if i < 0 {
+			break
+		}
+		path = path[:i]
+	}
+	f.problems = append(f.problems, Problem{File: f.name, Line: line, Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// sorted returns the problems found in line order.
+func (f *jsonFile) sorted() []Problem {
+	slices.SortStableFunc(f.problems, func(a, b Problem) int { return a.Line - b.Line })
+	return f.problems
+}
+
+// reportIssues records an entry's issues. Content lines are mentioned in the
+// message, as the content is a single JSON string.
+func (f *jsonFile) reportIssues(at string, issues []issue) {
+	for _, is := range issues {
+		msg := is.msg
+		if is.line > 0 {
+			msg = fmt.Sprintf("line %d of the content: %s", is.line, msg)
+		}
+		f.report(joinField(at, is.field), "%s", msg)
+	}
+}
+
+func joinField(at, field string) string {
+	switch {
+	case field == "":
+		return at
+	case strings.HasPrefix(field, "["):
+		return at + field
+	}
+	return at + "." + field
+}
+
+// decodeEntries decodes a content file's list of entries, rejecting unknown
+// fields. ok reports which entries decoded.
+func decodeEntries[T any](f *jsonFile) (entries []T, ok []bool) {
+	var raws []json.RawMessage
+	if err := json.Unmarshal(f.data, &raws); err != nil {
+		var syntax *json.SyntaxError
+		var typ *json.UnmarshalTypeError
+		switch {
+		case errors.As(err, &syntax):
+			// Offset is just past the character at fault.
+			f.problems = append(f.problems, Problem{File: f.name, Line: lineAt(f.data, max(syntax.Offset-1, 0)), Message: syntax.Error()})
+		case errors.As(err, &typ):
+			f.problems = append(f.problems, Problem{File: f.name, Line: 1, Message: "want a list of entries, got " + typ.Value})
+		default:
+			f.problems = append(f.problems, Problem{File: f.name, Message: err.Error()})
+		}
+		return nil, nil
+	}
+	f.lines = valueLines(f.data)
+
+	entries, ok = make([]T, len(raws)), make([]bool, len(raws))
+	for i, raw := range raws {
+		at := fmt.Sprintf("[%d]", i)
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.DisallowUnknownFields()
+		err := dec.Decode(&entries[i])
+		var typ *json.UnmarshalTypeError
+		switch {
+		case err == nil:
+			ok[i] = true
+		case errors.As(err, &typ):
+			f.report(joinField(at, typ.Field), "want %s, got %s", typ.Type, typ.Value)
+		case strings.HasPrefix(err.Error(), "json: unknown field "):
+			name := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+			f.report(f.findField(at, name), "unknown field %q", name)
+		default:
+			f.report(at, "%v", err)
+		}
+	}
+	return entries, ok
+}
+
+// findField returns the path of the first field called name within the
+// entry at, or at itself.
+func (f *jsonFile) findField(at, name string) string {
+	best, line := at, 0
+	for path, l := range f.lines {
+		if strings.HasPrefix(path, at) && strings.HasSuffix(path, "."+name) && (line == 0 || l < line) {
+			best, line = path, l
+		}
+	}
+	return best
+}
+
+// valueLines walks a JSON document and returns the line each value starts
+// on, by path: "[1]" for the second element of the top-level list,
+// "[1].voicings[0].frets" for a field within it. An object field's line is
+// the line of its key.
+func valueLines(data []byte) map[string]int {
+	lines := map[string]int{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	var walk func(path string) bool
+	walk = func(path string) bool {
+		line := lineAt(data, dec.InputOffset())
+		tok, err := dec.Token()
+		if err != nil {
+			return false
+		}
+		lines[path] = line
+		switch tok {
+		case json.Delim('['):
+			for i := 0; dec.More(); i++ {
+				if !walk(fmt.Sprintf("%s[%d]", path, i)) {
+					return false
+				}
+			}
+			_, err = dec.Token()
+		case json.Delim('{'):
+			for dec.More() {
+				keyLine := lineAt(data, dec.InputOffset())
+				key, err := dec.Token()
+				if err != nil {
+					return false
+				}
+				field := joinField(path, key.(string))
+				if !walk(field) {
+					return false
+				}
+				lines[field] = keyLine
+			}
+			_, err = dec.Token()
+		}
+		return err == nil
+	}
+	walk("")
+	return lines
+}
+
+// lineAt returns the line of the first token at or after offset.
+func lineAt(data []byte, offset int64) int {
+	for offset < int64(len(data)) && strings.IndexByte(" \t\r\n,:", data[offset]) >= 0 {
+		offset++
+	}
+	return bytes.Count(data[:min(offset, int64(len(data)))], []byte("\n")) + 1
+}
+
+// checkScales checks a scales file, returning the scales that can be used
+// (resolved, as by resolveScale) and the problems found.
+func checkScales(name string, data []byte) ([]Scale, []Problem) {
+	f := &jsonFile{name: name, data: data}
+	scales, ok := decodeEntries[Scale](f)
+	var valid []Scale
+	first := map[string]int{}
+	for i := range scales {
+		if !ok[i] {
+			continue
+		}
+		at := fmt.Sprintf("[%d]", i)
+		issues := checkScale(&scales[i])
+		if len(issues) > 0 {
+			f.reportIssues(at, issues)
+			continue
+		}
+		if j, dup := first[scales[i].Name]; dup {
+			f.report(at+".name", "scale %q is already defined at [%d]", scales[i].Name, j)
+		} else {
+			first[scales[i].Name] = i
+		}
+		valid = append(valid, scales[i])
+	}
+	return valid, f.sorted()
+}
+
+// checkScale resolves a scale and checks it, including that its positions
+// hold its notes in standard tuning.
+func checkScale(s *Scale) []issue {
+	var issues []issue
+	for i, n := range s.Notes {
+		if _, err := theory.ParseNote(n); err != nil {
+			issues = append(issues, issue{field: fmt.Sprintf("notes[%d]", i), msg: err.Error()})
+		}
+	}
+	switch {
+	case s.Root == "" && s.Type == "":
+		if len(s.Notes) == 0 {
+			issues = append(issues, issue{field: "notes", msg: "list at least one note, or give root and type"})
+		}
+	case s.Root == "":
+		issues = append(issues, issue{field: "root", msg: "required with type"})
+	case s.Type == "":
+		issues = append(issues, issue{field: "type", msg: "required with root"})
+	default:
+		if _, err := theory.ParseNote(s.Root); err != nil {
+			issues = append(issues, issue{field: "root", msg: err.Error()})
+		} else if err := resolveScale(s); err != nil {
+			var mismatch notesMismatchError
+			switch {
+			case !errors.As(err, &mismatch):
+				issues = append(issues, issue{field: "type", msg: err.Error()})
+			case len(issues) == 0: // notes that aren't notes are reported already
+				issues = append(issues, issue{field: "notes", msg: err.Error()})
+			}
+		}
+	}
+	if s.Name == "" {
+		issues = append(issues, issue{field: "name", msg: "required"})
+	}
+	if len(issues) > 0 {
+		return issues
+	}
+
+	notes := fretboard.PitchClasses(s.Notes)
+	tuning := fretboard.Standard
+	for i, pos := range s.Positions {
+		at := fmt.Sprintf("positions[%d]", i)
+		if pos.Fret < 0 || pos.Fret > maxFret {
+			issues = append(issues, issue{field: at + ".fret", msg: fmt.Sprintf("fret %d is out of range 0-%d", pos.Fret, maxFret)})
+			continue
+		}
+		if len(pos.Strings) == 0 {
+			issues = append(issues, issue{field: at + ".strings", msg: "list at least one string"})
+		}
+		for j, str := range pos.Strings {
+			field := fmt.Sprintf("%s.strings[%d]", at, j)
+			if str < 0 || str >= len(tuning.Strings) {
+				issues = append(issues, issue{field: field, msg: fmt.Sprintf("string %d is out of range 0-%d (0 is the lowest string)", str, len(tuning.Strings)-1)})
+				continue
+			}
+			if pc := tuning.PitchAt(str, pos.Fret).PitchClass(); !slices.Contains(notes, pc) {
+				issues = append(issues, issue{field: field, msg: fmt.Sprintf("string %d fret %d is %s in %s tuning, which isn't in %s",
+					str, pos.Fret, pc, tuning.Name, s.Name)})
+			}
+		}
+	}
+	return issues
+}
+
+// checkChords checks a chords file, returning the chords that can be used
+// (named, as by resolveChord) and the problems found.
+func checkChords(name string, data []byte) ([]Chord, []Problem) {
+	f := &jsonFile{name: name, data: data}
+	chords, ok := decodeEntries[Chord](f)
+	var valid []Chord
+	first := map[string]int{}
+	for i := range chords {
+		if !ok[i] {
+			continue
+		}
+		at := fmt.Sprintf("[%d]", i)
+		issues := checkChord(&chords[i])
+		if len(issues) > 0 {
+			f.reportIssues(at, issues)
+			continue
+		}
+		if j, dup := first[chords[i].Name]; dup {
+			f.report(at+".name", "chord %q is already defined at [%d]", chords[i].Name, j)
+		} else {
+			first[chords[i].Name] = i
+		}
+		valid = append(valid, chords[i])
+	}
+	return valid, f.sorted()
+}
+
+// checkChord names a chord and checks it, including that each voicing plays
+// only the chord's notes.
+func checkChord(c *Chord) []issue {
+	var issues []issue
+	if _, err := theory.ParseNote(c.Root); err != nil {
+		issues = append(issues, issue{field: "root", msg: err.Error()})
+	}
+	if _, ok := theory.LookupChordQuality(c.Quality); !ok {
+		issues = append(issues, issue{field: "quality", msg: fmt.Sprintf("unknown chord quality %q", c.Quality)})
+	}
+	if len(issues) > 0 {
+		return issues
+	}
+	if err := resolveChord(c); err != nil {
+		return []issue{{msg: err.Error()}}
+	}
+
+	var tones []theory.PitchClass
+	var names []string
+	for _, n := range chordNotes(*c) {
+		tones = append(tones, n.PitchClass())
+		names = append(names, n.String())
+	}
+	for i, v := range c.Voicings {
+		at := fmt.Sprintf("voicings[%d]", i)
+		id := v.Tuning
+		if id == "" {
+			id = fretboard.Standard.ID
+		}
+		tuning, ok := fretboard.LookupTuning(id)
+		if !ok {
+			issues = append(issues, issue{field: at + ".tuning", msg: fmt.Sprintf("unknown tuning %q", v.Tuning)})
+			continue
+		}
+		if len(v.Frets) != len(tuning.Strings) {
+			issues = append(issues, issue{field: at + ".frets", msg: fmt.Sprintf("has %d frets, want one per string (%d in %s tuning)",
+				len(v.Frets), len(tuning.Strings), tuning.Name)})
+			continue
+		}
+		if len(v.Fingers) > 0 && len(v.Fingers) != len(v.Frets) {
+			issues = append(issues, issue{field: at + ".fingers", msg: fmt.Sprintf("has %d fingers, want one per string (%d)", len(v.Fingers), len(v.Frets))})
+		}
+		for j, finger := range v.Fingers {
+			if finger < 0 || finger > 4 {
+				issues = append(issues, issue{field: fmt.Sprintf("%s.fingers[%d]", at, j), msg: fmt.Sprintf("finger %d is out of range 0-4", finger)})
+			}
+		}
+		played := 0
+		for str, fret := range v.Frets {
+			field := fmt.Sprintf("%s.frets[%d]", at, str)
+			switch {
+			case fret < -1 || fret > maxFret:
+				issues = append(issues, issue{field: field, msg: fmt.Sprintf("fret %d is out of range -1-%d (-1 mutes the string)", fret, maxFret)})
+			case fret >= 0:
+				played++
+				if pc := tuning.PitchAt(str, fret).PitchClass(); !slices.Contains(tones, pc) {
+					issues = append(issues, issue{field: field, msg: fmt.Sprintf("string %d fret %d is %s, which isn't in %s (%s)",
+						str, fret, pc, c.Name, strings.Join(names, " "))})
+				}
+			}
+		}
+		if played == 0 {
+			issues = append(issues, issue{field: at + ".frets", msg: "every string is muted"})
+		}
+	}
+	return issues
+}
+
+// checkLessons checks a lessons file, returning the lessons that can be used
+// and the problems found.
+func checkLessons(name string, data []byte, refs *lintRefs) ([]Lesson, []Problem) {
+	f := &jsonFile{name: name, data: data}
+	lessons, ok := decodeEntries[Lesson](f)
+	var valid []Lesson
+	for i := range lessons {
+		if !ok[i] {
+			continue
+		}
+		at := fmt.Sprintf("[%d]", i)
+		issues := checkLesson(lessons[i], refs)
+		if refs != nil {
+			where := fmt.Sprintf("%s:%d", name, f.lines[at])
+			if prev, dup := refs.defined[lessons[i].ID]; dup && lessons[i].ID != "" {
+				issues = append(issues, issue{field: "id", msg: fmt.Sprintf("lesson %s is already defined at %s", lessons[i].ID, prev)})
+			} else {
+				refs.defined[lessons[i].ID] = where
+			}
+		}
+		if len(issues) > 0 {
+			f.reportIssues(at, issues)
+			continue
+		}
+		valid = append(valid, lessons[i])
+	}
+	return valid, f.sorted()
+}
+
+// checkLessonFile reads and checks a Markdown lesson. ok is false when the
+// lesson can't be used.
+func checkLessonFile(name string, data []byte, refs *lintRefs) (Lesson, bool, []Problem) {
+	doc := strings.ReplaceAll(string(data), "\r\n", "\n")
+	l, fm, err := parseLessonFile(name, doc)
+	if err != nil {
+		p := Problem{File: name, Message: err.Error()}
+		if line, msg, ok := strings.Cut(p.Message, ": "); ok {
+			if _, err := fmt.Sscanf(line, "line %d", &p.Line); err == nil {
+				p.Message = msg
+			}
+		}
+		return Lesson{}, false, []Problem{p}
+	}
+
+	// Fields are found by their front-matter key; content lines follow the
+	// front-matter.
+	docLines := strings.Split(doc, "\n")
+	bodyStart := len(docLines) - strings.Count(l.Content, "\n")
+	keyLine := func(field string) int {
+		key, _, _ := strings.Cut(field, "[")
+		for i, line := range docLines[:bodyStart-1] {
+			if k, _, ok := strings.Cut(line, ":"); ok && strings.EqualFold(strings.TrimSpace(k), key) {
+				return i + 1
+			}
+		}
+		return 0
+	}
+
+	var problems []Problem
+	for key := range fm {
+		if !slices.Contains(frontMatterKeys, key) {
+			problems = append(problems, Problem{File: name, Line: keyLine(key), Field: key,
+				Message: fmt.Sprintf("unknown front-matter key, want %s", strings.Join(frontMatterKeys, ", "))})
+		}
+	}
+	issues := checkLesson(l, refs)
+	if refs != nil {
+		where := fmt.Sprintf("%s:%d", name, max(keyLine("id"), 1))
+		if prev, dup := refs.defined[l.ID]; dup {
+			issues = append(issues, issue{field: "id", msg: fmt.Sprintf("lesson %s is already defined at %s", l.ID, prev)})
+		} else {
+			refs.defined[l.ID] = where
+		}
+	}
+	for _, is := range issues {
+		p := Problem{File: name, Field: is.field, Message: is.msg, Line: keyLine(is.field)}
+		if is.line > 0 {
+			p.Field, p.Line = "", bodyStart+is.line-1
+		}
+		problems = append(problems, p)
+	}
+	slices.SortStableFunc(problems, func(a, b Problem) int { return a.Line - b.Line })
+	return l, len(problems) == 0, problems
+}
+
+// checkLesson checks a lesson's fields and, when linting, its prerequisites
+// and the widgets in its content.
+func checkLesson(l Lesson, refs *lintRefs) []issue {
+	var issues []issue
+	if l.ID == "" {
+		issues = append(issues, issue{field: "id", msg: "required"})
+	}
+	if l.Title == "" {
+		issues = append(issues, issue{field: "title", msg: "required"})
+	}
+	if !slices.Contains(lessonLevels, l.Level) {
+		issues = append(issues, issue{field: "level", msg: fmt.Sprintf("unknown level %q, want %s", l.Level, strings.Join(lessonLevels, ", "))})
+	}
+	if strings.TrimSpace(l.Content) == "" {
+		issues = append(issues, issue{field: "content", msg: "required"})
+	}
+	for i, id := range l.Prerequisites {
+		field := fmt.Sprintf("prerequisites[%d]", i)
+		switch {
+		case id == l.ID:
+			issues = append(issues, issue{field: field, msg: "a lesson can't be its own prerequisite"})
+		case refs != nil && !refs.lessons[id]:
+			issues = append(issues, issue{field: field, msg: fmt.Sprintf("no lesson has ID %q", id)})
+		}
+	}
+	if refs != nil {
+		issues = append(issues, checkContent(l.Content, refs)...)
+	}
+	return issues
+}
+
+var fenceLine = regexp.MustCompile("^\\s*```")
+
+// checkContent checks the fenced blocks of a lesson's Markdown: that tab
+// parses and that scale and chord widgets name something that can be drawn.
+func checkContent(content string, refs *lintRefs) []issue {
+	var issues []issue
+	lines := strings.Split(content, "\n")
+	for i := 0; i < len(lines); i++ {
+		if !fenceLine.MatchString(lines[i]) {
+			continue
+		}
+		start := i + 1
+		info := strings.TrimSpace(strings.TrimSpace(lines[i])[3:])
+		var body []string
+		if strings.HasSuffix(info, "```") {
+			info = strings.TrimSpace(strings.TrimSuffix(info, "```"))
+		} else {
+			for i++; i < len(lines) && strings.TrimSpace(lines[i]) != "```"; i++ {
+				body = append(body, lines[i])
+			}
+			if i == len(lines) {
+				issues = append(issues, issue{field: "content", line: start, msg: "``` block is never closed"})
+			}
+		}
+
+		kind, arg, _ := strings.Cut(info, " ")
+		arg = strings.TrimSpace(arg)
+		switch strings.ToLower(kind) {
+		case "tab":
+			if _, err := tab.Parse(strings.Join(body, "\n")); err != nil {
+				issues = append(issues, issue{field: "content", line: start, msg: "tab: " + err.Error()})
+			}
+		case "scale":
+			scale, shape, err := parseScaleSpec(arg)
+			if err != nil {
+				issues = append(issues, issue{field: "content", line: start, msg: "scale: " + err.Error()})
+			} else if n := len(scaleShapes(scale, fretboard.Standard)); shape > n {
+				issues = append(issues, issue{field: "content", line: start,
+					msg: fmt.Sprintf("scale: %s has %d shapes in %s tuning", scale.Name, n, fretboard.Standard.Name)})
+			}
+		case "chord":
+			if _, _, err := theory.ParseChordSymbol(arg); err != nil && !refs.chords[arg] {
+				issues = append(issues, issue{field: "content", line: start, msg: "chord: " + err.Error()})
+			}
+		}
+	}
+	return issues
+}
+
+// Lint checks the content in dir as it would be layered over the built-in
+// content: every entry against the content schema, scale positions and
+// chord voicings against their notes, lesson prerequisites and the widgets
+// in lesson text. It returns the problems in file order, or an error if dir
+// holds no content files.
+func Lint(dir string) ([]Problem, error) {
+	if info, err := os.Stat(dir); err != nil {
+		return nil, err
+	} else if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+	src := source{name: dir, fsys: os.DirFS(dir)}
+	sources := append(contentSources(nil), src)
+
+	refs := &lintRefs{lessons: map[string]bool{}, chords: map[string]bool{}, defined: map[string]string{}}
+	lessons, _ := loadLessonsFrom(sources)
+	for _, l := range lessons {
+		refs.lessons[l.ID] = true
+	}
+	chords, _ := loadChordsFrom(sources)
+	for _, c := range chords {
+		refs.chords[c.Name] = true
+	}
+
+	var problems []Problem
+	found := false
+	for _, file := range []string{"scales.json", "chords.json", "lessons.json"} {
+		data, ok, err := src.readFile(file)
+		if err != nil {
+			problems = append(problems, Problem{File: src.path(file), Message: err.Error()})
+		}
+		if !ok {
+			continue
+		}
+		found = true
+		var more []Problem
+		switch file {
+		case "scales.json":
+			_, more = checkScales(src.path(file), data)
+		case "chords.json":
+			_, more = checkChords(src.path(file), data)
+		case "lessons.json":
+			_, more = checkLessons(src.path(file), data, refs)
+		}
+		problems = append(problems, more...)
+	}
+
+	files, _ := fs.Glob(src.fsys, "lessons/*.md")
+	sort.Strings(files)
+	for _, file := range files {
+		found = true
+		data, _, err := src.readFile(file)
+		if err != nil {
+			problems = append(problems, Problem{File: src.path(file), Message: err.Error()})
+			continue
+		}
+		_, _, more := checkLessonFile(src.path(file), data, refs)
+		problems = append(problems, more...)
+	}
+	if !found {
+		return nil, fmt.Errorf("%s has no scales.json, chords.json, lessons.json or lessons/*.md", dir)
+	}
+	return problems, nil
+}
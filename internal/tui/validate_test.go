@@ -0,0 +1,235 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// problemStrings formats problems as the lint command prints them.
+func problemStrings(problems []Problem) []string {
+	var out []string
+	for _, p := range problems {
+		out = append(out, p.Error())
+	}
+	return out
+}
+
+func TestCheckScales(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		wantNames []string
+		want      []string
+	}{
+		{"valid", `[
+  {"name": "C Major", "notes": ["C", "D", "E", "F", "G", "A", "B"]},
+  {"root": "E", "type": "dorian"}
+]`, []string{"C Major", "E Dorian"}, nil},
+		{"syntax error", "[\n  {\"name\": \"C\",}\n]", nil,
+			[]string{"scales.json:2: invalid character '}' looking for beginning of object key string"}},
+		{"not a list", `{"name": "C"}`, nil, []string{"scales.json:1: want a list of entries, got object"}},
+		{"wrong type and unknown field", `[
+  {"name": "A", "notes": "A C E"},
+  {"name": "B", "notes": ["B"], "colour": "red"}
+]`, nil, []string{
+			`scales.json:2: [0].notes: want []string, got string`,
+			`scales.json:3: [1].colour: unknown field "colour"`,
+		}},
+		{"bad notes and types", `[
+  {"name": "X", "notes": ["C", "H"]},
+  {"root": "E"},
+  {"root": "E", "type": "bebop"},
+  {"notes": []},
+  {"name": "C", "root": "C", "type": "major", "notes": ["C", "D", "Eb"]}
+]`, nil, []string{
+			`scales.json:2: [0].notes[1]: invalid note letter in "H"`,
+			`scales.json:3: [1].type: required with root`,
+			`scales.json:3: [1].name: required`,
+			`scales.json:4: [2].type: unknown scale type "bebop"`,
+			`scales.json:4: [2].name: required`,
+			`scales.json:5: [3].notes: list at least one note, or give root and type`,
+			`scales.json:5: [3].name: required`,
+			`scales.json:6: [4].notes: C D Eb doesn't match C major (C D E F G A B)`,
+		}},
+		{"bad positions", `[
+  {
+    "name": "A Minor Pentatonic",
+    "notes": ["A", "C", "D", "E", "G"],
+    "positions": [
+      {"fret": 5, "strings": [0, 6]},
+      {"fret": 25, "strings": [0]},
+      {"fret": 6, "strings": [0]},
+      {"fret": 5, "strings": []}
+    ]
+  }
+]`, nil, []string{
+			`scales.json:6: [0].positions[0].strings[1]: string 6 is out of range 0-5 (0 is the lowest string)`,
+			`scales.json:7: [0].positions[1].fret: fret 25 is out of range 0-24`,
+			`scales.json:8: [0].positions[2].strings[0]: string 0 fret 6 is A# in Standard tuning, which isn't in A Minor Pentatonic`,
+			`scales.json:9: [0].positions[3].strings: list at least one string`,
+		}},
+		// The later duplicate is kept, and replaces the first when loaded.
+		{"duplicate", `[
+  {"name": "C Major", "notes": ["C"]},
+  {"name": "C Major", "notes": ["C", "E"]}
+]`, []string{"C Major", "C Major"}, []string{`scales.json:3: [1].name: scale "C Major" is already defined at [0]`}},
+	}
+	for _, tt := range tests {
+		scales, problems := checkScales("scales.json", []byte(tt.data))
+		var names []string
+		for _, s := range scales {
+			names = append(names, s.Name)
+		}
+		if !reflect.DeepEqual(names, tt.wantNames) {
+			t.Errorf("%s: valid scales %v, want %v", tt.name, names, tt.wantNames)
+		}
+		if got := problemStrings(problems); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: problems\n%s\nwant\n%s", tt.name, strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+		}
+	}
+}
+
+func TestCheckChords(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []string
+	}{
+		{"valid", `[{"name": "C", "root": "C", "quality": "major", "voicings": [{"frets": [-1, 3, 2, 0, 1, 0]}]}]`, nil},
+		{"bad root and quality", `[
+  {"name": "H", "root": "H", "quality": "major"},
+  {"name": "C13", "root": "C", "quality": "thirteenth"}
+]`, []string{
+			`chords.json:2: [0].root: invalid note letter in "H"`,
+			`chords.json:3: [1].quality: unknown chord quality "thirteenth"`,
+		}},
+		{"bad voicings", `[
+  {
+    "name": "G",
+    "root": "G",
+    "quality": "major",
+    "voicings": [
+      {"frets": [3, 2, 0, 0, 0]},
+      {"frets": [3, 2, 0, 0, 0, 3], "tuning": "open-x"},
+      {"frets": [3, 2, 0, 1, 0, 25], "fingers": [2, 1, 0, 5, 0, 3]},
+      {"frets": [-1, -1, -1, -1, -1, -1]},
+      {"frets": [3, 2, 0, 0, 0, 3], "fingers": [2, 1]}
+    ]
+  }
+]`, []string{
+			`chords.json:7: [0].voicings[0].frets: has 5 frets, want one per string (6 in Standard tuning)`,
+			`chords.json:8: [0].voicings[1].tuning: unknown tuning "open-x"`,
+			`chords.json:9: [0].voicings[2].fingers[3]: finger 5 is out of range 0-4`,
+			`chords.json:9: [0].voicings[2].frets[3]: string 3 fret 1 is G#, which isn't in G (G B D)`,
+			`chords.json:9: [0].voicings[2].frets[5]: fret 25 is out of range -1-24 (-1 mutes the string)`,
+			`chords.json:10: [0].voicings[3].frets: every string is muted`,
+			`chords.json:11: [0].voicings[4].fingers: has 2 fingers, want one per string (6)`,
+		}},
+	}
+	for _, tt := range tests {
+		_, problems := checkChords("chords.json", []byte(tt.data))
+		if got := problemStrings(problems); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: problems\n%s\nwant\n%s", tt.name, strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+		}
+	}
+}
+
+func TestCheckLessons(t *testing.T) {
+	data := `[
+  {"id": "a", "title": "A", "level": "beginner", "content": "Hi"},
+  {"id": "", "title": "", "level": "expert", "content": " "},
+  {"id": "b", "title": "B", "level": "advanced", "content": "x", "prerequisites": ["b"]}
+]`
+	lessons, problems := checkLessons("lessons.json", []byte(data), nil)
+	if len(lessons) != 1 || lessons[0].ID != "a" {
+		t.Errorf("valid lessons %+v, want only a", lessons)
+	}
+	want := []string{
+		`lessons.json:3: [1].id: required`,
+		`lessons.json:3: [1].title: required`,
+		`lessons.json:3: [1].level: unknown level "expert", want beginner, intermediate, advanced`,
+		`lessons.json:3: [1].content: required`,
+		`lessons.json:4: [2].prerequisites[0]: a lesson can't be its own prerequisite`,
+	}
+	if got := problemStrings(problems); !reflect.DeepEqual(got, want) {
+		t.Errorf("problems\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+// writeFiles lays out a content directory from file names and contents.
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// TestLint checks the cross-file checks on a content pack: prerequisites
+// and chords defined elsewhere, duplicate IDs, and the widgets and
+// front-matter of Markdown lessons.
+func TestLint(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"chords.json": `[{"name": "Cadd2", "root": "C", "quality": "add9"}]`,
+		"lessons.json": `[
+  {"id": "pack-1", "title": "One", "level": "beginner", "content": "` + "```chord Cadd2```" + `"},
+  {"id": "pack-2", "title": "Two", "level": "beginner", "content": "Intro", "prerequisites": ["pack-1", "pack-9"]}
+]`,
+		"lessons/three.md": "---\nid: pack-3\ntitle: Three\nlevel: beginner\ncolour: red\n---\nRead this.\n\n```tab\ne|-0-|\n```\n\n```scale A bebop\n```\n\n```chord Q7\n```\n\n```scale A minor pentatonic shape 9\n```\n\n```tab\ne|-0-|\nB|-1-|\n",
+		"lessons/dup.md":   "---\nid: pack-1\ntitle: Again\nlevel: beginner\n---\nSame ID.\n",
+		"lessons/bad.md":   "---\nid: pack-4\ntitle: [unclosed\n",
+	})
+	problems, err := Lint(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lessons := filepath.Join(dir, "lessons")
+	want := []string{
+		filepath.Join(dir, "lessons.json") + `:3: [1].prerequisites[1]: no lesson has ID "pack-9"`,
+		filepath.Join(lessons, "bad.md") + ": front-matter starting on line 1 is not closed with ---",
+		filepath.Join(lessons, "dup.md") + ":2: id: lesson pack-1 is already defined at " + filepath.Join(dir, "lessons.json") + ":2",
+		filepath.Join(lessons, "three.md") + ":5: colour: unknown front-matter key, want id, title, level, tags, prerequisites",
+		filepath.Join(lessons, "three.md") + ":9: tab: a tab needs at least two strings, found 1",
+		filepath.Join(lessons, "three.md") + `:13: scale: unknown scale type "bebop"`,
+		filepath.Join(lessons, "three.md") + `:16: chord: invalid note letter in "Q"`,
+		filepath.Join(lessons, "three.md") + ":19: scale: A Minor Pentatonic has 5 shapes in Standard tuning",
+		filepath.Join(lessons, "three.md") + ":22: ``` block is never closed",
+	}
+	if got := problemStrings(problems); !reflect.DeepEqual(got, want) {
+		t.Errorf("problems\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestLintBuiltInContent(t *testing.T) {
+	problems, err := Lint(filepath.Join("..", "..", "data"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range problems {
+		t.Errorf("built-in content: %s", p)
+	}
+}
+
+func TestLintErrors(t *testing.T) {
+	empty := t.TempDir()
+	if _, err := Lint(empty); err == nil || !strings.Contains(err.Error(), "has no scales.json") {
+		t.Errorf("empty directory: got %v", err)
+	}
+	file := writeFiles(t, map[string]string{"scales.json": "[]"})
+	if _, err := Lint(filepath.Join(file, "scales.json")); err == nil || !strings.Contains(err.Error(), "is not a directory") {
+		t.Errorf("file: got %v", err)
+	}
+	if _, err := Lint(filepath.Join(empty, "missing")); err == nil {
+		t.Error("missing directory: no error")
+	}
+}
@@ -0,0 +1,200 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/paulgreig/guitar-training/internal/obs"
+)
+
+// contentPollInterval is how often the content directories are checked for
+// changes while the TUI runs.
+const contentPollInterval = time.Second
+
+// contentPolledMsg carries the stamp of the content files at the last poll.
+type contentPolledMsg struct {
+	stamp string
+}
+
+// stampContent summarises the content files in dirs by size and
+// modification time, so editing, adding or removing any of them changes the
+// stamp. Polling the stamps needs no platform file-watching support.
+func stampContent(dirs []string) string {
+	var b strings.Builder
+	for _, dir := range dirs {
+		files := []string{"scales.json", "lessons.json", "chords.json"}
+		if entries, err := os.ReadDir(filepath.Join(dir, "lessons")); err == nil {
+			for _, e := range entries {
+				files = append(files, path.Join("lessons", e.Name()))
+			}
+		}
+		for _, file := range files {
+			info, err := os.Stat(filepath.Join(dir, filepath.FromSlash(file)))
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(&b, "%s %s %d %d\n", dir, file, info.Size(), info.ModTime().UnixNano())
+		}
+	}
+	return b.String()
+}
+
+// watchContent polls the content directories once. It is re-issued after
+// every poll; the built-in content never changes, so with no directories
+// there is nothing to watch.
+func watchContent(dirs []string) tea.Cmd {
+	if len(dirs) == 0 {
+		return nil
+	}
+	return tea.Tick(contentPollInterval, func(time.Time) tea.Msg {
+		return contentPolledMsg{stamp: stampContent(dirs)}
+	})
+}
+
+// handleContentPolled reloads the content when any of its files changed.
+func (m Model) handleContentPolled(msg contentPolledMsg) (Model, tea.Cmd) {
+	if msg.stamp == m.contentStamp {
+		return m, watchContent(m.dataPaths)
+	}
+	m.contentStamp = msg.stamp
+	obs.Event("content_changed", map[string]interface{}{
+		"view": m.view,
+	})
+	return m, tea.Batch(loadContent(m.dataPaths), watchContent(m.dataPaths))
+}
+
+// setScales installs loaded scales, keeping the cursor and the open scale on
+// the same entries where they still exist. Scales with problems are left out
+// and the rest installed, but when a whole file failed to load the scales
+// already shown are kept, so a half-saved file doesn't empty the list.
+func (m Model) setScales(scales []Scale, err error) Model {
+	m = m.noteLoad("scales", err)
+	if fileFailed(err) && m.scales != nil {
+		return m
+	}
+	old := m.scales
+	m.scales = scales
+	return m.follow("scales", len(scales), func(i int) (int, bool) {
+		return reselect(old, scales, i, func(s Scale) string { return s.Name })
+	})
+}
+
+// setLessons installs loaded lessons the same way as setScales.
+func (m Model) setLessons(lessons []Lesson, err error) Model {
+	m = m.noteLoad("lessons", err)
+	if fileFailed(err) && m.lessons != nil {
+		return m
+	}
+	old := m.lessons
+	m.lessons = lessons
+	m = m.clearLayout()
+	m = m.follow("lessons", len(lessons), func(i int) (int, bool) {
+		return reselect(old, lessons, i, func(l Lesson) string { return l.ID })
+	})
+	if m.view == "lesson-detail" {
+		// The headings may have changed; rewrapping may change the length.
+		m.lessonTOC = false
+		m = m.scrollLesson(m.lessonScroll)
+	}
+	return m
+}
+
+// setChords installs loaded chords the same way as setScales, and keeps the
+// queued progression on the same chords.
+func (m Model) setChords(chords []Chord, err error) Model {
+	m = m.noteLoad("chords", err)
+	if fileFailed(err) && m.chords != nil {
+		return m
+	}
+	old := m.chords
+	m.chords = chords
+	m = m.clearLayout()
+	name := func(c Chord) string { return c.Name }
+	var progression []int
+	for _, i := range m.progression {
+		if j, ok := reselect(old, chords, i, name); ok {
+			progression = append(progression, j)
+		}
+	}
+	m.progression = progression
+	return m.follow("chords", len(chords), func(i int) (int, bool) {
+		return reselect(old, chords, i, name)
+	})
+}
+
+// follow moves the list cursor, or the selection on a detail screen, to
+// where find says its entry of kind now is. When the open entry was removed
+// the screen goes back to the list.
+func (m Model) follow(kind string, n int, find func(int) (int, bool)) Model {
+	if m.view == kind {
+		if i, ok := find(m.cursor); ok {
+			m.cursor = i
+		} else {
+			m.cursor = min(m.cursor, max(n-1, 0))
+		}
+		return m
+	}
+	if m.selectedKind() != kind {
+		return m
+	}
+	if i, ok := find(m.selectedIndex); ok {
+		m.selectedIndex = i
+		return m
+	}
+	obs.Event("content_entry_removed", map[string]interface{}{
+		"kind": kind,
+		"view": m.view,
+	})
+	cursor := min(m.selectedIndex, max(n-1, 0))
+	switch m.view {
+	case "rate":
+		// Keep the pending rating; go back to the list once it's given.
+		m.ratingReturn = kind
+		return m
+	case "scale-run":
+		m.runPlaying = false
+		m.runGen++
+	case "scale-listen":
+		m.stopListening()
+	}
+	m.view, m.cursor, m.selectedIndex = kind, cursor, cursor
+	return m
+}
+
+// selectedKind is the kind of content selectedIndex refers to on the
+// current screen, or "" when it refers to none.
+func (m Model) selectedKind() string {
+	view := m.view
+	if view == "rate" {
+		view = m.ratingReturn
+	}
+	switch view {
+	case "scale-detail", "scale-run", "scale-listen":
+		return "scales"
+	case "lesson-detail":
+		return "lessons"
+	case "chord-detail":
+		return "chords"
+	}
+	return ""
+}
+
+// reselect returns the index in list of the entry that was old[i], matched
+// by key. ok is false when it is no longer there.
+func reselect[T any](old, list []T, i int, key func(T) string) (int, bool) {
+	if i < 0 || i >= len(old) {
+		return 0, false
+	}
+	k := key(old[i])
+	for j := range list {
+		if key(list[j]) == k {
+			return j, true
+		}
+	}
+	return 0, false
+}
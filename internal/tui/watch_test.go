@@ -0,0 +1,273 @@
+package tui
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/paulgreig/guitar-training/internal/config"
+)
+
+func writeContent(t *testing.T, dir, file, body string) {
+	t.Helper()
+	path := filepath.Join(dir, filepath.FromSlash(file))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStampContent(t *testing.T) {
+	dir := t.TempDir()
+	dirs := []string{dir}
+	if s := stampContent(dirs); s != "" {
+		t.Errorf("stamp of an empty directory = %q", s)
+	}
+	if s := stampContent([]string{filepath.Join(dir, "absent")}); s != "" {
+		t.Errorf("stamp of a missing directory = %q", s)
+	}
+
+	writeContent(t, dir, "scales.json", "[]")
+	stamp := stampContent(dirs)
+	if stamp == "" || stampContent(dirs) != stamp {
+		t.Fatalf("stamp %q isn't stable", stamp)
+	}
+	later := time.Now().Add(time.Hour)
+	steps := []struct {
+		name    string
+		change  func()
+		changed bool
+	}{
+		{"edit", func() { writeContent(t, dir, "scales.json", `[{"name": "Mine", "notes": ["C"]}]`) }, true},
+		{"touch", func() { os.Chtimes(filepath.Join(dir, "scales.json"), later, later) }, true},
+		{"add lessons.json", func() { writeContent(t, dir, "lessons.json", "[]") }, true},
+		{"add a lesson file", func() { writeContent(t, dir, "lessons/bends.md", "# Bends\n") }, true},
+		{"add another file", func() { writeContent(t, dir, "notes.txt", "not content") }, false},
+		{"add a nested file", func() { writeContent(t, dir, "chords/extra.json", "[]") }, false},
+		{"delete a lesson file", func() { os.Remove(filepath.Join(dir, "lessons", "bends.md")) }, true},
+		{"delete lessons.json", func() { os.Remove(filepath.Join(dir, "lessons.json")) }, true},
+	}
+	for _, tt := range steps {
+		tt.change()
+		next := stampContent(dirs)
+		if (next != stamp) != tt.changed {
+			t.Errorf("%s: stamp changed is %v, want %v", tt.name, next != stamp, tt.changed)
+		}
+		stamp = next
+	}
+}
+
+// watchModel returns a model with dir layered over the built-in content,
+// loaded as at startup.
+func watchModel(t *testing.T, dir string) Model {
+	t.Helper()
+	cfg := config.Default()
+	cfg.DataPaths = []string{dir}
+	m := NewModel(cfg)
+	for _, load := range []tea.Cmd{loadScales(cfg.DataPaths), loadLessons(cfg.DataPaths), loadChords(cfg.DataPaths)} {
+		next, _ := m.Update(load())
+		m = next.(Model)
+	}
+	return m
+}
+
+// poll checks the content files as the running program would, and delivers
+// the reload when they changed. It reports whether they had.
+func poll(t *testing.T, m Model) (Model, bool) {
+	t.Helper()
+	stamp := stampContent(m.dataPaths)
+	changed := stamp != m.contentStamp
+	next, cmd := m.Update(contentPolledMsg{stamp: stamp})
+	m = next.(Model)
+	if cmd == nil || m.contentStamp != stamp {
+		t.Fatalf("after a poll the stamp is %q and the command %v", m.contentStamp, cmd)
+	}
+	if !changed {
+		// The command is the next poll, which waits for the tick.
+		return m, false
+	}
+	batch, ok := cmd().(tea.BatchMsg)
+	if !ok || len(batch) != 2 {
+		t.Fatalf("a change gave %v, want a reload and the next poll", batch)
+	}
+	loads, ok := batch[0]().(tea.BatchMsg)
+	if !ok {
+		t.Fatal("a change didn't reload the content")
+	}
+	for _, load := range loads {
+		next, _ := m.Update(load())
+		m = next.(Model)
+	}
+	return m, true
+}
+
+func scaleList(names ...string) []Scale {
+	scales := []Scale{}
+	for _, name := range names {
+		scales = append(scales, Scale{Name: name, Notes: []string{"C"}})
+	}
+	return scales
+}
+
+// TestFollow checks reloading keeps the cursor and the open entry on the
+// same scale, and leaves a screen whose scale was removed.
+func TestFollow(t *testing.T) {
+	tests := []struct {
+		name      string
+		view      string
+		index     int // cursor on a list, otherwise selectedIndex
+		reloaded  []string
+		wantView  string
+		wantIndex int
+	}{
+		{"list, moved", "scales", 1, []string{"X", "A", "B", "C"}, "scales", 2},
+		{"list, removed", "scales", 2, []string{"A", "B"}, "scales", 1},
+		{"list, emptied", "scales", 2, nil, "scales", 0},
+		{"detail, moved", "scale-detail", 1, []string{"B", "C"}, "scale-detail", 0},
+		{"detail, removed", "scale-detail", 1, []string{"A", "C"}, "scales", 1},
+		{"detail, removed last", "scale-detail", 2, []string{"A"}, "scales", 0},
+		{"run, removed", "scale-run", 0, []string{"B"}, "scales", 0},
+		{"other kind", "lesson-detail", 2, []string{"C"}, "lesson-detail", 2},
+	}
+	for _, tt := range tests {
+		m := NewModel(config.Default())
+		m.scales = scaleList("A", "B", "C")
+		m.view, m.runPlaying = tt.view, tt.view == "scale-run"
+		if tt.view == "scales" {
+			m.cursor = tt.index
+		} else {
+			m.selectedIndex = tt.index
+		}
+		next, _ := m.Update(ScalesLoadedMsg{Scales: scaleList(tt.reloaded...)})
+		m = next.(Model)
+		got := m.selectedIndex
+		if m.view == "scales" {
+			got = m.cursor
+		}
+		if m.view != tt.wantView || got != tt.wantIndex {
+			t.Errorf("%s: on %s at %d, want %s at %d", tt.name, m.view, got, tt.wantView, tt.wantIndex)
+		}
+		if m.view == "scales" && tt.view != "scales" && (m.selectedIndex != m.cursor || m.runPlaying) {
+			t.Errorf("%s: back on the list with selection %d, playing %v", tt.name, m.selectedIndex, m.runPlaying)
+		}
+	}
+}
+
+// TestFollowRating checks a rating pending for a removed scale is kept, and
+// the list shown once it is given.
+func TestFollowRating(t *testing.T) {
+	m := NewModel(config.Default())
+	m.scales = scaleList("A", "B")
+	m.view, m.ratingReturn, m.selectedIndex = "rate", "scale-run", 1
+	next, _ := m.Update(ScalesLoadedMsg{Scales: scaleList("A")})
+	m = next.(Model)
+	if m.view != "rate" || m.ratingReturn != "scales" {
+		t.Errorf("on %s returning to %s, want rate returning to scales", m.view, m.ratingReturn)
+	}
+}
+
+func TestFollowProgression(t *testing.T) {
+	m := NewModel(config.Default())
+	m.chords = []Chord{{Name: "C"}, {Name: "G"}, {Name: "Am"}}
+	m.progression = []int{0, 1, 2, 1}
+	next, _ := m.Update(ChordsLoadedMsg{Chords: []Chord{{Name: "Am"}, {Name: "C"}, {Name: "F"}}})
+	m = next.(Model)
+	if want := []int{1, 0}; !reflect.DeepEqual(m.progression, want) {
+		t.Errorf("progression = %v, want %v", m.progression, want)
+	}
+}
+
+// TestLoadFailureKeepsEntries checks a file that fails to load leaves the
+// entries shown, while problems with single entries don't.
+func TestLoadFailureKeepsEntries(t *testing.T) {
+	m := NewModel(config.Default())
+	m.scales = scaleList("A", "B")
+	failed := errors.Join(fileError{errors.New("could not parse scales.json")})
+	next, _ := m.Update(ScalesLoadedMsg{Scales: scaleList(), Err: failed})
+	m = next.(Model)
+	if len(m.scales) != 2 {
+		t.Errorf("a failed file left %d scales", len(m.scales))
+	}
+	if n, ok := m.currentNotice(); !ok || n.source != "scales" || n.severity != severityWarning {
+		t.Errorf("notice = %+v, %v", n, ok)
+	}
+
+	problem := errors.Join(Problem{File: "scales.json", Field: "[1].notes", Message: "bad note"})
+	next, _ = m.Update(ScalesLoadedMsg{Scales: scaleList("A"), Err: problem})
+	m = next.(Model)
+	if len(m.scales) != 1 {
+		t.Errorf("a bad entry left %d scales, want the 1 good one", len(m.scales))
+	}
+
+	// The first load installs what there is, as there is nothing to keep.
+	m = NewModel(config.Default())
+	next, _ = m.Update(ScalesLoadedMsg{Scales: scaleList("A"), Err: failed})
+	if m = next.(Model); len(m.scales) != 1 {
+		t.Errorf("the first load installed %d scales", len(m.scales))
+	}
+}
+
+// TestContentReload edits, adds and removes lesson files while one is open.
+func TestContentReload(t *testing.T) {
+	dir := t.TempDir()
+	writeContent(t, dir, "lessons/a-first.md", "# First\n\nOne.\n")
+	writeContent(t, dir, "lessons/b-second.md", "# Second\n\nTwo.\n")
+	m := watchModel(t, dir)
+	if _, changed := poll(t, m); changed {
+		t.Fatal("nothing changed but the content reloaded")
+	}
+	index := func(id string) int {
+		for i, l := range m.lessons {
+			if l.ID == id {
+				return i
+			}
+		}
+		return -1
+	}
+	m.view, m.selectedIndex = "lesson-detail", index("b-second")
+	builtin := index("a-first")
+
+	writeContent(t, dir, "lessons/b-second.md", "# Second, revised\n\nTwo and more.\n")
+	m, changed := poll(t, m)
+	if l := m.lessons[m.selectedIndex]; !changed || m.view != "lesson-detail" || l.ID != "b-second" || l.Title != "Second, revised" {
+		t.Errorf("after an edit showing %s %q on %s", l.ID, l.Title, m.view)
+	}
+
+	writeContent(t, dir, "lessons/c-third.md", "# Third\n")
+	writeContent(t, dir, "lessons.json", `[{"id": "from-json", "title": "JSON", "level": "beginner", "content": "Three."}]`)
+	os.Remove(filepath.Join(dir, "lessons", "a-first.md"))
+	m, _ = poll(t, m)
+	// lessons.json is read before the lesson files, so its lesson comes first.
+	if m.view != "lesson-detail" || m.selectedIndex != builtin+1 || m.lessons[m.selectedIndex].ID != "b-second" {
+		t.Errorf("after removing another lesson showing %d on %s", m.selectedIndex, m.view)
+	}
+	if m.lessons[builtin].ID != "from-json" || m.lessons[len(m.lessons)-1].ID != "c-third" {
+		t.Errorf("the added lessons aren't in the order they were read")
+	}
+
+	// Saving half a file keeps its lessons until it is whole again.
+	writeContent(t, dir, "lessons.json", `[{"id": `)
+	m, _ = poll(t, m)
+	if index("from-json") < 0 || m.view != "lesson-detail" {
+		t.Errorf("a broken file left %d lessons on %s", len(m.lessons), m.view)
+	}
+	if n, _ := m.currentNotice(); n.source != "lessons" {
+		t.Errorf("a broken file showed %+v", n)
+	}
+
+	os.Remove(filepath.Join(dir, "lessons.json"))
+	os.Remove(filepath.Join(dir, "lessons", "b-second.md"))
+	m, _ = poll(t, m)
+	if m.view != "lessons" || m.cursor != builtin || m.lessons[m.cursor].ID != "c-third" || index("from-json") >= 0 {
+		t.Errorf("removing the open lesson left %s at %d", m.view, m.cursor)
+	}
+	if n, _ := m.currentNotice(); n.severity != severityInfo {
+		t.Errorf("after fixing the file the notice is %+v", n)
+	}
+}